@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/husio/scrumboard/server/pubsub"
+	"github.com/husio/scrumboard/server/surf"
+)
+
+// config is the application configuration. Values are loaded, from the
+// lowest to the highest priority, from defaults, configuration file,
+// environment variables and command line flags.
+//
+// Every option can be set using a flag, an environment variable with the
+// flag name upper cased and dashes replaced with underscores (ie.
+// "redis-url" is REDIS_URL) or a configuration file key equal to the flag
+// name. Secrets can be also read from the file pointed by an environment
+// variable with _FILE suffix (ie. GITHUB_SECRET_FILE).
+type config struct {
+	Debug     bool
+	Port      int
+	Static    string
+	Templates string
+
+	// MetricsAddr is the address of a separate server exposing metrics.
+	// Metrics include board IDs, which grant access to the boards, so
+	// they must not be served publicly.
+	MetricsAddr string
+
+	// Embedded mode is using only local files and does not require any
+	// external service.
+	Embedded bool
+	DataDir  string
+
+	RedisURL    string
+	DatabaseURL string
+
+	GithubClientID string
+	GithubSecret   string
+
+	Hub              string
+	HubOverflow      string
+	BoardStore       string
+	Cache            string
+	SnapshotStore    string
+	SnapshotDir      string
+	SnapshotInterval time.Duration
+
+	BoardRetentionMonths int
+	BoardArchiveDir      string
+
+	ShutdownTimeout      time.Duration
+	ShutdownDrainDelay   time.Duration
+	ShutdownFlushTimeout time.Duration
+
+	LogFormat string
+	LogLevel  string
+	LogCaller bool
+
+	// flags is bound to all configuration options
+	flags *flag.FlagSet
+}
+
+// secretOptions are options that must never be printed. Passwords of the
+// connection URLs are redacted as well.
+var secretOptions = map[string]bool{
+	"github-secret": true,
+	"redis-url":     true,
+	"database-url":  true,
+}
+
+// configFlags returns flag set bound to given configuration.
+func configFlags(c *config) *flag.FlagSet {
+	fs := flag.NewFlagSet("scrumboard", flag.ContinueOnError)
+	fs.BoolVar(&c.Debug, "debug", false, "Run in debug mode.")
+	fs.IntVar(&c.Port, "port", 8000, "HTTP server port.")
+	fs.StringVar(&c.MetricsAddr, "metrics-addr", "localhost:9100", "Metrics server address. It must not be reachable publicly. Metrics are not served if empty.")
+	fs.StringVar(&c.Static, "static", "", "Static files directory. Files embedded in the binary are used if empty.")
+	fs.StringVar(&c.Templates, "templates", "./templates/**.tmpl", "HTML templates glob pattern.")
+	fs.BoolVar(&c.Embedded, "embedded", false, "Store all data in local files, without any external service.")
+	fs.StringVar(&c.DataDir, "data-dir", "./data", "Data directory used in embedded mode.")
+	fs.StringVar(&c.RedisURL, "redis-url", "redis://localhost:6379/2", "Redis server address.")
+	fs.StringVar(&c.DatabaseURL, "database-url", "postgres://localhost/scrumboard?sslmode=disable", "PostgreSQL database address.")
+	fs.StringVar(&c.GithubClientID, "github-client-id", "", "GitHub OAuth application client ID.")
+	fs.StringVar(&c.GithubSecret, "github-secret", "", "GitHub OAuth application client secret.")
+	fs.StringVar(&c.Hub, "hub", "memory", "Board messages hub: memory or redis. Redis is required when running more than one process.")
+	fs.StringVar(&c.HubOverflow, "hub-overflow", "coalesce", "Slow client policy: disconnect, coalesce or queue:<size>.")
+	fs.StringVar(&c.BoardStore, "board-store", "", "Board storage: redis, postgres or file. Defaults to file in embedded mode and redis otherwise.")
+	fs.StringVar(&c.Cache, "cache", "", "Cache storage: redis or file. Defaults to file in embedded mode and redis otherwise.")
+	fs.StringVar(&c.SnapshotStore, "snapshot-store", "", "Board state storage: redis, file, postgres or memory. Defaults to file in embedded mode and redis otherwise.")
+	fs.StringVar(&c.SnapshotDir, "snapshot-dir", "", "Board state directory used by file storage. Defaults to snapshots directory inside of data directory.")
+	fs.DurationVar(&c.SnapshotInterval, "snapshot-interval", 5*time.Second, "How often board states are written.")
+	fs.IntVar(&c.BoardRetentionMonths, "board-retention-months", 0, "Remove boards with no activity for given number of months. Zero disables removal.")
+	fs.StringVar(&c.BoardArchiveDir, "board-archive-dir", "", "Directory where removed boards are archived.")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", 20*time.Second, "How long to wait for clients to disconnect when shutting down.")
+	fs.DurationVar(&c.ShutdownDrainDelay, "shutdown-drain-delay", 5*time.Second, "How long to report the process as not ready before refusing new connections when shutting down, so that load balancers stop routing traffic to it.")
+	fs.DurationVar(&c.ShutdownFlushTimeout, "shutdown-flush-timeout", 10*time.Second, "How long to wait for pending board states to be written when shutting down.")
+	fs.StringVar(&c.LogFormat, "log-format", "logfmt", "Log format: logfmt or json.")
+	fs.StringVar(&c.LogLevel, "log-level", "info", "Minimal level of logged entries: debug, info or error.")
+	fs.BoolVar(&c.LogCaller, "log-caller", true, "Include source code location in log entries.")
+	return fs
+}
+
+// loadConfig returns configuration build from defaults, configuration file,
+// environment and given command line arguments. Arguments left after flags
+// are returned as well. Returned configuration is not validated.
+func loadConfig(args []string) (*config, bool, []string, error) {
+	var c config
+	fs := configFlags(&c)
+	c.flags = fs
+	configPath := fs.String("config", os.Getenv("CONFIG"), "JSON configuration file.")
+	printConfig := fs.Bool("print-config", false, "Print configuration with secrets redacted and exit.")
+	if err := fs.Parse(args); err != nil {
+		return nil, false, nil, err
+	}
+
+	// flags have the highest priority and must be applied again after
+	// all other sources
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+
+	if *configPath != "" {
+		if err := loadConfigFile(fs, *configPath); err != nil {
+			return nil, false, nil, err
+		}
+	}
+	if err := loadConfigEnv(fs); err != nil {
+		return nil, false, nil, err
+	}
+	for name, value := range flags {
+		fs.Set(name, value)
+	}
+
+	c.setDefaults()
+	return &c, *printConfig, fs.Args(), nil
+}
+
+func loadConfigFile(fs *flag.FlagSet, path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration file: %s", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fmt.Errorf("cannot decode configuration file: %s", err)
+	}
+	for name, value := range values {
+		if !isOption(fs, name) {
+			return fmt.Errorf("configuration file: unknown option %q", name)
+		}
+		if err := fs.Set(name, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("configuration file: invalid %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+func loadConfigEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || !isOption(fs, f.Name) {
+			return
+		}
+		name := envName(f.Name)
+		value := os.Getenv(name)
+		if path := os.Getenv(name + "_FILE"); path != "" && secretOptions[f.Name] {
+			raw, rerr := ioutil.ReadFile(path)
+			if rerr != nil {
+				err = fmt.Errorf("cannot read %s_FILE: %s", name, rerr)
+				return
+			}
+			value = strings.TrimSpace(string(raw))
+		}
+		if value == "" {
+			return
+		}
+		if serr := fs.Set(f.Name, value); serr != nil {
+			err = fmt.Errorf("invalid %s: %s", name, serr)
+		}
+	})
+	return err
+}
+
+// isOption returns true if flag of given name is a configuration option and
+// not a command line only flag.
+func isOption(fs *flag.FlagSet, name string) bool {
+	return fs.Lookup(name) != nil && name != "config" && name != "print-config"
+}
+
+// envName returns environment variable name of given option.
+func envName(option string) string {
+	return strings.ToUpper(strings.Replace(option, "-", "_", -1))
+}
+
+// setDefaults sets values of options that depend on other options.
+func (c *config) setDefaults() {
+	byMode := func(value *string, standard, embedded string) {
+		if *value != "" {
+			return
+		}
+		if c.Embedded {
+			*value = embedded
+		} else {
+			*value = standard
+		}
+	}
+	byMode(&c.BoardStore, "redis", "file")
+	byMode(&c.Cache, "redis", "file")
+	byMode(&c.SnapshotStore, "redis", "file")
+	if c.SnapshotDir == "" {
+		c.SnapshotDir = filepath.Join(c.DataDir, "snapshots")
+	}
+}
+
+func (c *config) validate() error {
+	var errs []string
+	invalid := func(option, format string, args ...interface{}) {
+		errs = append(errs, envName(option)+": "+fmt.Sprintf(format, args...))
+	}
+	oneOf := func(option, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		invalid(option, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+
+	if c.Port <= 0 || c.Port > 65535 {
+		invalid("port", "invalid port number %d", c.Port)
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			invalid("metrics-addr", "invalid address: %s", err)
+		}
+	}
+	oneOf("hub", c.Hub, "memory", "redis")
+	if _, err := pubsub.ParseOverflowPolicy(c.HubOverflow); err != nil {
+		invalid("hub-overflow", "%s", err)
+	}
+	oneOf("board-store", c.BoardStore, "redis", "postgres", "file")
+	oneOf("cache", c.Cache, "redis", "file")
+	oneOf("snapshot-store", c.SnapshotStore, "redis", "file", "postgres", "memory")
+	if c.SnapshotInterval <= 0 {
+		invalid("snapshot-interval", "must be greater than zero")
+	}
+	if c.ShutdownTimeout <= 0 {
+		invalid("shutdown-timeout", "must be greater than zero")
+	}
+	if c.ShutdownDrainDelay < 0 {
+		invalid("shutdown-drain-delay", "must not be negative")
+	}
+	if c.ShutdownFlushTimeout <= 0 {
+		invalid("shutdown-flush-timeout", "must be greater than zero")
+	}
+	if _, err := surf.ParseLogFormat(c.LogFormat); err != nil {
+		invalid("log-format", "%s", err)
+	}
+	if _, err := surf.ParseLogLevel(c.LogLevel); err != nil {
+		invalid("log-level", "%s", err)
+	}
+	if c.BoardRetentionMonths < 0 {
+		invalid("board-retention-months", "must not be negative")
+	}
+	if _, err := url.Parse(c.RedisURL); err != nil {
+		invalid("redis-url", "invalid URL")
+	}
+	if _, err := url.Parse(c.DatabaseURL); err != nil {
+		invalid("database-url", "invalid URL")
+	}
+
+	// secrets are optional only when developing locally
+	if !c.Debug {
+		if c.GithubClientID == "" {
+			invalid("github-client-id", "required")
+		}
+		if c.GithubSecret == "" {
+			invalid("github-secret", "required, set it directly or using GITHUB_SECRET_FILE")
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.New("invalid configuration:\n\t" + strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+// redacted returns configuration as a JSON encoded configuration file, with
+// all secrets redacted.
+func (c *config) redacted() ([]byte, error) {
+	values := make(map[string]interface{})
+	c.flags.VisitAll(func(f *flag.Flag) {
+		if !isOption(c.flags, f.Name) {
+			return
+		}
+		value := f.Value.(flag.Getter).Get()
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case string:
+			if secretOptions[f.Name] {
+				value = redact(v)
+			}
+		}
+		values[f.Name] = value
+	})
+	return json.MarshalIndent(values, "", "\t")
+}
+
+// redact returns secret value that is safe to be printed. Only password is
+// removed from URLs.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if u, err := url.Parse(secret); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+	return "<redacted>"
+}
+
+// logConfig returns logging configuration. Invalid values are replaced with
+// defaults.
+func (c *config) logConfig() surf.LogConfig {
+	format, _ := surf.ParseLogFormat(c.LogFormat)
+	level, err := surf.ParseLogLevel(c.LogLevel)
+	if err != nil {
+		level = surf.LevelInfo
+	}
+	return surf.LogConfig{
+		Format:   format,
+		MinLevel: level,
+		Caller:   c.LogCaller,
+	}
+}
+
+func (c *config) addr() string {
+	return "0.0.0.0:" + strconv.Itoa(c.Port)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed dist
+var dist embed.FS
+
+// staticFiles returns file system with static files. Files embedded in the
+// binary are used, unless directory is given.
+func staticFiles(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	files, err := fs.Sub(dist, "dist")
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
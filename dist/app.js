@@ -10302,6 +10302,12 @@ var _husio$scrumboard$Model$decodeState = A3(
 		_elm_lang$core$Json_Decode$field,
 		'cards',
 		_elm_lang$core$Json_Decode$list(_husio$scrumboard$Model$decodeCardState)));
+var _husio$scrumboard$Model$decodeServerError = A2(
+	_elm_lang$core$Json_Decode$andThen,
+	function (t) {
+		return _elm_lang$core$Native_Utils.eq(t, 'error') ? A2(_elm_lang$core$Json_Decode$field, 'error', _elm_lang$core$Json_Decode$string) : _elm_lang$core$Json_Decode$fail('not an error message');
+	},
+	A2(_elm_lang$core$Json_Decode$field, 'type', _elm_lang$core$Json_Decode$string));
 var _husio$scrumboard$Model$WsMessage = function (a) {
 	return {ctor: 'WsMessage', _0: a};
 };
@@ -10471,69 +10477,85 @@ var _husio$scrumboard$Update$update = F2(
 					};
 				}
 			case 'WsMessage':
-				var _p1 = A2(_elm_lang$core$Json_Decode$decodeString, _husio$scrumboard$Model$decodeState, _p0._0);
-				if (_p1.ctor === 'Err') {
+				var _p1 = {
+					ctor: '_Tuple2',
+					_0: A2(_elm_lang$core$Json_Decode$decodeString, _husio$scrumboard$Model$decodeServerError, _p0._0),
+					_1: A2(_elm_lang$core$Json_Decode$decodeString, _husio$scrumboard$Model$decodeState, _p0._0)
+				};
+				if (_p1._0.ctor === 'Ok') {
 					return {
 						ctor: '_Tuple2',
 						_0: _elm_lang$core$Native_Utils.update(
 							model,
 							{
-								error: _elm_lang$core$Maybe$Just(_p1._0)
+								error: _elm_lang$core$Maybe$Just(_p1._0._0)
 							}),
 						_1: _elm_lang$core$Platform_Cmd$none
 					};
 				} else {
-					var _p4 = _p1._0;
-					var noop = function (a) {
-						return a;
-					};
-					var idx = _elm_lang$core$Dict$fromList(
-						A2(
-							_elm_lang$core$List$map,
-							function (c) {
-								return {ctor: '_Tuple2', _0: c.issue.id, _1: c};
-							},
-							model.cards));
-					var update = function (sc) {
-						var _p2 = A2(_elm_lang$core$Dict$get, sc.issueId, idx);
-						if (_p2.ctor === 'Nothing') {
-							return {
-								ctor: '_Tuple2',
-								_0: _elm_lang$core$Maybe$Nothing,
-								_1: A3(
-									_husio$scrumboard$Update$refreshGithubIssue,
-									A2(_husio$scrumboard$Model$DroppableID, sc.position, sc.order),
-									model.flags.githubToken,
-									sc.issueUrl)
-							};
-						} else {
-							return {
-								ctor: '_Tuple2',
-								_0: _elm_lang$core$Maybe$Just(
-									_elm_lang$core$Native_Utils.update(
-										_p2._0,
-										{position: sc.position})),
-								_1: _elm_lang$core$Platform_Cmd$none
-							};
-						}
-					};
-					var _p3 = _elm_lang$core$List$unzip(
-						A2(_elm_lang$core$List$map, update, _p4.cards));
-					var maybeCards = _p3._0;
-					var cmds = _p3._1;
-					var cards = A2(_elm_lang$core$List$filterMap, noop, maybeCards);
-					var m = _husio$scrumboard$Update$adjustRowNumber(
-						_elm_lang$core$Native_Utils.update(
-							model,
-							{
-								rows: _p4.rows,
-								cards: _husio$scrumboard$Update$tidyCards(cards)
-							}));
-					return {
-						ctor: '_Tuple2',
-						_0: m,
-						_1: _elm_lang$core$Platform_Cmd$batch(cmds)
-					};
+					if (_p1._1.ctor === 'Err') {
+						return {
+							ctor: '_Tuple2',
+							_0: _elm_lang$core$Native_Utils.update(
+								model,
+								{
+									error: _elm_lang$core$Maybe$Just(_p1._1._0)
+								}),
+							_1: _elm_lang$core$Platform_Cmd$none
+						};
+					} else {
+						var _p4 = _p1._1._0;
+						var noop = function (a) {
+							return a;
+						};
+						var idx = _elm_lang$core$Dict$fromList(
+							A2(
+								_elm_lang$core$List$map,
+								function (c) {
+									return {ctor: '_Tuple2', _0: c.issue.id, _1: c};
+								},
+								model.cards));
+						var update = function (sc) {
+							var _p2 = A2(_elm_lang$core$Dict$get, sc.issueId, idx);
+							if (_p2.ctor === 'Nothing') {
+								return {
+									ctor: '_Tuple2',
+									_0: _elm_lang$core$Maybe$Nothing,
+									_1: A3(
+										_husio$scrumboard$Update$refreshGithubIssue,
+										A2(_husio$scrumboard$Model$DroppableID, sc.position, sc.order),
+										model.flags.githubToken,
+										sc.issueUrl)
+								};
+							} else {
+								return {
+									ctor: '_Tuple2',
+									_0: _elm_lang$core$Maybe$Just(
+										_elm_lang$core$Native_Utils.update(
+											_p2._0,
+											{position: sc.position})),
+									_1: _elm_lang$core$Platform_Cmd$none
+								};
+							}
+						};
+						var _p3 = _elm_lang$core$List$unzip(
+							A2(_elm_lang$core$List$map, update, _p4.cards));
+						var maybeCards = _p3._0;
+						var cmds = _p3._1;
+						var cards = A2(_elm_lang$core$List$filterMap, noop, maybeCards);
+						var m = _husio$scrumboard$Update$adjustRowNumber(
+							_elm_lang$core$Native_Utils.update(
+								model,
+								{
+									rows: _p4.rows,
+									cards: _husio$scrumboard$Update$tidyCards(cards)
+								}));
+						return {
+							ctor: '_Tuple2',
+							_0: m,
+							_1: _elm_lang$core$Platform_Cmd$batch(cmds)
+						};
+					}
 				}
 			case 'QueryIcelog':
 				return {
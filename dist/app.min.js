@@ -1,4 +1,4 @@
 (function(){"use strict";function a(a){function b(b){return function(c){return a(b,c)}}return b.arity=2,b.func=a,b}function b(a){function b(b){return function(c){return function(d){return a(b,c,d)}}}return b.arity=3,b.func=a,b}function c(a){function b(b){return function(c){return function(d){return function(e){return a(b,c,d,e)}}}}return b.arity=4,b.func=a,b}function d(a){function b(b){return function(c){return function(d){return function(e){return function(f){return a(b,c,d,e,f)}}}}}return b.arity=5,b.func=a,b}function e(a){function b(b){return function(c){return function(d){return function(e){return function(f){return function(g){return a(b,c,d,e,f,g)}}}}}}return b.arity=6,b.func=a,b}function f(a){function b(b){return function(c){return function(d){return function(e){return function(f){return function(g){return function(h){return a(b,c,d,e,f,g,h)}}}}}}}return b.arity=7,b.func=a,b}function g(a){function b(b){return function(c){return function(d){return function(e){return function(f){return function(g){return function(h){return function(i){return a(b,c,d,e,f,g,h,i)}}}}}}}}return b.arity=8,b.func=a,b}function h(a){function b(b){return function(c){return function(d){return function(e){return function(f){return function(g){return function(h){return function(i){return function(j){return a(b,c,d,e,f,g,h,i,j)}}}}}}}}}return b.arity=9,b.func=a,b}function i(a,b,c){return 2===a.arity?a.func(b,c):a(b)(c)}function j(a,b,c,d){return 3===a.arity?a.func(b,c,d):a(b)(c)(d)}function k(a,b,c,d,e){return 4===a.arity?a.func(b,c,d,e):a(b)(c)(d)(e)}function l(a,b,c,d,e,f){return 5===a.arity?a.func(b,c,d,e,f):a(b)(c)(d)(e)(f)}function m(a,b,c,d,e,f,g){return 6===a.arity?a.func(b,c,d,e,f,g):a(b)(c)(d)(e)(f)(g)}var n=function(){function c(a,b){if(0>a||a>=L(b))throw new Error("Index "+a+" is out of range. Check the length of your array first or use getMaybe or getWithDefault.");return d(a,b)}function d(a,b){for(var c=b.height;c>0;c--){for(var d=a>>5*c;b.lengths[d]<=a;)d++;d>0&&(a-=b.lengths[d-1]),b=b.table[d]}return b.table[a]}function e(a,b,c){return 0>a||L(c)<=a?c:f(a,b,c)}function f(a,b,c){if(c=K(c),0===c.height)c.table[a]=b;else{var d=M(a,c);d>0&&(a-=c.lengths[d-1]),c.table[d]=f(a,b,c.table[d])}return c}function g(a,b){if(0>=a)return W;var c=Math.floor(Math.log(a)/Math.log(U));return h(b,c,0,a)}function h(a,b,c,d){if(0===b){for(var e=new Array((d-c)%(U+1)),f=0;f<e.length;f++)e[f]=a(c+f);return{ctor:"_Array",height:0,table:e}}for(var g=Math.pow(U,b),e=new Array(Math.ceil((d-c)/g)),i=new Array(e.length),f=0;f<e.length;f++)e[f]=h(a,b-1,c+f*g,Math.min(c+(f+1)*g,d)),i[f]=L(e[f])+(f>0?i[f-1]:0);return{ctor:"_Array",height:b,table:e,lengths:i}}function j(a){if("[]"===a.ctor)return W;for(var b=new Array(U),c=[],d=0;"[]"!==a.ctor;)if(b[d]=a._0,a=a._1,d++,d===U){var e={ctor:"_Array",height:0,table:b};k(e,c),b=new Array(U),d=0}if(d>0){var e={ctor:"_Array",height:0,table:b.splice(0,d)};k(e,c)}for(var f=0;f<c.length-1;f++)c[f].table.length>0&&k(c[f],c);var g=c[c.length-1];return g.height>0&&1===g.table.length?g.table[0]:g}function k(a,b){var c=a.height;if(b.length===c){var d={ctor:"_Array",height:c+1,table:[],lengths:[]};b.push(d)}b[c].table.push(a);var e=L(a);b[c].lengths.length>0&&(e+=b[c].lengths[b[c].lengths.length-1]),b[c].lengths.push(e),b[c].table.length===U&&(k(b[c],b),b[c]={ctor:"_Array",height:c+1,table:[],lengths:[]})}function l(a,b){var c=m(a,b);if(null!==c)return c;var d=N(a,b.height);return P(b,d)}function m(a,b){if(0===b.height){if(b.table.length<U){var c={ctor:"_Array",height:0,table:b.table.slice()};return c.table.push(a),c}return null}var d=m(a,I(b));if(null!==d){var c=K(b);return c.table[c.table.length-1]=d,c.lengths[c.lengths.length-1]++,c}if(b.table.length<U){var e=N(a,b.height-1),c=K(b);return c.table.push(e),c.lengths.push(c.lengths[c.lengths.length-1]+L(e)),c}return null}function n(a){return o(D.Nil,a)}function o(a,b){for(var c=b.table.length-1;c>=0;c--)a=0===b.height?D.Cons(b.table[c],a):o(a,b.table[c]);return a}function p(a,b){var c={ctor:"_Array",height:b.height,table:new Array(b.table.length)};b.height>0&&(c.lengths=b.lengths);for(var d=0;d<b.table.length;d++)c.table[d]=0===b.height?a(b.table[d]):p(a,b.table[d]);return c}function q(a,b){return r(a,b,0)}function r(a,b,c){var d={ctor:"_Array",height:b.height,table:new Array(b.table.length)};b.height>0&&(d.lengths=b.lengths);for(var e=0;e<b.table.length;e++)d.table[e]=0===b.height?i(a,c+e,b.table[e]):r(a,b.table[e],0==e?c:c+b.lengths[e-1]);return d}function s(a,b,c){if(0===c.height)for(var d=0;d<c.table.length;d++)b=i(a,c.table[d],b);else for(var d=0;d<c.table.length;d++)b=s(a,b,c.table[d]);return b}function t(a,b,c){if(0===c.height)for(var d=c.table.length;d--;)b=i(a,c.table[d],b);else for(var d=c.table.length;d--;)b=t(a,b,c.table[d]);return b}function u(a,b,c){return 0>a&&(a+=L(c)),0>b&&(b+=L(c)),w(a,v(b,c))}function v(a,b){if(a===L(b))return b;if(0===b.height){var c={ctor:"_Array",height:0};return c.table=b.table.slice(0,a),c}var d=M(a,b),e=v(a-(d>0?b.lengths[d-1]:0),b.table[d]);if(0===d)return e;var c={ctor:"_Array",height:b.height,table:b.table.slice(0,d),lengths:b.lengths.slice(0,d)};return e.table.length>0&&(c.table[d]=e,c.lengths[d]=L(e)+(d>0?c.lengths[d-1]:0)),c}function w(a,b){if(0===a)return b;if(0===b.height){var c={ctor:"_Array",height:0};return c.table=b.table.slice(a,b.table.length+1),c}var d=M(a,b),e=w(a-(d>0?b.lengths[d-1]:0),b.table[d]);if(d===b.table.length-1)return e;var c={ctor:"_Array",height:b.height,table:b.table.slice(d,b.table.length+1),lengths:new Array(b.table.length-d)};c.table[0]=e;for(var f=0,g=0;g<c.table.length;g++)f+=L(c.table[g]),c.lengths[g]=f;return c}function x(a,b){if(0===a.table.length)return b;if(0===b.table.length)return a;var c=y(a,b);if(c[0].table.length+c[1].table.length<=U){if(0===c[0].table.length)return c[1];if(0===c[1].table.length)return c[0];if(c[0].table=c[0].table.concat(c[1].table),c[0].height>0){for(var d=L(c[0]),e=0;e<c[1].lengths.length;e++)c[1].lengths[e]+=d;c[0].lengths=c[0].lengths.concat(c[1].lengths)}return c[0]}if(c[0].height>0){var f=B(a,b);f>V&&(c=H(c[0],c[1],f))}return P(c[0],c[1])}function y(a,b){if(0===a.height&&0===b.height)return[a,b];if(1!==a.height||1!==b.height)if(a.height===b.height){a=K(a),b=K(b);var c=y(I(a),J(b));z(a,c[1]),A(b,c[0])}else if(a.height>b.height){a=K(a);var c=y(I(a),b);z(a,c[0]),b=O(c[1],c[1].height+1)}else{b=K(b);var c=y(a,J(b)),d=0===c[0].table.length?0:1,e=0===d?1:0;A(b,c[d]),a=O(c[e],c[e].height+1)}if(0===a.table.length||0===b.table.length)return[a,b];var f=B(a,b);return V>=f?[a,b]:H(a,b,f)}function z(a,b){var c=a.table.length-1;a.table[c]=b,a.lengths[c]=L(b),a.lengths[c]+=c>0?a.lengths[c-1]:0}function A(a,b){if(b.table.length>0){a.table[0]=b,a.lengths[0]=L(b);for(var c=L(a.table[0]),d=1;d<a.lengths.length;d++)c+=L(a.table[d]),a.lengths[d]=c}else{a.table.shift();for(var d=1;d<a.lengths.length;d++)a.lengths[d]=a.lengths[d]-a.lengths[0];a.lengths.shift()}}function B(a,b){for(var c=0,d=0;d<a.table.length;d++)c+=a.table[d].table.length;for(var d=0;d<b.table.length;d++)c+=b.table[d].table.length;var e=a.table.length+b.table.length;return e-(Math.floor((c-1)/U)+1)}function C(a,b,c){return c<a.length?a[c]:b[c-a.length]}function E(a,b,c,d){c<a.length?a[c]=d:b[c-a.length]=d}function F(a,b,c,d){E(a.table,b.table,c,d);var e=0===c||c===a.lengths.length?0:C(a.lengths,a.lengths,c-1);E(a.lengths,b.lengths,c,e+L(d))}function G(a,b){0>b&&(b=0);var c={ctor:"_Array",height:a,table:new Array(b)};return a>0&&(c.lengths=new Array(b)),c}function H(a,b,c){for(var d=G(a.height,Math.min(U,a.table.length+b.table.length-c)),e=G(a.height,d.table.length-(a.table.length+b.table.length-c)),f=0;C(a.table,b.table,f).table.length%U===0;)E(d.table,e.table,f,C(a.table,b.table,f)),E(d.lengths,e.lengths,f,C(a.lengths,b.lengths,f)),f++;for(var g=f,h=new G(a.height-1,0),i=0;f-g-(h.table.length>0?1:0)<c;){var j=C(a.table,b.table,f),k=Math.min(U-h.table.length,j.table.length);if(h.table=h.table.concat(j.table.slice(i,k)),h.height>0)for(var l=h.lengths.length,m=l;l+k-i>m;m++)h.lengths[m]=L(h.table[m]),h.lengths[m]+=m>0?h.lengths[m-1]:0;i+=k,j.table.length<=k&&(f++,i=0),h.table.length===U&&(F(d,e,g,h),h=G(a.height-1,0),g++)}for(h.table.length>0&&(F(d,e,g,h),g++);f<a.table.length+b.table.length;)F(d,e,g,C(a.table,b.table,f)),f++,g++;return[d,e]}function I(a){return a.table[a.table.length-1]}function J(a){return a.table[0]}function K(a){var b={ctor:"_Array",height:a.height,table:a.table.slice()};return a.height>0&&(b.lengths=a.lengths.slice()),b}function L(a){return 0===a.height?a.table.length:a.lengths[a.lengths.length-1]}function M(a,b){for(var c=a>>5*b.height;b.lengths[c]<=a;)c++;return c}function N(a,b){return 0===b?{ctor:"_Array",height:0,table:[a]}:{ctor:"_Array",height:b,table:[N(a,b-1)],lengths:[1]}}function O(a,b){return b===a.height?a:{ctor:"_Array",height:b,table:[O(a,b-1)],lengths:[L(a)]}}function P(a,b){return{ctor:"_Array",height:a.height+1,table:[a,b],lengths:[L(a),L(a)+L(b)]}}function Q(a){var b=new Array(L(a));return R(b,0,a),b}function R(a,b,c){for(var d=0;d<c.table.length;d++)if(0===c.height)a[b+d]=c.table[d];else{var e=0===d?0:c.lengths[d-1];R(a,b+e,c.table[d])}}function S(a){if(0===a.length)return W;var b=Math.floor(Math.log(a.length)/Math.log(U));return T(a,b,0,a.length)}function T(a,b,c,d){if(0===b)return{ctor:"_Array",height:0,table:a.slice(c,d)};for(var e=Math.pow(U,b),f=new Array(Math.ceil((d-c)/e)),g=new Array(f.length),h=0;h<f.length;h++)f[h]=T(a,b-1,c+h*e,Math.min(c+(h+1)*e,d)),g[h]=L(f[h])+(h>0?g[h-1]:0);return{ctor:"_Array",height:b,table:f,lengths:g}}var U=32,V=2,W={ctor:"_Array",height:0,table:[]};return{empty:W,fromList:j,toList:n,initialize:a(g),append:a(x),push:a(l),slice:b(u),get:a(c),set:b(e),map:a(p),indexedMap:a(q),foldl:b(s),foldr:b(t),length:L,toJSArray:Q,fromJSArray:S}}(),o=function(){function c(a,b){return a/b|0}function d(a,b){return a%b}function e(a,b){if(0===b)throw new Error("Cannot perform mod 0. Division by zero error.");var c=a%b,d=0===a?0:b>0?a>=0?c:c+b:-e(-a,-b);return d===b?0:d}function f(a,b){return Math.log(b)/Math.log(a)}function g(a){return-a}function h(a){return 0>a?-a:a}function i(a,b){return p.cmp(a,b)<0?a:b}function j(a,b){return p.cmp(a,b)>0?a:b}function k(a,b,c){return p.cmp(c,a)<0?a:p.cmp(c,b)>0?b:c}function l(a,b){return{ctor:v[p.cmp(a,b)+1]}}function m(a,b){return a!==b}function n(a){return!a}function o(a){return a===1/0||a===-(1/0)}function q(a){return 0|a}function r(a){return a*Math.PI/180}function s(a){return 2*Math.PI*a}function t(a){var b=a._0,c=a._1;return p.Tuple2(b*Math.cos(c),b*Math.sin(c))}function u(a){var b=a._0,c=a._1;return p.Tuple2(Math.sqrt(b*b+c*c),Math.atan2(c,b))}var v=["LT","EQ","GT"];return{div:a(c),rem:a(d),mod:a(e),pi:Math.PI,e:Math.E,cos:Math.cos,sin:Math.sin,tan:Math.tan,acos:Math.acos,asin:Math.asin,atan:Math.atan,atan2:a(Math.atan2),degrees:r,turns:s,fromPolar:t,toPolar:u,sqrt:Math.sqrt,logBase:a(f),negate:g,abs:h,min:a(i),max:a(j),clamp:b(k),compare:a(l),xor:a(m),not:n,truncate:q,ceiling:Math.ceil,floor:Math.floor,round:Math.round,toFloat:function(a){return a},isNaN:isNaN,isInfinite:o}}(),p=function(){function b(a,b){for(var d,e=[],f=c(a,b,0,e);f&&(d=e.pop());)f=c(d.x,d.y,0,e);return f}function c(a,b,d,e){if(d>100)return e.push({x:a,y:b}),!0;if(a===b)return!0;if("object"!=typeof a){if("function"==typeof a)throw new Error('Trying to use `(==)` on functions. There is no way to know if functions are "the same" in the Elm sense. Read more about this at http://package.elm-lang.org/packages/elm-lang/core/latest/Basics#== which describes why it is this way and what the better version will look like.');return!1}if(null===a||null===b)return!1;if(a instanceof Date)return a.getTime()===b.getTime();if(!("ctor"in a)){for(var f in a)if(!c(a[f],b[f],d+1,e))return!1;return!0}if(("RBNode_elm_builtin"===a.ctor||"RBEmpty_elm_builtin"===a.ctor)&&(a=na(a),b=na(b)),"Set_elm_builtin"===a.ctor&&(a=_elm_lang$core$Set$toList(a),b=_elm_lang$core$Set$toList(b)),"::"===a.ctor){for(var g=a,h=b;"::"===g.ctor&&"::"===h.ctor;){if(!c(g._0,h._0,d+1,e))return!1;g=g._1,h=h._1}return g.ctor===h.ctor}if("_Array"===a.ctor){var i=n.toJSArray(a),j=n.toJSArray(b);if(i.length!==j.length)return!1;for(var k=0;k<i.length;k++)if(!c(i[k],j[k],d+1,e))return!1;return!0}if(!c(a.ctor,b.ctor,d+1,e))return!1;for(var f in a)if(!c(a[f],b[f],d+1,e))return!1;return!0}function d(a,b){if("object"!=typeof a)return a===b?r:b>a?q:s;if(a instanceof String){var c=a.valueOf(),e=b.valueOf();return c===e?r:e>c?q:s}if("::"===a.ctor||"[]"===a.ctor){for(;"::"===a.ctor&&"::"===b.ctor;){var f=d(a._0,b._0);if(f!==r)return f;a=a._1,b=b._1}return a.ctor===b.ctor?r:"[]"===a.ctor?q:s}if("_Tuple"===a.ctor.slice(0,6)){var f,g=a.ctor.slice(6)-0,h="cannot compare tuples with more than 6 elements.";if(0===g)return r;if(g>=1){if(f=d(a._0,b._0),f!==r)return f;if(g>=2){if(f=d(a._1,b._1),f!==r)return f;if(g>=3){if(f=d(a._2,b._2),f!==r)return f;if(g>=4){if(f=d(a._3,b._3),f!==r)return f;if(g>=5){if(f=d(a._4,b._4),f!==r)return f;if(g>=6){if(f=d(a._5,b._5),f!==r)return f;if(g>=7)throw new Error("Comparison error: "+h)}}}}}}return r}throw new Error("Comparison error: comparison is only defined on ints, floats, times, chars, strings, lists of comparable values, and tuples of comparable values.")}function e(a,b){return{ctor:"_Tuple2",_0:a,_1:b}}function f(a){return new String(a)}function g(a){return u++}function h(a,b){var c={};for(var d in a)c[d]=a[d];for(var d in b)c[d]=b[d];return c}function i(a,b){return{ctor:"::",_0:a,_1:b}}function j(a,b){if("string"==typeof a)return a+b;if("[]"===a.ctor)return b;var c=i(a._0,v),d=c;for(a=a._1;"[]"!==a.ctor;)d._1=i(a._0,v),a=a._1,d=d._1;return d._1=b,c}function k(a,b){return function(c){throw new Error("Ran into a `Debug.crash` in module `"+a+"` "+m(b)+"\nThe message provided by the code author is:\n\n    "+c)}}function l(a,b,c){return function(d){throw new Error("Ran into a `Debug.crash` in module `"+a+"`\n\nThis was caused by the `case` expression "+m(b)+".\nOne of the branches ended with a crash and the following value got through:\n\n    "+o(c)+"\n\nThe message provided by the code author is:\n\n    "+d)}}function m(a){return a.start.line==a.end.line?"on line "+a.start.line:"between lines "+a.start.line+" and "+a.end.line}function o(a){var b=typeof a;if("function"===b)return"<function>";if("boolean"===b)return a?"True":"False";if("number"===b)return a+"";if(a instanceof String)return"'"+p(a,!0)+"'";if("string"===b)return'"'+p(a,!1)+'"';if(null===a)return"null";if("object"===b&&"ctor"in a){var c=a.ctor.substring(0,5);if("_Tupl"===c){var d=[];for(var e in a)"ctor"!==e&&d.push(o(a[e]));return"("+d.join(",")+")"}if("_Task"===c)return"<task>";if("_Array"===a.ctor){var f=aa(a);return"Array.fromList "+o(f)}if("<decoder>"===a.ctor)return"<decoder>";if("_Process"===a.ctor)return"<process:"+a.id+">";if("::"===a.ctor){var d="["+o(a._0);for(a=a._1;"::"===a.ctor;)d+=","+o(a._0),a=a._1;return d+"]"}if("[]"===a.ctor)return"[]";if("Set_elm_builtin"===a.ctor)return"Set.fromList "+o(_elm_lang$core$Set$toList(a));if("RBNode_elm_builtin"===a.ctor||"RBEmpty_elm_builtin"===a.ctor)return"Dict.fromList "+o(na(a));var d="";for(var g in a)if("ctor"!==g){var h=o(a[g]),i=h[0],j="{"===i||"("===i||"<"===i||'"'===i||h.indexOf(" ")<0;d+=" "+(j?h:"("+h+")")}return a.ctor+d}if("object"===b){if(a instanceof Date)return"<"+a.toString()+">";if(a.elm_web_socket)return"<websocket>";var d=[];for(var e in a)d.push(e+" = "+o(a[e]));return 0===d.length?"{}":"{ "+d.join(", ")+" }"}return"<internal structure>"}function p(a,b){var c=a.replace(/\\/g,"\\\\").replace(/\n/g,"\\n").replace(/\t/g,"\\t").replace(/\r/g,"\\r").replace(/\v/g,"\\v").replace(/\0/g,"\\0");return b?c.replace(/\'/g,"\\'"):c.replace(/\"/g,'\\"')}var q=-1,r=0,s=1,t={ctor:"_Tuple0"},u=0,v={ctor:"[]"};return{eq:b,cmp:d,Tuple0:t,Tuple2:e,chr:f,update:h,guid:g,append:a(j),crash:k,crashCase:l,toString:o}}(),q=(a(function(a,b){var c=b;return i(a,c._0,c._1)}),b(function(a,b,c){return a({ctor:"_Tuple2",_0:b,_1:c})}),b(function(a,b,c){return i(a,c,b)}),a(function(a,b){return a})),r=function(a){return a},s=s||{};s["<|"]=a(function(a,b){return a(b)});var s=s||{};s["|>"]=a(function(a,b){return b(a)});var s=s||{};s[">>"]=b(function(a,b,c){return b(a(c))});var s=s||{};s["<<"]=b(function(a,b,c){return a(b(c))});var s=s||{};s["++"]=p.append;var t=p.toString,u=(o.isInfinite,o.isNaN,o.toFloat),v=o.ceiling,s=(o.floor,o.truncate,o.round,o.not,o.xor,s||{});s["||"]=o.or;var s=s||{};s["&&"]=o.and;var w=o.max,x=(o.min,o.compare),s=s||{};s[">="]=o.ge;var s=s||{};s["<="]=o.le;var s=s||{};s[">"]=o.gt;var s=s||{};s["<"]=o.lt;var s=s||{};s["/="]=o.neq;var s=s||{};s["=="]=o.eq;var s=(o.e,o.pi,o.clamp,o.logBase,o.abs,o.negate,o.sqrt,o.atan2,o.atan,o.asin,o.acos,o.tan,o.sin,o.cos,s||{});s["^"]=o.exp;var s=s||{};s["%"]=o.mod;var s=(o.rem,s||{});s["//"]=o.div;var s=s||{};s["/"]=o.floatDiv;var s=s||{};s["*"]=o.mul;var s=s||{};s["-"]=o.sub;var s=s||{};s["+"]=o.add;var y=(o.toPolar,o.fromPolar,o.turns,o.degrees,{ctor:"GT"}),z={ctor:"LT"},A=a(function(a,b){var c=b;return"Just"===c.ctor?c._0:a}),B={ctor:"Nothing"},C=(a(function(a,b){var c=b;return"Just"===c.ctor?a(c._0):B}),function(a){return{ctor:"Just",_0:a}}),D=(a(function(a,b){var c=b;return"Just"===c.ctor?C(a(c._0)):B}),b(function(a,b,c){var d={ctor:"_Tuple2",_0:b,_1:c};return"_Tuple2"===d.ctor&&"Just"===d._0.ctor&&"Just"===d._1.ctor?C(i(a,d._0._0,d._1._0)):B}),c(function(a,b,c,d){var e={ctor:"_Tuple3",_0:b,_1:c,_2:d};return"_Tuple3"===e.ctor&&"Just"===e._0.ctor&&"Just"===e._1.ctor&&"Just"===e._2.ctor?C(j(a,e._0._0,e._1._0,e._2._0)):B}),d(function(a,b,c,d,e){var f={ctor:"_Tuple4",_0:b,_1:c,_2:d,_3:e};return"_Tuple4"===f.ctor&&"Just"===f._0.ctor&&"Just"===f._1.ctor&&"Just"===f._2.ctor&&"Just"===f._3.ctor?C(k(a,f._0._0,f._1._0,f._2._0,f._3._0)):B}),e(function(a,b,c,d,e,f){var g={ctor:"_Tuple5",_0:b,_1:c,_2:d,_3:e,_4:f};return"_Tuple5"===g.ctor&&"Just"===g._0.ctor&&"Just"===g._1.ctor&&"Just"===g._2.ctor&&"Just"===g._3.ctor&&"Just"===g._4.ctor?C(l(a,g._0._0,g._1._0,g._2._0,g._3._0,g._4._0)):B}),function(){function f(a,b){return{ctor:"::",_0:a,_1:b}}function g(a){for(var b=u,c=a.length;c--;)b=f(a[c],b);return b}function h(a){for(var b=[];"[]"!==a.ctor;)b.push(a._0),a=a._1;return b}function m(a,b,c){for(var d=h(c),e=b,f=d.length;f--;)e=i(a,d[f],e);return e}function n(a,b,c){for(var d=[];"[]"!==b.ctor&&"[]"!==c.ctor;)d.push(i(a,b._0,c._0)),b=b._1,c=c._1;return g(d)}function o(a,b,c,d){for(var e=[];"[]"!==b.ctor&&"[]"!==c.ctor&&"[]"!==d.ctor;)e.push(j(a,b._0,c._0,d._0)),b=b._1,c=c._1,d=d._1;return g(e)}function q(a,b,c,d,e){for(var f=[];"[]"!==b.ctor&&"[]"!==c.ctor&&"[]"!==d.ctor&&"[]"!==e.ctor;)f.push(k(a,b._0,c._0,d._0,e._0)),b=b._1,c=c._1,d=d._1,e=e._1;return g(f)}function r(a,b,c,d,e,f){for(var h=[];"[]"!==b.ctor&&"[]"!==c.ctor&&"[]"!==d.ctor&&"[]"!==e.ctor&&"[]"!==f.ctor;)h.push(l(a,b._0,c._0,d._0,e._0,f._0)),b=b._1,c=c._1,d=d._1,e=e._1,f=f._1;return g(h)}function s(a,b){return g(h(b).sort(function(b,c){return p.cmp(a(b),a(c))}))}function t(a,b){return g(h(b).sort(function(b,c){var d=a(b)(c).ctor;return"EQ"===d?0:"LT"===d?-1:1}))}var u={ctor:"[]"};return{Nil:u,Cons:f,cons:a(f),toArray:h,fromArray:g,foldr:b(m),map2:b(n),map3:c(o),map4:d(q),map5:e(r),sortBy:a(s),sortWith:a(t)}}()),E=D.sortWith,F=(D.sortBy,a(function(a,b){for(;;){if(p.cmp(a,0)<1)return b;var c=b;if("[]"===c.ctor)return b;var d=a-1,e=c._1;a=d,b=e}}),D.map5,D.map4,D.map3,D.map2),G=a(function(a,b){for(;;){var c=b;if("[]"===c.ctor)return!1;if(a(c._0))return!0;var d=a,e=c._1;a=d,b=e}}),H=(a(function(a,b){return!i(G,function(b){return!a(b)},b)}),D.foldr),I=b(function(a,b,c){for(;;){var d=c;if("[]"===d.ctor)return b;var e=a,f=i(a,d._0,b),g=d._1;a=e,b=f,c=g}}),J=function(b){return j(I,a(function(a,b){return b+1}),0,b)},K=function(a){var b=a;return"::"===b.ctor?C(j(I,w,b._0,b._1)):B},L=a(function(a,b){return i(G,function(b){return p.eq(b,a)},b)}),M=function(a){var b=a;return"::"===b.ctor?C(b._0):B},N=N||{};N["::"]=D.cons;var O=a(function(b,c){return j(H,a(function(a,c){return{ctor:"::",_0:b(a),_1:c}}),{ctor:"[]"},c)}),P=a(function(b,c){var d=a(function(a,c){return b(a)?{ctor:"::",_0:a,_1:c}:c});return j(H,d,{ctor:"[]"},c)}),Q=b(function(a,b,c){var d=a(b);return"Just"===d.ctor?{ctor:"::",_0:d._0,_1:c}:c}),R=a(function(a,b){return j(H,Q(a),{ctor:"[]"},b)}),S=function(b){return j(I,a(function(a,b){return{ctor:"::",_0:a,_1:b}}),{ctor:"[]"},b)},T=(b(function(b,c,d){var e=a(function(a,c){var d=c;return"::"===d.ctor?{ctor:"::",_0:i(b,a,d._0),_1:c}:{ctor:"[]"}});return S(j(I,e,{ctor:"::",_0:c,_1:{ctor:"[]"}},d))}),a(function(b,c){var d=c;return"[]"===d.ctor?b:j(H,a(function(a,b){return{ctor:"::",_0:a,_1:b}}),c,b)})),U=function(a){return j(H,T,{ctor:"[]"},a)},V=(a(function(a,b){return U(i(O,a,b))}),a(function(b,c){var d=a(function(a,c){var d=c,e=d._0,f=d._1;return b(a)?{ctor:"_Tuple2",_0:{ctor:"::",_0:a,_1:e},_1:f}:{ctor:"_Tuple2",_0:e,_1:{ctor:"::",_0:a,_1:f}}});return j(H,d,{ctor:"_Tuple2",_0:{ctor:"[]"},_1:{ctor:"[]"}},c)}),function(b){var c=a(function(a,b){var c=a,d=b;return{ctor:"_Tuple2",_0:{ctor:"::",_0:c._0,_1:d._0},_1:{ctor:"::",_0:c._1,_1:d._1}}});return j(H,c,{ctor:"_Tuple2",_0:{ctor:"[]"},_1:{ctor:"[]"}},b)}),W=(a(function(b,c){var d=c;if("[]"===d.ctor)return{ctor:"[]"};var e=a(function(a,c){return{ctor:"::",_0:b,_1:{ctor:"::",_0:a,_1:c}}}),f=j(H,e,{ctor:"[]"},d._1);return{ctor:"::",_0:d._0,_1:f}}),b(function(a,b,c){for(;;){if(p.cmp(a,0)<1)return c;var d=b;if("[]"===d.ctor)return c;var e=a-1,f=d._1,g={ctor:"::",_0:d._0,_1:c};a=e,b=f,c=g}})),X=a(function(a,b){return S(j(W,a,b,{ctor:"[]"}))}),Y=b(function(a,b,c){if(p.cmp(b,0)<1)return{ctor:"[]"};var d={ctor:"_Tuple2",_0:b,_1:c};a:do{b:do{if("_Tuple2"!==d.ctor)break a;if("[]"===d._1.ctor)return c;if("::"!==d._1._1.ctor){if(1===d._0)break b;break a}switch(d._0){case 1:break b;case 2:return{ctor:"::",_0:d._1._0,_1:{ctor:"::",_0:d._1._1._0,_1:{ctor:"[]"}}};case 3:if("::"===d._1._1._1.ctor)return{ctor:"::",_0:d._1._0,_1:{ctor:"::",_0:d._1._1._0,_1:{ctor:"::",_0:d._1._1._1._0,_1:{ctor:"[]"}}}};break a;default:if("::"===d._1._1._1.ctor&&"::"===d._1._1._1._1.ctor){var e=d._1._1._1._0,f=d._1._1._0,g=d._1._0,h=d._1._1._1._1._0,k=d._1._1._1._1._1;return p.cmp(a,1e3)>0?{ctor:"::",_0:g,_1:{ctor:"::",_0:f,_1:{ctor:"::",_0:e,_1:{ctor:"::",_0:h,_1:i(X,b-4,k)}}}}:{ctor:"::",_0:g,_1:{ctor:"::",_0:f,_1:{ctor:"::",_0:e,_1:{ctor:"::",_0:h,_1:j(Y,a+1,b-4,k)}}}}}break a}}while(!1);return{ctor:"::",_0:d._1._0,_1:{ctor:"[]"}}}while(!1);return c}),Z=(a(function(a,b){return j(Y,0,a,b)}),b(function(a,b,c){for(;;){if(p.cmp(b,0)<1)return a;var d={ctor:"::",_0:c,_1:a},e=b-1,f=c;a=d,b=e,c=f}})),$=(a(function(a,b){return j(Z,{ctor:"[]"},a,b)}),b(function(a,b,c){for(;;){if(!(p.cmp(a,b)<1))return c;var d=a,e=b-1,f={ctor:"::",_0:b,_1:c};a=d,b=e,c=f}})),_=a(function(a,b){return j($,a,b,{ctor:"[]"})}),aa=(a(function(a,b){return j(F,a,i(_,0,J(b)-1),b)}),n.append,n.length,n.slice,n.set,a(function(a,b){return p.cmp(0,a)<1&&p.cmp(a,n.length(b))<0?C(i(n.get,a,b)):B}),n.push,n.empty,a(function(b,c){var d=a(function(a,c){return b(a)?i(n.push,a,c):c});return j(n.foldl,d,n.empty,c)}),n.foldr,n.foldl,n.indexedMap,n.map,n.toList),ba=(n.fromList,n.initialize),ca=(a(function(a,b){return i(ba,a,q(b))}),function(){function b(a,b){var c=a+": "+p.toString(b),d=d||{};return d.stdout?d.stdout.write(c):console.log(c),b}function c(a){throw new Error(a)}return{crash:c,log:a(b)}}()),da=function(){function c(a){return 0===a.length}function d(a,b){return a+b}function e(a){var b=a[0];return b?C(p.Tuple2(p.chr(b),a.slice(1))):B}function f(a,b){return a+b}function g(a){return D.toArray(a).join("")}function h(a){return a.length}function j(a,b){for(var c=b.split(""),d=c.length;d--;)c[d]=a(p.chr(c[d]));return c.join("")}function k(a,b){return b.split("").map(p.chr).filter(a).join("")}function l(a){return a.split("").reverse().join("")}function m(a,b,c){for(var d=c.length,e=0;d>e;++e)b=i(a,p.chr(c[e]),b);return b}function n(a,b,c){for(var d=c.length;d--;)b=i(a,p.chr(c[d]),b);return b}function o(a,b){return D.fromArray(b.split(a))}function q(a,b){return D.toArray(b).join(a)}function r(a,b){for(var c="";a>0;)1&a&&(c+=b),a>>=1,b+=b;return c}function s(a,b,c){return c.slice(a,b)}function t(a,b){return 1>a?"":b.slice(0,a)}function u(a,b){return 1>a?"":b.slice(-a)}function v(a,b){return 1>a?b:b.slice(a)}function w(a,b){return 1>a?b:b.slice(0,-a)}function x(a,b,c){var d=(a-c.length)/2;return r(Math.ceil(d),b)+c+r(0|d,b)}function y(a,b,c){return c+r(a-c.length,b)}function z(a,b,c){return r(a-c.length,b)+c}function A(a){return a.trim()}function E(a){return a.replace(/^\s+/,"")}function F(a){return a.replace(/\s+$/,"")}function G(a){return D.fromArray(a.trim().split(/\s+/g))}function H(a){return D.fromArray(a.split(/\r\n|\r|\n/g))}function I(a){return a.toUpperCase()}function J(a){return a.toLowerCase()}function K(a,b){for(var c=b.length;c--;)if(a(p.chr(b[c])))return!0;return!1}function L(a,b){for(var c=b.length;c--;)if(!a(p.chr(b[c])))return!1;return!0}function M(a,b){return b.indexOf(a)>-1}function N(a,b){return 0===b.indexOf(a)}function O(a,b){return b.length>=a.length&&b.lastIndexOf(a)===b.length-a.length}function P(a,b){var c=a.length;if(1>c)return D.Nil;for(var d=0,e=[];(d=b.indexOf(a,d))>-1;)e.push(d),d+=c;return D.fromArray(e)}function Q(a){var b=a.length;if(0===b)return R(a);var c=a[0];if("0"===c&&"x"===a[1]){for(var d=2;b>d;++d){var c=a[d];if(!(c>="0"&&"9">=c||c>="A"&&"F">=c||c>="a"&&"f">=c))return R(a)}return ia(parseInt(a,16))}if(c>"9"||"0">c&&"-"!==c&&"+"!==c)return R(a);for(var d=1;b>d;++d){var c=a[d];if("0">c||c>"9")return R(a)}return ia(parseInt(a,10))}function R(a){return ha("could not convert string '"+a+"' to an Int")}function S(a){if(0===a.length||/[\sxbo]/.test(a))return T(a);var b=+a;return b===b?ia(b):T(a)}function T(a){return ha("could not convert string '"+a+"' to a Float")}function U(a){return D.fromArray(a.split("").map(p.chr))}function V(a){return D.toArray(a).join("")}return{isEmpty:c,cons:a(d),uncons:e,append:a(f),concat:g,length:h,map:a(j),filter:a(k),reverse:l,foldl:b(m),foldr:b(n),split:a(o),join:a(q),repeat:a(r),slice:b(s),left:a(t),right:a(u),dropLeft:a(v),dropRight:a(w),pad:b(x),padLeft:b(z),padRight:b(y),trim:A,trimLeft:E,trimRight:F,words:G,lines:H,toUpper:I,toLower:J,any:a(K),all:a(L),contains:a(M),startsWith:a(N),endsWith:a(O),indexes:a(P),toInt:Q,toFloat:S,toList:U,fromList:V}}(),ea=function(){return{fromCode:function(a){return p.chr(String.fromCharCode(a))},toCode:function(a){return a.charCodeAt(0)},toUpper:function(a){return p.chr(a.toUpperCase())},toLower:function(a){return p.chr(a.toLowerCase())},toLocaleUpper:function(a){return p.chr(a.toLocaleUpperCase())},toLocaleLower:function(a){return p.chr(a.toLocaleLowerCase())}}}(),fa=(ea.fromCode,ea.toCode),ga=(ea.toLocaleLower,ea.toLocaleUpper,ea.toLower,ea.toUpper,b(function(a,b,c){var d=fa(c);return p.cmp(d,fa(a))>-1&&p.cmp(d,fa(b))<1})),ha=(i(ga,p.chr("A"),p.chr("Z")),i(ga,p.chr("a"),p.chr("z")),i(ga,p.chr("0"),p.chr("9")),i(ga,p.chr("0"),p.chr("7")),a(function(a,b){var c=b;return"Ok"===c.ctor?c._0:a}),function(a){return{ctor:"Err",_0:a}}),ia=(a(function(a,b){var c=b;return"Ok"===c.ctor?a(c._0):ha(c._0)}),function(a){return{ctor:"Ok",_0:a}}),ja=a(function(a,b){var c=b;return"Ok"===c.ctor?ia(a(c._0)):ha(c._0)}),ka=(b(function(a,b,c){var d={ctor:"_Tuple2",_0:b,_1:c};return"Ok"===d._0.ctor?"Ok"===d._1.ctor?ia(i(a,d._0._0,d._1._0)):ha(d._1._0):ha(d._0._0)}),c(function(a,b,c,d){var e={ctor:"_Tuple3",_0:b,_1:c,_2:d};return"Ok"===e._0.ctor?"Ok"===e._1.ctor?"Ok"===e._2.ctor?ia(j(a,e._0._0,e._1._0,e._2._0)):ha(e._2._0):ha(e._1._0):ha(e._0._0)}),d(function(a,b,c,d,e){var f={ctor:"_Tuple4",_0:b,_1:c,_2:d,_3:e};return"Ok"===f._0.ctor?"Ok"===f._1.ctor?"Ok"===f._2.ctor?"Ok"===f._3.ctor?ia(k(a,f._0._0,f._1._0,f._2._0,f._3._0)):ha(f._3._0):ha(f._2._0):ha(f._1._0):ha(f._0._0)}),e(function(a,b,c,d,e,f){var g={ctor:"_Tuple5",_0:b,_1:c,_2:d,_3:e,_4:f};return"Ok"===g._0.ctor?"Ok"===g._1.ctor?"Ok"===g._2.ctor?"Ok"===g._3.ctor?"Ok"===g._4.ctor?ia(l(a,g._0._0,g._1._0,g._2._0,g._3._0,g._4._0)):ha(g._4._0):ha(g._3._0):ha(g._2._0):ha(g._1._0):ha(g._0._0)}),a(function(a,b){var c=b;return"Ok"===c.ctor?ia(c._0):ha(a(c._0))}),a(function(a,b){var c=b;return"Just"===c.ctor?ia(c._0):ha(a)}),da.fromList,da.toList,da.toFloat,da.toInt,da.indexes,da.indexes,da.endsWith,da.startsWith,da.contains,da.all,da.any,da.toLower,da.toUpper,da.lines,da.words,da.trimRight,da.trimLeft,da.trim,da.padRight,da.padLeft,da.pad,da.dropRight,da.dropLeft,da.right,da.left,da.slice,da.repeat,da.join),la=(da.split,da.foldr,da.foldl,da.reverse,da.filter,da.map,da.length,da.concat),ma=(da.append,da.uncons,da.cons,da.isEmpty,b(function(a,b,c){for(;;){var d=c;if("RBEmpty_elm_builtin"===d.ctor)return b;var e=a,f=j(a,d._1,d._2,j(ma,a,b,d._4)),g=d._3;a=e,b=f,c=g}})),na=function(a){return j(ma,b(function(a,b,c){return{ctor:"::",_0:{ctor:"_Tuple2",_0:a,_1:b},_1:c}}),{ctor:"[]"},a)},oa=b(function(a,b,c){for(;;){var d=c;if("RBEmpty_elm_builtin"===d.ctor)return b;var e=a,f=j(a,d._1,d._2,j(oa,a,b,d._3)),g=d._4;a=e,b=f,c=g}}),pa=e(function(c,d,e,f,g,h){var i=b(function(a,b,f){for(;;){var g=f,h=g._1,i=g._0,l=i;if("[]"===l.ctor)return{ctor:"_Tuple2",_0:i,_1:j(e,a,b,h)};var m=l._1,n=l._0._1,o=l._0._0;if(!(p.cmp(o,a)<0))return p.cmp(o,a)>0?{ctor:"_Tuple2",_0:i,_1:j(e,a,b,h)}:{ctor:"_Tuple2",_0:m,_1:k(d,o,n,b,h)};var q=a,r=b,s={ctor:"_Tuple2",_0:m,_1:j(c,o,n,h)};a=q,b=r,f=s}}),l=j(oa,i,{ctor:"_Tuple2",_0:na(f),_1:h},g),m=l._0,n=l._1;return j(I,a(function(a,b){var d=a;return j(c,d._0,d._1,b)}),n,m)}),qa=c(function(a,b,c,d){return ca.crash(la({ctor:"::",_0:"Internal red-black tree invariant violated, expected ",_1:{ctor:"::",_0:a,_1:{ctor:"::",_0:" and got ",_1:{ctor:"::",_0:t(b),_1:{ctor:"::",_0:"/",_1:{ctor:"::",_0:c,_1:{ctor:"::",_0:"/",_1:{ctor:"::",_0:d,_1:{ctor:"::",_0:"\nPlease report this bug to <https://github.com/elm-lang/core/issues>",_1:{ctor:"[]"}}}}}}}}}}))}),ra=function(a){var b=a;a:do{if("RBNode_elm_builtin"===b.ctor){if("BBlack"===b._0.ctor)return!0;break a}if("LBBlack"===b._0.ctor)return!0;break a}while(!1);return!1},sa=a(function(a,b){for(;;){var c=b;if("RBEmpty_elm_builtin"===c.ctor)return a;var d=i(sa,a+1,c._4),e=c._3;a=d,b=e}}),ta=a(function(a,b){a:for(;;){var c=b;if("RBEmpty_elm_builtin"===c.ctor)return B;var d=i(x,a,c._1);switch(d.ctor){case"LT":var e=a,f=c._3;a=e,b=f;continue a;case"EQ":return C(c._2);default:var g=a,h=c._4;a=g,b=h;continue a}}}),ua=a(function(a,b){var c=i(ta,a,b);return"Just"===c.ctor?!0:!1}),va=b(function(a,b,c){for(;;){var d=c;if("RBEmpty_elm_builtin"===d.ctor)return{ctor:"_Tuple2",_0:a,_1:b};var e=d._1,f=d._2,g=d._4;a=e,b=f,c=g}}),wa={ctor:"NBlack"},xa={ctor:"BBlack"},ya={ctor:"Black"},za=function(a){var b=a;if("RBNode_elm_builtin"===b.ctor){var c=b._0;return p.eq(c,ya)||p.eq(c,xa)}return!0},Aa={ctor:"Red"},Ba=function(a){var b=a;switch(b.ctor){case"Black":return xa;case"Red":return ya;case"NBlack":return Aa;default:return ca.crash("Can't make a double black node more black!")}},Ca=function(a){var b=a;switch(b.ctor){case"BBlack":return ya;case"Black":return Aa;case"Red":return wa;default:return ca.crash("Can't make a negative black node less black!")}},Da={ctor:"LBBlack"},Ea={ctor:"LBlack"},Fa=function(a){return{ctor:"RBEmpty_elm_builtin",_0:a}},Ga=Fa(Ea),Ha=d(function(a,b,c,d,e){return{ctor:"RBNode_elm_builtin",_0:a,_1:b,_2:c,_3:d,_4:e}}),Ia=function(a){var b=a;return"RBNode_elm_builtin"===b.ctor&&"Red"===b._0.ctor?l(Ha,ya,b._1,b._2,b._3,b._4):a},Ja=function(a){var b=a;return"RBNode_elm_builtin"===b.ctor?l(Ha,Ca(b._0),b._1,b._2,b._3,b._4):Fa(Ea)},Ka=function(a){return function(b){return function(c){return function(d){return function(e){return function(f){return function(g){return function(h){return function(i){return function(j){return function(k){return l(Ha,Ca(a),d,e,l(Ha,ya,b,c,h,i),l(Ha,ya,f,g,j,k))}}}}}}}}}}},La=function(a){var b=a;return"RBEmpty_elm_builtin"===b.ctor?Fa(Ea):l(Ha,ya,b._1,b._2,b._3,b._4)},Ma=function(a){var b=a;return"RBEmpty_elm_builtin"===b.ctor?ca.crash("can't make a Leaf red"):l(Ha,Aa,b._1,b._2,b._3,b._4)},Na=function(a){
 var b=a;a:do{b:do{c:do{d:do{e:do{f:do{g:do{if("RBNode_elm_builtin"!==b.ctor)break a;if("RBNode_elm_builtin"===b._3.ctor)if("RBNode_elm_builtin"===b._4.ctor)switch(b._3._0.ctor){case"Red":switch(b._4._0.ctor){case"Red":if("RBNode_elm_builtin"===b._3._3.ctor&&"Red"===b._3._3._0.ctor)break g;if("RBNode_elm_builtin"===b._3._4.ctor&&"Red"===b._3._4._0.ctor)break f;if("RBNode_elm_builtin"===b._4._3.ctor&&"Red"===b._4._3._0.ctor)break e;if("RBNode_elm_builtin"===b._4._4.ctor&&"Red"===b._4._4._0.ctor)break d;break a;case"NBlack":if("RBNode_elm_builtin"===b._3._3.ctor&&"Red"===b._3._3._0.ctor)break g;if("RBNode_elm_builtin"===b._3._4.ctor&&"Red"===b._3._4._0.ctor)break f;if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._4._3.ctor&&"Black"===b._4._3._0.ctor&&"RBNode_elm_builtin"===b._4._4.ctor&&"Black"===b._4._4._0.ctor)break c;break a;default:if("RBNode_elm_builtin"===b._3._3.ctor&&"Red"===b._3._3._0.ctor)break g;if("RBNode_elm_builtin"===b._3._4.ctor&&"Red"===b._3._4._0.ctor)break f;break a}case"NBlack":switch(b._4._0.ctor){case"Red":if("RBNode_elm_builtin"===b._4._3.ctor&&"Red"===b._4._3._0.ctor)break e;if("RBNode_elm_builtin"===b._4._4.ctor&&"Red"===b._4._4._0.ctor)break d;if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._3._3.ctor&&"Black"===b._3._3._0.ctor&&"RBNode_elm_builtin"===b._3._4.ctor&&"Black"===b._3._4._0.ctor)break b;break a;case"NBlack":if("BBlack"===b._0.ctor){if("RBNode_elm_builtin"===b._4._3.ctor&&"Black"===b._4._3._0.ctor&&"RBNode_elm_builtin"===b._4._4.ctor&&"Black"===b._4._4._0.ctor)break c;if("RBNode_elm_builtin"===b._3._3.ctor&&"Black"===b._3._3._0.ctor&&"RBNode_elm_builtin"===b._3._4.ctor&&"Black"===b._3._4._0.ctor)break b;break a}break a;default:if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._3._3.ctor&&"Black"===b._3._3._0.ctor&&"RBNode_elm_builtin"===b._3._4.ctor&&"Black"===b._3._4._0.ctor)break b;break a}default:switch(b._4._0.ctor){case"Red":if("RBNode_elm_builtin"===b._4._3.ctor&&"Red"===b._4._3._0.ctor)break e;if("RBNode_elm_builtin"===b._4._4.ctor&&"Red"===b._4._4._0.ctor)break d;break a;case"NBlack":if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._4._3.ctor&&"Black"===b._4._3._0.ctor&&"RBNode_elm_builtin"===b._4._4.ctor&&"Black"===b._4._4._0.ctor)break c;break a;default:break a}}else switch(b._3._0.ctor){case"Red":if("RBNode_elm_builtin"===b._3._3.ctor&&"Red"===b._3._3._0.ctor)break g;if("RBNode_elm_builtin"===b._3._4.ctor&&"Red"===b._3._4._0.ctor)break f;break a;case"NBlack":if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._3._3.ctor&&"Black"===b._3._3._0.ctor&&"RBNode_elm_builtin"===b._3._4.ctor&&"Black"===b._3._4._0.ctor)break b;break a;default:break a}else{if("RBNode_elm_builtin"!==b._4.ctor)break a;switch(b._4._0.ctor){case"Red":if("RBNode_elm_builtin"===b._4._3.ctor&&"Red"===b._4._3._0.ctor)break e;if("RBNode_elm_builtin"===b._4._4.ctor&&"Red"===b._4._4._0.ctor)break d;break a;case"NBlack":if("BBlack"===b._0.ctor&&"RBNode_elm_builtin"===b._4._3.ctor&&"Black"===b._4._3._0.ctor&&"RBNode_elm_builtin"===b._4._4.ctor&&"Black"===b._4._4._0.ctor)break c;break a;default:break a}}}while(!1);return Ka(b._0)(b._3._3._1)(b._3._3._2)(b._3._1)(b._3._2)(b._1)(b._2)(b._3._3._3)(b._3._3._4)(b._3._4)(b._4)}while(!1);return Ka(b._0)(b._3._1)(b._3._2)(b._3._4._1)(b._3._4._2)(b._1)(b._2)(b._3._3)(b._3._4._3)(b._3._4._4)(b._4)}while(!1);return Ka(b._0)(b._1)(b._2)(b._4._3._1)(b._4._3._2)(b._4._1)(b._4._2)(b._3)(b._4._3._3)(b._4._3._4)(b._4._4)}while(!1);return Ka(b._0)(b._1)(b._2)(b._4._1)(b._4._2)(b._4._4._1)(b._4._4._2)(b._3)(b._4._3)(b._4._4._3)(b._4._4._4)}while(!1);return l(Ha,ya,b._4._3._1,b._4._3._2,l(Ha,ya,b._1,b._2,b._3,b._4._3._3),l(Oa,ya,b._4._1,b._4._2,b._4._3._4,Ma(b._4._4)))}while(!1);return l(Ha,ya,b._3._4._1,b._3._4._2,l(Oa,ya,b._3._1,b._3._2,Ma(b._3._3),b._3._4._3),l(Ha,ya,b._1,b._2,b._3._4._4,b._4))}while(!1);return a},Oa=d(function(a,b,c,d,e){var f=l(Ha,a,b,c,d,e);return za(f)?Na(f):f}),Pa=d(function(a,b,c,d,e){return ra(d)||ra(e)?l(Oa,Ba(a),b,c,Ja(d),Ja(e)):l(Ha,a,b,c,d,e)}),Qa=d(function(a,b,c,d,e){var f=e;return"RBEmpty_elm_builtin"===f.ctor?j(Ra,a,d,e):l(Pa,a,b,c,d,l(Qa,f._0,f._1,f._2,f._3,f._4))}),Ra=b(function(a,b,c){var d={ctor:"_Tuple2",_0:b,_1:c};if("RBEmpty_elm_builtin"!==d._0.ctor){if("RBEmpty_elm_builtin"===d._1.ctor){var e=d._1._0,f=d._0._0,g={ctor:"_Tuple3",_0:a,_1:f,_2:e};return"_Tuple3"===g.ctor&&"Black"===g._0.ctor&&"Red"===g._1.ctor&&"LBlack"===g._2.ctor?l(Ha,ya,d._0._1,d._0._2,d._0._3,d._0._4):k(qa,"Black/Red/LBlack",a,t(f),t(e))}var h=d._0._2,i=d._0._4,m=d._0._1,n=l(Qa,d._0._0,m,h,d._0._3,i),o=j(va,m,h,i),p=o._0,q=o._1;return l(Pa,a,p,q,n,c)}if("RBEmpty_elm_builtin"!==d._1.ctor){var r=d._1._0,s=d._0._0,u={ctor:"_Tuple3",_0:a,_1:s,_2:r};return"_Tuple3"===u.ctor&&"Black"===u._0.ctor&&"LBlack"===u._1.ctor&&"Red"===u._2.ctor?l(Ha,ya,d._1._1,d._1._2,d._1._3,d._1._4):k(qa,"Black/LBlack/Red",a,t(s),t(r))}var v=a;switch(v.ctor){case"Red":return Fa(Ea);case"Black":return Fa(Da);default:return ca.crash("cannot have bblack or nblack nodes at this point")}}),Sa=a(function(a,b){var c=b;if("RBEmpty_elm_builtin"===c.ctor)return Fa(Ea);var d=c._1;return l(Ha,c._0,d,i(a,d,c._2),i(Sa,a,c._3),i(Sa,a,c._4))}),Ta={ctor:"Same"},Ua={ctor:"Remove"},Va={ctor:"Insert"},Wa=b(function(a,b,c){var d=function(c){var e=c;if("RBEmpty_elm_builtin"===e.ctor){var f=b(B);return"Nothing"===f.ctor?{ctor:"_Tuple2",_0:Ta,_1:Ga}:{ctor:"_Tuple2",_0:Va,_1:l(Ha,Aa,a,f._0,Ga,Ga)}}var g=e._2,h=e._4,k=e._3,m=e._1,n=e._0,o=i(x,a,m);switch(o.ctor){case"EQ":var p=b(C(g));return"Nothing"===p.ctor?{ctor:"_Tuple2",_0:Ua,_1:j(Ra,n,k,h)}:{ctor:"_Tuple2",_0:Ta,_1:l(Ha,n,m,p._0,k,h)};case"LT":var q=d(k),r=q._0,s=q._1,t=r;switch(t.ctor){case"Same":return{ctor:"_Tuple2",_0:Ta,_1:l(Ha,n,m,g,s,h)};case"Insert":return{ctor:"_Tuple2",_0:Va,_1:l(Oa,n,m,g,s,h)};default:return{ctor:"_Tuple2",_0:Ua,_1:l(Pa,n,m,g,s,h)}}default:var u=d(h),r=u._0,v=u._1,w=r;switch(w.ctor){case"Same":return{ctor:"_Tuple2",_0:Ta,_1:l(Ha,n,m,g,k,v)};case"Insert":return{ctor:"_Tuple2",_0:Va,_1:l(Oa,n,m,g,k,v)};default:return{ctor:"_Tuple2",_0:Ua,_1:l(Pa,n,m,g,k,v)}}}},e=d(c),f=e._0,g=e._1,h=f;switch(h.ctor){case"Same":return g;case"Insert":return Ia(g);default:return La(g)}}),Xa=b(function(a,b,c){return j(Wa,a,q(C(b)),c)}),Ya=(a(function(a,b){return j(Xa,a,b,Ga)}),a(function(a,b){return j(oa,Xa,b,a)})),Za=a(function(a,c){var d=b(function(b,c,d){return i(a,b,c)?j(Xa,b,c,d):d});return j(oa,d,Ga,c)}),$a=(a(function(b,c){return i(Za,a(function(a,b){return i(ua,a,c)}),b)}),a(function(a,c){var d=b(function(b,c,d){var e=d,f=e._1,g=e._0;return i(a,b,c)?{ctor:"_Tuple2",_0:j(Xa,b,c,g),_1:f}:{ctor:"_Tuple2",_0:g,_1:j(Xa,b,c,f)}});return j(oa,d,{ctor:"_Tuple2",_0:Ga,_1:Ga},c)}),function(b){return j(I,a(function(a,b){var c=a;return j(Xa,c._0,c._1,b)}),Ga,b)}),_a=a(function(a,b){return j(Wa,a,q(B),b)}),ab=(a(function(a,c){return j(oa,b(function(a,b,c){return i(_a,a,c)}),a,c)}),function(){function i(a){return{ctor:"<decoder>",tag:"succeed",msg:a}}function j(a){return{ctor:"<decoder>",tag:"fail",msg:a}}function k(a){return{ctor:"<decoder>",tag:a}}function l(a,b){return{ctor:"<decoder>",tag:a,decoder:b}}function m(a){return{ctor:"<decoder>",tag:"null",value:a}}function o(a,b){return{ctor:"<decoder>",tag:"field",field:a,decoder:b}}function q(a,b){return{ctor:"<decoder>",tag:"index",index:a,decoder:b}}function r(a){return{ctor:"<decoder>",tag:"key-value",decoder:a}}function s(a,b){return{ctor:"<decoder>",tag:"map-many",func:a,decoders:b}}function t(a,b){return{ctor:"<decoder>",tag:"andThen",decoder:b,callback:a}}function u(a){return{ctor:"<decoder>",tag:"oneOf",decoders:a}}function v(a,b){return s(a,[b])}function w(a,b,c){return s(a,[b,c])}function x(a,b,c,d){return s(a,[b,c,d])}function y(a,b,c,d,e){return s(a,[b,c,d,e])}function z(a,b,c,d,e,f){return s(a,[b,c,d,e,f])}function A(a,b,c,d,e,f,g){return s(a,[b,c,d,e,f,g])}function E(a,b,c,d,e,f,g,h){return s(a,[b,c,d,e,f,g,h])}function F(a,b,c,d,e,f,g,h,i){return s(a,[b,c,d,e,f,g,h,i])}function G(a){return{tag:"ok",value:a}}function H(a,b){return{tag:"primitive",type:a,value:b}}function I(a,b){return{tag:"index",index:a,rest:b}}function J(a,b){return{tag:"field",field:a,rest:b}}function I(a,b){return{tag:"index",index:a,rest:b}}function K(a){return{tag:"oneOf",problems:a}}function L(a){return{tag:"fail",msg:a}}function M(a){for(var b="_";a;)switch(a.tag){case"primitive":return"Expecting "+a.type+("_"===b?"":" at "+b)+" but instead got: "+N(a.value);case"index":b+="["+a.index+"]",a=a.rest;break;case"field":b+="."+a.field,a=a.rest;break;case"oneOf":for(var c=a.problems,d=0;d<c.length;d++)c[d]=M(c[d]);return"I ran into the following problems"+("_"===b?"":" at "+b)+":\n\n"+c.join("\n");case"fail":return"I ran into a `fail` decoder"+("_"===b?"":" at "+b)+": "+a.msg}}function N(a){return void 0===a?"undefined":JSON.stringify(a)}function O(a,b){var c;try{c=JSON.parse(b)}catch(d){return ha("Given an invalid JSON: "+d.message)}return P(a,c)}function P(a,b){var c=Q(a,b);return"ok"===c.tag?ia(c.value):ha(M(c))}function Q(a,b){switch(a.tag){case"bool":return"boolean"==typeof b?G(b):H("a Bool",b);case"int":return"number"!=typeof b?H("an Int",b):b>-2147483647&&2147483647>b&&(0|b)===b?G(b):!isFinite(b)||b%1?H("an Int",b):G(b);case"float":return"number"==typeof b?G(b):H("a Float",b);case"string":return"string"==typeof b?G(b):b instanceof String?G(b+""):H("a String",b);case"null":return null===b?G(a.value):H("null",b);case"value":return G(b);case"list":if(!(b instanceof Array))return H("a List",b);for(var c=D.Nil,d=b.length;d--;){var e=Q(a.decoder,b[d]);if("ok"!==e.tag)return I(d,e);c=D.Cons(e.value,c)}return G(c);case"array":if(!(b instanceof Array))return H("an Array",b);for(var f=b.length,g=new Array(f),d=f;d--;){var e=Q(a.decoder,b[d]);if("ok"!==e.tag)return I(d,e);g[d]=e.value}return G(n.fromJSArray(g));case"maybe":var e=Q(a.decoder,b);return G("ok"===e.tag?C(e.value):B);case"field":var h=a.field;if("object"!=typeof b||null===b||!(h in b))return H("an object with a field named `"+h+"`",b);var e=Q(a.decoder,b[h]);return"ok"===e.tag?e:J(h,e);case"index":var i=a.index;if(!(b instanceof Array))return H("an array",b);if(i>=b.length)return H("a longer array. Need index "+i+" but there are only "+b.length+" entries",b);var e=Q(a.decoder,b[i]);return"ok"===e.tag?e:I(i,e);case"key-value":if("object"!=typeof b||null===b||b instanceof Array)return H("an object",b);var j=D.Nil;for(var k in b){var e=Q(a.decoder,b[k]);if("ok"!==e.tag)return J(k,e);var l=p.Tuple2(k,e.value);j=D.Cons(l,j)}return G(j);case"map-many":for(var m=a.func,o=a.decoders,d=0;d<o.length;d++){var e=Q(o[d],b);if("ok"!==e.tag)return e;m=m(e.value)}return G(m);case"andThen":var e=Q(a.decoder,b);return"ok"!==e.tag?e:Q(a.callback(e.value),b);case"oneOf":for(var q=[],r=a.decoders;"[]"!==r.ctor;){var e=Q(r._0,b);if("ok"===e.tag)return e;q.push(e),r=r._1}return K(q);case"fail":return L(a.msg);case"succeed":return G(a.msg)}}function R(a,b){if(a===b)return!0;if(a.tag!==b.tag)return!1;switch(a.tag){case"succeed":case"fail":return a.msg===b.msg;case"bool":case"int":case"float":case"string":case"value":return!0;case"null":return a.value===b.value;case"list":case"array":case"maybe":case"key-value":return R(a.decoder,b.decoder);case"field":return a.field===b.field&&R(a.decoder,b.decoder);case"index":return a.index===b.index&&R(a.decoder,b.decoder);case"map-many":return a.func!==b.func?!1:S(a.decoders,b.decoders);case"andThen":return a.callback===b.callback&&R(a.decoder,b.decoder);case"oneOf":return S(a.decoders,b.decoders)}}function S(a,b){var c=a.length;if(c!==b.length)return!1;for(var d=0;c>d;d++)if(!R(a[d],b[d]))return!1;return!0}function T(a,b){return JSON.stringify(b,null,a)}function U(a){return a}function V(a){for(var b={};"[]"!==a.ctor;){var c=a._0;b[c._0]=c._1,a=a._1}return b}return{encode:a(T),runOnString:a(O),run:a(P),decodeNull:m,decodePrimitive:k,decodeContainer:a(l),decodeField:a(o),decodeIndex:a(q),map1:a(v),map2:b(w),map3:c(x),map4:d(y),map5:e(z),map6:f(A),map7:g(E),map8:h(F),decodeKeyValuePairs:r,andThen:a(t),fail:j,succeed:i,oneOf:u,identity:U,encodeNull:null,encodeArray:n.toJSArray,encodeList:D.toArray,encodeObject:V,equality:R}}()),bb=ab.encodeList,cb=(ab.encodeArray,ab.encodeObject),db=(ab.encodeNull,ab.identity),eb=(ab.identity,ab.identity),fb=ab.identity,gb=ab.encode,hb=ab.decodeNull,ib=ab.decodePrimitive("value"),jb=ab.andThen,kb=ab.fail,lb=ab.succeed,mb=ab.run,nb=ab.runOnString,ob=(ab.map8,ab.map7,ab.map6,ab.map5,ab.map4),pb=ab.map3,qb=ab.map2,rb=ab.map1,sb=ab.oneOf,tb=(ab.decodeIndex,ab.decodeField),ub=a(function(a,b){return j(H,tb,b,a)}),vb=(ab.decodeKeyValuePairs,function(a){return i(ab.decodeContainer,"list",a)}),wb=(ab.decodePrimitive("float"),ab.decodePrimitive("int")),xb=ab.decodePrimitive("bool"),yb=ab.decodePrimitive("string"),zb=(ca.crash,ca.log,a(function(a,b){var c=b;return{ctor:"_Tuple2",_0:c._0,_1:a(c._1)}}),a(function(a,b){var c=b;return{ctor:"_Tuple2",_0:a(c._0),_1:c._1}}),function(){function c(a){return function(b){return function(b,c){b.worker=function(b){if("undefined"!=typeof b)throw new Error("The `"+c+"` module does not need flags.\nCall "+c+".worker() with no arguments and you should be all set!");return g(a.init,a.update,a.subscriptions,e)}}}}function d(a){return function(b){return function(c,d){c.worker=function(c){if("undefined"==typeof b)throw new Error("Are you trying to sneak a Never value into Elm? Trickster!\nIt looks like "+d+".main is defined with `programWithFlags` but has type `Program Never`.\nUse `program` instead if you do not want flags.");var f=i(ab.run,b,c);if("Err"===f.ctor)throw new Error(d+".worker(...) was called with an unexpected argument.\nI tried to convert it to an Elm value, but ran into this problem:\n\n"+f._0);return g(a.init(f._0),a.update,a.subscriptions,e)}}}}function e(a,b){return function(a){}}function f(b){var c=r(D.Nil),d=p.Tuple2(p.Tuple0,c);return Rb({init:d,view:function(a){return main},update:a(function(a,b){return d}),subscriptions:function(a){return c}})}function g(a,b,c,d){function e(a,d){return Ab.nativeBinding(function(e){var f=i(b,a,d);d=f._0,g(d);var h=f._1,k=c(d);t(j,h,k),e(Ab.succeed(d))})}function f(a){Ab.rawSend(l,a)}var g,j={},k=Ab.nativeBinding(function(b){var e=a._0;g=d(f,e);var h=a._1,i=c(e);t(j,h,i),b(Ab.succeed(e))}),l=o(k,e),m=h(j,f);return m?{ports:m}:{}}function h(a,b){var c;for(var d in C){var e=C[d];e.isForeign&&(c=c||{},c[d]="cmd"===e.tag?z(d):B(d,b)),a[d]=l(e,b)}return c}function l(a,b){function c(a,b){if("self"===a.ctor)return j(g,d,a._0,b);var c=a._0;switch(e){case"cmd":return j(f,d,c.cmds,b);case"sub":return j(f,d,c.subs,b);case"fx":return k(f,d,c.cmds,c.subs,b)}}var d={main:b,self:void 0},e=a.tag,f=a.onEffects,g=a.onSelfMsg,h=o(a.init,c);return d.self=h,h}function m(a,b){return Ab.nativeBinding(function(c){a.main(b),c(Ab.succeed(p.Tuple0))})}function n(a,b){return i(Ab.send,a.self,{ctor:"self",_0:b})}function o(a,b){function c(a){var e=Ab.receive(function(c){return b(c,a)});return i(d,c,e)}var d=Ab.andThen,e=i(d,c,a);return Ab.rawSpawn(e)}function q(a){return function(b){return{type:"leaf",home:a,value:b}}}function r(a){return{type:"node",branches:a}}function s(a,b){return{type:"map",tagger:a,tree:b}}function t(a,b,c){var d={};u(!0,b,d,null),u(!1,c,d,null);for(var e in a){var f=e in d?d[e]:{cmds:D.Nil,subs:D.Nil};Ab.rawSend(a[e],{ctor:"fx",_0:f})}}function u(a,b,c,d){switch(b.type){case"leaf":var e=b.home,f=v(a,e,d,b.value);return void(c[e]=w(a,f,c[e]));case"node":for(var g=b.branches;"[]"!==g.ctor;)u(a,g._0,c,d),g=g._1;return;case"map":return void u(a,b.tree,c,{tagger:b.tagger,rest:d})}}function v(a,b,c,d){function e(a){for(var b=c;b;)a=b.tagger(a),b=b.rest;return a}var f=a?C[b].cmdMap:C[b].subMap;return i(f,e,d)}function w(a,b,c){return c=c||{cmds:D.Nil,subs:D.Nil},a?(c.cmds=D.Cons(b,c.cmds),c):(c.subs=D.Cons(b,c.subs),c)}function x(a){if(a in C)throw new Error("There can only be one port named `"+a+"`, but your program has multiple.")}function y(a,b){return x(a),C[a]={tag:"cmd",cmdMap:E,converter:b,isForeign:!0},q(a)}function z(a){function c(a,b,c){for(;"[]"!==b.ctor;){for(var d=f,e=g(b._0),i=0;i<d.length;i++)d[i](e);b=b._1}return h}function d(a){f.push(a)}function e(a){f=f.slice();var b=f.indexOf(a);b>=0&&f.splice(b,1)}var f=[],g=C[a].converter,h=Ab.succeed(null);return C[a].init=h,C[a].onEffects=b(c),{subscribe:d,unsubscribe:e}}function A(a,b){return x(a),C[a]={tag:"sub",subMap:F,converter:b,isForeign:!0},q(a)}function B(a,c){function d(a,b,c){for(var d=e(a,b,c),f=0;f<k.length;f++)h(k[f]);return k=null,o=h,n=e,d}function e(a,b,c){return l=b,p}function f(a,b,c){return n(a,b,c)}function g(a){k.push(a)}function h(a){for(var b=l;"[]"!==b.ctor;)c(b._0(a)),b=b._1}function j(b){var c=i(mb,m,b);if("Err"===c.ctor)throw new Error("Trying to send an unexpected type of value through port `"+a+"`:\n"+c._0);o(c._0)}var k=[],l=D.Nil,m=C[a].converter,n=d,o=g,p=Ab.succeed(null);return C[a].init=p,C[a].onEffects=b(f),{send:j}}var C={},E=a(function(a,b){return b}),F=a(function(a,b){return function(c){return a(b(c))}});return{sendToApp:a(m),sendToSelf:a(n),effectManagers:C,outgoingPort:y,incomingPort:A,htmlToProgram:f,program:c,programWithFlags:d,initialize:g,leaf:q,batch:r,map:a(s)}}()),Ab=function(){function b(a){return{ctor:"_Task_succeed",value:a}}function c(a){return{ctor:"_Task_fail",value:a}}function d(a){return{ctor:"_Task_nativeBinding",callback:a,cancel:null}}function e(a,b){return{ctor:"_Task_andThen",callback:a,task:b}}function f(a,b){return{ctor:"_Task_onError",callback:a,task:b}}function g(a){return{ctor:"_Task_receive",callback:a}}function h(a){var b={ctor:"_Process",id:p.guid(),root:a,stack:null,mailbox:[]};return o(b),b}function i(a){return d(function(c){var d=h(a);c(b(d))})}function j(a,b){a.mailbox.push(b),o(a)}function k(a,c){return d(function(d){j(a,c),d(b(p.Tuple0))})}function l(a){return d(function(c){var d=a.root;"_Task_nativeBinding"===d.ctor&&d.cancel&&d.cancel(),a.root=null,c(b(p.Tuple0))})}function m(a){return d(function(c){var d=setTimeout(function(){c(b(p.Tuple0))},a);return function(){clearTimeout(d)}})}function n(a,b){for(;r>a;){var c=b.root.ctor;if("_Task_succeed"!==c)if("_Task_fail"!==c)if("_Task_andThen"!==c)if("_Task_onError"!==c){if("_Task_nativeBinding"===c){b.root.cancel=b.root.callback(function(a){b.root=a,o(b)});break}if("_Task_receive"!==c)throw new Error(c);var d=b.mailbox;if(0===d.length)break;b.root=b.root.callback(d.shift()),++a}else b.stack={ctor:"_Task_onError",callback:b.root.callback,rest:b.stack},b.root=b.root.task,++a;else b.stack={ctor:"_Task_andThen",callback:b.root.callback,rest:b.stack},b.root=b.root.task,++a;else{for(;b.stack&&"_Task_andThen"===b.stack.ctor;)b.stack=b.stack.rest;if(null===b.stack)break;b.root=b.stack.callback(b.root.value),b.stack=b.stack.rest,++a}else{for(;b.stack&&"_Task_onError"===b.stack.ctor;)b.stack=b.stack.rest;if(null===b.stack)break;b.root=b.stack.callback(b.root.value),b.stack=b.stack.rest,++a}}return r>a?a+1:(o(b),a)}function o(a){t.push(a),s||(setTimeout(q,0),s=!0)}function q(){for(var a,b=0;r>b&&(a=t.shift());)a.root&&(b=n(b,a));return a?void setTimeout(q,0):void(s=!1)}var r=1e4,s=!1,t=[];return{succeed:b,fail:c,nativeBinding:d,andThen:a(e),onError:a(f),receive:g,spawn:i,kill:l,sleep:m,send:a(k),rawSpawn:h,rawSend:j}}(),Bb=zb.batch,Cb=Bb({ctor:"[]"}),Db=Db||{};Db["!"]=a(function(a,b){return{ctor:"_Tuple2",_0:a,_1:Bb(b)}});var Eb,Fb,Gb=(zb.map,zb.batch),Hb=Gb({ctor:"[]"}),Ib=(zb.map,Ab.succeed,zb.sendToSelf),Jb=zb.sendToApp,Kb=(zb.programWithFlags,zb.program,lb),Lb=(jb(r),qb(a(function(a,b){return b(a)}))),Mb=b(function(a,b,c){var d=function(a){return sb({ctor:"::",_0:a,_1:{ctor:"::",_0:hb(c),_1:{ctor:"[]"}}})},e=function(e){var f=i(mb,a,e);if("Ok"===f.ctor){var g=i(mb,d(b),f._0);return"Ok"===g.ctor?lb(g._0):kb(g._0)}return lb(c)};return i(jb,e,ib)}),Nb=(c(function(a,b,c,d){return i(Lb,j(Mb,i(ub,a,ib),b,c),d)}),c(function(a,b,c,d){return i(Lb,j(Mb,i(tb,a,ib),b,c),d)})),Ob=(b(function(a,b,c){return i(Lb,i(ub,a,b),c)}),b(function(a,b,c){return i(Lb,i(tb,a,b),c)})),Pb=function(){function d(a){return{type:"text",text:a}}function e(b){return a(function(a,c){return f(b,a,c)})}function f(a,b,c){for(var d=q(b),e=d.namespace,f=d.facts,g=[],h=0;"[]"!==c.ctor;){var i=c._0;h+=i.descendantsCount||0,g.push(i),c=c._1}return h+=g.length,{type:"node",tag:a,facts:f,children:g,namespace:e,descendantsCount:h}}function g(a,b,c){for(var d=q(b),e=d.namespace,f=d.facts,g=[],h=0;"[]"!==c.ctor;){var i=c._0;h+=i._1.descendantsCount||0,g.push(i),c=c._1}return h+=g.length,{type:"keyed-node",tag:a,facts:f,children:g,namespace:e,descendantsCount:h}}function h(a,b,c){var d=q(a).facts;return{type:"custom",facts:d,model:b,impl:c}}function k(a,b){return{type:"tagger",tagger:a,node:b,descendantsCount:1+(b.descendantsCount||0)}}function l(a,b,c){return{type:"thunk",func:a,args:b,thunk:c,node:void 0}}function m(a,b){return l(a,[b],function(){return a(b)})}function n(a,b,c){return l(a,[b,c],function(){return i(a,b,c)})}function o(a,b,c,d){return l(a,[b,c,d],function(){return j(a,b,c,d)})}function q(a){for(var b,c={};"[]"!==a.ctor;){var d=a._0,e=d.key;if(e===na||e===oa||e===ma){var f=c[e]||{};f[d.realKey]=d.value,c[e]=f}else if(e===la){for(var g=c[e]||{},h=d.value;"[]"!==h.ctor;){var i=h._0;g[i._0]=i._1,h=h._1}c[e]=g}else if("namespace"===e)b=d.value;else if("className"===e){var j=c[e];c[e]="undefined"==typeof j?d.value:j+" "+d.value}else c[e]=d.value;a=a._1}return{facts:c,namespace:b}}function r(a){return{key:la,value:a}}function s(a,b){return{key:a,value:b}}function t(a,b){return{key:na,realKey:a,value:b}}function u(a,b,c){return{key:oa,realKey:b,value:{value:c,namespace:a}}}function v(a,b,c){return{key:ma,realKey:a,value:{options:b,decoder:c}}}function w(a,b){return a.options===b.options||a.options.stopPropagation===b.options.stopPropagation&&a.options.preventDefault===b.options.preventDefault?ab.equality(a.decoder,b.decoder):!1}function x(a,b){return b.key!==ma?b:v(b.realKey,b.value.options,i(rb,a,b.value.decoder))}function y(a,b){switch(a.type){case"thunk":return a.node||(a.node=a.thunk()),y(a.node,b);case"tagger":for(var c=a.node,d=a.tagger;"tagger"===c.type;)"object"!=typeof d?d=[d,c.tagger]:d.push(c.tagger),c=c.node;var e={tagger:d,parent:b},f=y(c,e);return f.elm_event_node_ref=e,f;case"text":return pa.createTextNode(a.text);case"node":var f=a.namespace?pa.createElementNS(a.namespace,a.tag):pa.createElement(a.tag);z(f,b,a.facts);for(var g=a.children,h=0;h<g.length;h++)f.appendChild(y(g[h],b));return f;case"keyed-node":var f=a.namespace?pa.createElementNS(a.namespace,a.tag):pa.createElement(a.tag);z(f,b,a.facts);for(var g=a.children,h=0;h<g.length;h++)f.appendChild(y(g[h]._1,b));return f;case"custom":var f=a.impl.render(a.model);return z(f,b,a.facts),f}}function z(a,b,c){for(var d in c){var e=c[d];switch(d){case la:A(a,e);break;case ma:B(a,b,e);break;case na:D(a,e);break;case oa:E(a,e);break;case"value":a[d]!==e&&(a[d]=e);break;default:a[d]=e}}}function A(a,b){var c=a.style;for(var d in b)c[d]=b[d]}function B(a,b,c){var d=a.elm_handlers||{};for(var e in c){var f=d[e],g=c[e];if("undefined"==typeof g)a.removeEventListener(e,f),d[e]=void 0;else if("undefined"==typeof f){var f=C(b,g);a.addEventListener(e,f),d[e]=f}else f.info=g}a.elm_handlers=d}function C(a,b){function c(b){var d=c.info,e=i(ab.run,d.decoder,b);if("Ok"===e.ctor){var f=d.options;f.stopPropagation&&b.stopPropagation(),f.preventDefault&&b.preventDefault();for(var g=e._0,h=a;h;){var j=h.tagger;if("function"==typeof j)g=j(g);else for(var k=j.length;k--;)g=j[k](g);h=h.parent}}}return c.info=b,c}function D(a,b){for(var c in b){var d=b[c];"undefined"==typeof d?a.removeAttribute(c):a.setAttribute(c,d)}}function E(a,b){for(var c in b){var d=b[c],e=d.namespace,f=d.value;"undefined"==typeof f?a.removeAttributeNS(e,c):a.setAttributeNS(e,c,f)}}function F(a,b){var c=[];return H(a,b,c,0),c}function G(a,b,c){return{index:b,type:a,data:c,domNode:void 0,eventNode:void 0}}function H(a,b,c,d){if(a!==b){var e=a.type,f=b.type;if(e!==f)return void c.push(G("p-redraw",d,b));switch(f){case"thunk":for(var g=a.args,h=b.args,i=g.length,j=a.func===b.func&&i===h.length;j&&i--;)j=g[i]===h[i];if(j)return void(b.node=a.node);b.node=b.thunk();var k=[];return H(a.node,b.node,k,0),void(k.length>0&&c.push(G("p-thunk",d,k)));case"tagger":for(var l=a.tagger,m=b.tagger,n=!1,o=a.node;"tagger"===o.type;)n=!0,"object"!=typeof l?l=[l,o.tagger]:l.push(o.tagger),o=o.node;for(var p=b.node;"tagger"===p.type;)n=!0,"object"!=typeof m?m=[m,p.tagger]:m.push(p.tagger),p=p.node;return n&&l.length!==m.length?void c.push(G("p-redraw",d,b)):((n?I(l,m):l===m)||c.push(G("p-tagger",d,m)),void H(o,p,c,d+1));case"text":if(a.text!==b.text)return void c.push(G("p-text",d,b.text));return;case"node":if(a.tag!==b.tag||a.namespace!==b.namespace)return void c.push(G("p-redraw",d,b));var q=J(a.facts,b.facts);return"undefined"!=typeof q&&c.push(G("p-facts",d,q)),void K(a,b,c,d);case"keyed-node":if(a.tag!==b.tag||a.namespace!==b.namespace)return void c.push(G("p-redraw",d,b));var q=J(a.facts,b.facts);return"undefined"!=typeof q&&c.push(G("p-facts",d,q)),void L(a,b,c,d);case"custom":if(a.impl!==b.impl)return void c.push(G("p-redraw",d,b));var q=J(a.facts,b.facts);"undefined"!=typeof q&&c.push(G("p-facts",d,q));var r=b.impl.diff(a,b);if(r)return void c.push(G("p-custom",d,r));return}}}function I(a,b){for(var c=0;c<a.length;c++)if(a[c]!==b[c])return!1;return!0}function J(a,b,c){var d;for(var e in a)if(e!==la&&e!==ma&&e!==na&&e!==oa)if(e in b){var f=a[e],g=b[e];f===g&&"value"!==e||c===ma&&w(f,g)||(d=d||{},d[e]=g)}else d=d||{},d[e]="undefined"==typeof c?"string"==typeof a[e]?"":null:c===la?"":c===ma||c===na?void 0:{namespace:a[e].namespace,value:void 0};else{var h=J(a[e],b[e]||{},e);h&&(d=d||{},d[e]=h)}for(var i in b)i in a||(d=d||{},d[i]=b[i]);return d}function K(a,b,c,d){var e=a.children,f=b.children,g=e.length,h=f.length;g>h?c.push(G("p-remove-last",d,g-h)):h>g&&c.push(G("p-append",d,f.slice(g)));for(var i=d,j=h>g?g:h,k=0;j>k;k++){i++;var l=e[k];H(l,f[k],c,i),i+=l.descendantsCount||0}}function L(a,b,c,d){for(var e=[],f={},g=[],h=a.children,i=b.children,j=h.length,k=i.length,l=0,m=0,n=d;j>l&&k>m;){var o=h[l],p=i[m],q=o._0,r=p._0,s=o._1,t=p._1;if(q!==r){var u=j>l+1,v=k>m+1;if(u)var w=h[l+1],x=w._0,y=w._1,z=r===x;if(v)var A=i[m+1],B=A._0,C=A._1,D=q===B;if(u&&v&&D&&z)n++,H(s,C,e,n),M(f,e,q,t,m,g),n+=s.descendantsCount||0,n++,N(f,e,q,y,n),n+=y.descendantsCount||0,l+=2,m+=2;else if(v&&D)n++,M(f,e,r,t,m,g),H(s,C,e,n),n+=s.descendantsCount||0,l+=1,m+=2;else if(u&&z)n++,N(f,e,q,s,n),n+=s.descendantsCount||0,n++,H(y,t,e,n),n+=y.descendantsCount||0,l+=2,m+=1;else{if(!u||!v||x!==B)break;n++,N(f,e,q,s,n),M(f,e,r,t,m,g),n+=s.descendantsCount||0,n++,H(y,C,e,n),n+=y.descendantsCount||0,l+=2,m+=2}}else n++,H(s,t,e,n),n+=s.descendantsCount||0,l++,m++}for(;j>l;){n++;var o=h[l],s=o._1;N(f,e,o._0,s,n),n+=s.descendantsCount||0,l++}for(var E;k>m;){E=E||[];var p=i[m];M(f,e,p._0,p._1,void 0,E),m++}(e.length>0||g.length>0||"undefined"!=typeof E)&&c.push(G("p-reorder",d,{patches:e,inserts:g,endInserts:E}))}function M(a,b,c,d,e,f){var g=a[c];if("undefined"==typeof g)return g={tag:"insert",vnode:d,index:e,data:void 0},f.push({index:e,entry:g}),void(a[c]=g);if("remove"===g.tag){f.push({index:e,entry:g}),g.tag="move";var h=[];return H(g.vnode,d,h,g.index),g.index=e,void(g.data.data={patches:h,entry:g})}M(a,b,c+qa,d,e,f)}function N(a,b,c,d,e){var f=a[c];if("undefined"==typeof f){var g=G("p-remove",e,void 0);return b.push(g),void(a[c]={tag:"remove",vnode:d,index:e,data:g})}if("insert"===f.tag){f.tag="move";var h=[];H(d,f.vnode,h,e);var g=G("p-remove",e,{patches:h,entry:f});return void b.push(g)}N(a,b,c+qa,d,e)}function O(a,b,c,d){P(a,b,c,0,0,b.descendantsCount,d)}function P(a,b,c,d,e,f,g){for(var h=c[d],i=h.index;i===e;){var j=h.type;if("p-thunk"===j)O(a,b.node,h.data,g);else if("p-reorder"===j){h.domNode=a,h.eventNode=g;var k=h.data.patches;k.length>0&&P(a,b,k,0,e,f,g)}else if("p-remove"===j){h.domNode=a,h.eventNode=g;var l=h.data;if("undefined"!=typeof l){l.entry.data=a;var k=l.patches;k.length>0&&P(a,b,k,0,e,f,g)}}else h.domNode=a,h.eventNode=g;if(d++,!(h=c[d])||(i=h.index)>f)return d}switch(b.type){case"tagger":for(var m=b.node;"tagger"===m.type;)m=m.node;return P(a,m,c,d,e+1,f,a.elm_event_node_ref);case"node":for(var n=b.children,o=a.childNodes,p=0;p<n.length;p++){e++;var q=n[p],r=e+(q.descendantsCount||0);if(i>=e&&r>=i&&(d=P(o[p],q,c,d,e,r,g),!(h=c[d])||(i=h.index)>f))return d;e=r}return d;case"keyed-node":for(var n=b.children,o=a.childNodes,p=0;p<n.length;p++){e++;var q=n[p]._1,r=e+(q.descendantsCount||0);if(i>=e&&r>=i&&(d=P(o[p],q,c,d,e,r,g),!(h=c[d])||(i=h.index)>f))return d;e=r}return d;case"text":case"thunk":throw new Error("should never traverse `text` or `thunk` nodes like this")}}function Q(a,b,c,d){return 0===c.length?a:(O(a,b,c,d),R(a,c))}function R(a,b){for(var c=0;c<b.length;c++){var d=b[c],e=d.domNode,f=S(e,d);e===a&&(a=f)}return a}function S(a,b){switch(b.type){case"p-redraw":return T(a,b.data,b.eventNode);case"p-facts":return z(a,b.eventNode,b.data),a;case"p-text":return a.replaceData(0,a.length,b.data),a;case"p-thunk":return R(a,b.data);case"p-tagger":return"undefined"!=typeof a.elm_event_node_ref?a.elm_event_node_ref.tagger=b.data:a.elm_event_node_ref={tagger:b.data,parent:b.eventNode},a;case"p-remove-last":for(var c=b.data;c--;)a.removeChild(a.lastChild);return a;case"p-append":for(var d=b.data,c=0;c<d.length;c++)a.appendChild(y(d[c],b.eventNode));return a;case"p-remove":var e=b.data;if("undefined"==typeof e)return a.parentNode.removeChild(a),a;var f=e.entry;return"undefined"!=typeof f.index&&a.parentNode.removeChild(a),f.data=R(a,e.patches),a;case"p-reorder":return U(a,b);case"p-custom":var g=b.data;return g.applyPatch(a,g.data);default:throw new Error("Ran into an unknown patch!")}}function T(a,b,c){var d=a.parentNode,e=y(b,c);return"undefined"==typeof e.elm_event_node_ref&&(e.elm_event_node_ref=a.elm_event_node_ref),d&&e!==a&&d.replaceChild(e,a),e}function U(a,b){var c=b.data,d=V(c.endInserts,b);a=R(a,c.patches);for(var e=c.inserts,f=0;f<e.length;f++){var g=e[f],h=g.entry,i="move"===h.tag?h.data:y(h.vnode,b.eventNode);a.insertBefore(i,a.childNodes[g.index])}return"undefined"!=typeof d&&a.appendChild(d),a}function V(a,b){if("undefined"!=typeof a){for(var c=pa.createDocumentFragment(),d=0;d<a.length;d++){var e=a[d],f=e.entry;c.appendChild("move"===f.tag?f.data:y(f.vnode,b.eventNode))}return c}}function W(b){return a(function(a,c){return function(d){return function(e,f,g){var h=b(d,f);"undefined"==typeof g?_(c,e,f,h):ca(i(a,g,c),e,f,h)}}})}function X(b){var c=p.Tuple2(p.Tuple0,Cb);return i(ra,Eb,{init:c,view:function(){return b},update:a(function(){return c}),subscriptions:function(){return Hb}})()}function Y(a,b){return function(a,c,d){if("undefined"==typeof c)return a;var e="The `"+b+"` module does not need flags.\nInitialize it with no arguments and you should be all set!";$(e,d)}}function Z(a,b){return function(c,d,e){if("undefined"==typeof a){var f="Are you trying to sneak a Never value into Elm? Trickster!\nIt looks like "+b+".main is defined with `programWithFlags` but has type `Program Never`.\nUse `program` instead if you do not want flags.";$(f,e)}var g=i(ab.run,a,d);if("Ok"===g.ctor)return c(g._0);var f="Trying to initialize the `"+b+"` module with an unexpected flag.\nI tried to convert it to an Elm value, but ran into this problem:\n\n"+g._0;$(f,e)}}function $(a,b){throw b&&(b.innerHTML='<div style="padding-left:1em;"><h2 style="font-weight:normal;"><b>Oops!</b> Something went wrong when starting your Elm program.</h2><pre style="padding-left:1em;">'+a+"</pre></div>"),new Error(a)}function _(a,b,c,d){b.embed=function(b,c){for(;b.lastChild;)b.removeChild(b.lastChild);return zb.initialize(d(a.init,c,b),a.update,a.subscriptions,aa(b,a.view))},b.fullscreen=function(b){return zb.initialize(d(a.init,b,document.body),a.update,a.subscriptions,aa(document.body,a.view))}}function aa(a,b){return function(c,d){var e={tagger:c,parent:void 0},f=b(d),g=y(f,e);return a.appendChild(g),ba(g,b,f,e)}}function ba(a,b,c,d){function e(){
-switch(g){case"NO_REQUEST":throw new Error("Unexpected draw callback.\nPlease report this to <https://github.com/elm-lang/virtual-dom/issues>.");case"PENDING_REQUEST":ta(e),g="EXTRA_REQUEST";var c=b(f),i=F(h,c);return a=Q(a,h,i,d),void(h=c);case"EXTRA_REQUEST":return void(g="NO_REQUEST")}}var f,g="NO_REQUEST",h=c;return function(a){"NO_REQUEST"===g&&ta(e),g="PENDING_REQUEST",f=a}}function ca(a,b,c,d){b.fullscreen=function(b){var e={doc:void 0};return zb.initialize(d(a.init,b,document.body),a.update(da(e)),a.subscriptions,ea(c,document.body,e,a.view,a.viewIn,a.viewOut))},b.embed=function(b,e){var f={doc:void 0};return zb.initialize(d(a.init,e,b),a.update(da(f)),a.subscriptions,ea(c,b,f,a.view,a.viewIn,a.viewOut))}}function da(a){return Ab.nativeBinding(function(b){var c=a.doc;if(c){var d=c.getElementsByClassName("debugger-sidebar-messages")[0];d&&(d.scrollTop=d.scrollHeight)}b(Ab.succeed(p.Tuple0))})}function ea(a,b,c,d,e,f){return function(g,h){var i={tagger:g,parent:void 0},j={tagger:g,parent:void 0},k=d(h),l=y(k,i);b.appendChild(l);var m=ba(l,d,k,i),n=e(h)._1,o=y(n,j);b.appendChild(o);var p=ha(i,o,e),q=ba(o,p,n,j),r=fa(h,f,j,b,a,c);return function(a){m(a),q(a),r(a)}}}function fa(a,b,c,d,e,f){var g,h;return function(a){if(a.isDebuggerOpen){if(!f.doc)return g=b(a),void(h=ga(e,f,g,c));pa=f.doc;var d=b(a),i=F(g,d);h=Q(h,g,i,c),g=d,pa=document}}}function ga(a,b,c,d){function e(){b.doc=void 0,j.close()}var f=900,g=360,h=screen.width-f,i=screen.height-g,j=window.open("","","width="+f+",height="+g+",left="+h+",top="+i);pa=j.document,b.doc=pa,pa.title="Debugger - "+a,pa.body.style.margin="0",pa.body.style.padding="0";var k=y(c,d);return pa.body.appendChild(k),pa.addEventListener("keydown",function(a){a.metaKey&&82===a.which&&window.location.reload(),38===a.which&&(d.tagger({ctor:"Up"}),a.preventDefault()),40===a.which&&(d.tagger({ctor:"Down"}),a.preventDefault())}),window.addEventListener("unload",e),j.addEventListener("unload",function(){b.doc=void 0,window.removeEventListener("unload",e),d.tagger({ctor:"Close"})}),pa=document,k}function ha(a,b,c){var d,e=ka(b),f="Normal",g=a.tagger,h=function(){};return function(b){var i=c(b),j=i._0.ctor;return a.tagger="Normal"===j?g:h,f!==j&&(ia("removeEventListener",e,f),ia("addEventListener",e,j),"Normal"===f&&(d=document.body.style.overflow,document.body.style.overflow="hidden"),"Normal"===j&&(document.body.style.overflow=d),f=j),i._1}}function ia(a,b,c){switch(c){case"Normal":return;case"Pause":return ja(a,b,ua);case"Message":return ja(a,b,va)}}function ja(a,b,c){for(var d=0;d<c.length;d++)document.body[a](c[d],b,!0)}function ka(a){return function(b){if("keydown"!==b.type||!b.metaKey||82!==b.which){for(var c="scroll"===b.type||"wheel"===b.type,d=b.target;null!==d;){if("elm-overlay-message-details"===d.className&&c)return;if(d===a&&!c)return;d=d.parentNode}b.stopPropagation(),b.preventDefault()}}}var la="STYLE",ma="EVENT",na="ATTR",oa="ATTR_NS",pa="undefined"!=typeof document?document:{},qa="_elmW6BL",ra=W(Y),sa=W(Z),ta="undefined"!=typeof requestAnimationFrame?requestAnimationFrame:function(a){setTimeout(a,1e3/60)},ua=["click","dblclick","mousemove","mouseup","mousedown","mouseenter","mouseleave","touchstart","touchend","touchcancel","touchmove","pointerdown","pointerup","pointerover","pointerout","pointerenter","pointerleave","pointermove","pointercancel","dragstart","drag","dragend","dragenter","dragover","dragleave","drop","keyup","keydown","keypress","input","change","focus","blur"],va=ua.concat("wheel","scroll");return{node:e,text:d,custom:h,map:a(k),on:b(v),style:r,property:a(s),attribute:a(t),attributeNS:b(u),mapProperty:a(x),lazy:a(m),lazy2:b(n),lazy3:c(o),keyedNode:b(g),program:ra,programWithFlags:sa,staticProgram:X}}(),Qb=function(a){return i(Pb.programWithFlags,Fb,a)},Rb=function(a){return i(Pb.program,Eb,a)},Sb=(Pb.keyedNode,Pb.lazy3,Pb.lazy2,Pb.lazy,{stopPropagation:!1,preventDefault:!1}),Tb=Pb.on,Ub=a(function(a,b){return j(Tb,a,Sb,b)}),Vb=Pb.style,Wb=(Pb.mapProperty,Pb.attributeNS,Pb.attribute),Xb=Pb.property,Yb=(Pb.map,Pb.text),Zb=Pb.node,$b=(a(function(a,b){return{stopPropagation:a,preventDefault:b}}),Qb),_b=Yb,ac=Zb,bc=(ac("body"),ac("section"),ac("nav"),ac("article"),ac("aside"),ac("h1"),ac("h2"),ac("h3"),ac("h4"),ac("h5"),ac("h6"),ac("header"),ac("footer"),ac("address"),ac("main"),ac("p")),cc=(ac("hr"),ac("pre"),ac("blockquote"),ac("ol"),ac("ul"),ac("li"),ac("dl"),ac("dt"),ac("dd"),ac("figure"),ac("figcaption"),ac("div")),dc=ac("a"),ec=(ac("em"),ac("strong")),fc=(ac("small"),ac("s"),ac("cite"),ac("q"),ac("dfn"),ac("abbr"),ac("time"),ac("code"),ac("var"),ac("samp"),ac("kbd"),ac("sub"),ac("sup"),ac("i")),gc=(ac("b"),ac("u"),ac("mark"),ac("ruby"),ac("rt"),ac("rp"),ac("bdi"),ac("bdo"),ac("span")),hc=(ac("br"),ac("wbr"),ac("ins"),ac("del"),ac("img")),ic=(ac("iframe"),ac("embed"),ac("object"),ac("param"),ac("video"),ac("audio"),ac("source"),ac("track"),ac("canvas"),ac("math"),ac("table"),ac("caption"),ac("colgroup"),ac("col"),ac("tbody"),ac("thead"),ac("tfoot"),ac("tr"),ac("td"),ac("th"),ac("form"),ac("fieldset"),ac("legend"),ac("label"),ac("input")),jc=ac("button"),kc=(ac("select"),ac("datalist"),ac("optgroup"),ac("option"),ac("textarea"),ac("keygen"),ac("output"),ac("progress"),ac("meter"),ac("details"),ac("summary"),ac("menuitem"),ac("menu"),Wb),lc=Xb,mc=a(function(a,b){return i(lc,a,fb(b))}),nc=function(a){return i(mc,"className",a)},oc=function(a){return i(mc,"title",a)},pc=function(a){return i(mc,"src",a)},qc=function(a){return i(mc,"value",a)},rc=function(a){return i(mc,"placeholder",a)},sc=function(a){return i(mc,"href",a)},tc=function(a){return i(mc,"target",a)},uc=a(function(a,b){return i(lc,a,db(b))}),vc=function(a){return i(uc,"autofocus",a)},wc=function(a){return i(uc,"disabled",a)},xc=Vb,yc=i(tb,"keyCode",wb),zc=(i(ub,{ctor:"::",_0:"target",_1:{ctor:"::",_0:"checked",_1:{ctor:"[]"}}},xb),i(ub,{ctor:"::",_0:"target",_1:{ctor:"::",_0:"value",_1:{ctor:"[]"}}},yb)),Ac=Sb,Bc=Tb,Cc=Ub,Dc=(p.update(Ac,{preventDefault:!0}),function(a){return i(Cc,"input",i(rb,a,zc))}),Ec=function(a){return i(Cc,"click",lb(a))},Fc=(a(function(a,b){return{stopPropagation:a,preventDefault:b}}),Ab.onError),Gc=Ab.andThen,Hc=a(function(a,b){var c=b;return Ab.spawn(i(Gc,Jb(a),c._0))}),Ic=Ab.fail,Jc=(a(function(a,b){return i(Fc,function(b){return Ic(a(b))},b)}),Ab.succeed),Kc=a(function(a,b){return i(Gc,function(b){return Jc(a(b))},b)}),Lc=b(function(a,b,c){return i(Gc,function(b){return i(Gc,function(c){return Jc(i(a,b,c))},c)},b)}),Mc=(c(function(a,b,c,d){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return Jc(j(a,b,c,d))},d)},c)},b)}),d(function(a,b,c,d,e){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return i(Gc,function(e){return Jc(k(a,b,c,d,e))},e)},d)},c)},b)}),e(function(a,b,c,d,e,f){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return i(Gc,function(e){return i(Gc,function(f){return Jc(l(a,b,c,d,e,f))},f)},e)},d)},c)},b)}),function(b){var c=b;return"[]"===c.ctor?Jc({ctor:"[]"}):j(Lc,a(function(a,b){return{ctor:"::",_0:a,_1:b}}),c._0,Mc(c._1))}),Nc=b(function(a,b,c){return i(Kc,function(a){return{ctor:"_Tuple0"}},Mc(i(O,Hc(a),b)))}),Oc=Jc({ctor:"_Tuple0"}),Pc=b(function(a,b,c){return Jc({ctor:"_Tuple0"})}),Qc=zb.leaf("Task"),Rc=function(a){return{ctor:"Perform",_0:a}},Sc=(a(function(a,b){return Qc(Rc(i(Kc,a,b)))}),a(function(a,b){return Qc(Rc(i(Fc,function(b){return Jc(a(ha(b)))},i(Gc,function(b){return Jc(a(ia(b)))},b))))})),Tc=a(function(a,b){var c=b;return Rc(i(Kc,a,c._0))});zb.effectManagers.Task={pkg:"elm-lang/core",init:Oc,onEffects:Nc,onSelfMsg:Pc,tag:"cmd",cmdMap:Tc};var Uc=function(){function b(a,b){return Ab.nativeBinding(function(c){var d=setInterval(function(){Ab.rawSpawn(b)},a);return function(){clearInterval(d)}})}var c=Ab.nativeBinding(function(a){a(Ab.succeed(Date.now()))});return{now:c,setInterval_:a(b)}}(),Vc=Uc.setInterval_,Wc=b(function(a,b,c){var d=b;if("[]"===d.ctor)return Jc(c);var e=d._0,f=function(b){return j(Wc,a,d._1,j(Xa,e,b,c))},g=Ab.spawn(i(Vc,e,i(Ib,a,e)));return i(Gc,f,g)}),Xc=a(function(a,b){var c=a,d=c._1,e=c._0,f=i(ta,e,b);return"Nothing"===f.ctor?j(Xa,e,{ctor:"::",_0:d,_1:{ctor:"[]"}},b):j(Xa,e,{ctor:"::",_0:d,_1:f._0},b)}),Yc=Uc.now,Zc=b(function(a,b,c){var d=i(ta,b,c.taggers);if("Nothing"===d.ctor)return Jc(c);var e=function(b){return Mc(i(O,function(c){return i(Jb,a,c(b))},d._0))};return i(Gc,function(a){return Jc(c)},i(Gc,e,Yc))}),$c=zb.leaf("Time"),_c=a(function(a,b){return{taggers:a,processes:b}}),ad=Jc(i(_c,Ga,Ga)),bd=b(function(a,d,e){var f=e,g=b(function(a,b,c){var d=c;return{ctor:"_Tuple3",_0:d._0,_1:d._1,_2:i(Gc,function(a){return d._2},Ab.kill(b))}}),h=c(function(a,b,c,d){var e=d;return{ctor:"_Tuple3",_0:e._0,_1:j(Xa,a,c,e._1),_2:e._2}}),k=b(function(a,b,c){var d=c;return{ctor:"_Tuple3",_0:{ctor:"::",_0:a,_1:d._0},_1:d._1,_2:d._2}}),l=j(I,Xc,Ga,d),n=m(pa,k,h,g,l,f.processes,{ctor:"_Tuple3",_0:{ctor:"[]"},_1:Ga,_2:Jc({ctor:"_Tuple0"})}),o=n._0,p=n._1,q=n._2;return i(Gc,function(a){return Jc(i(_c,l,a))},i(Gc,function(b){return j(Wc,a,o,p)},q))}),cd=a(function(a,b){return{ctor:"Every",_0:a,_1:b}}),dd=(a(function(a,b){return $c(i(cd,a,b))}),a(function(a,b){var c=b;return i(cd,c._0,function(b){return a(c._1(b))})}));zb.effectManagers.Time={pkg:"elm-lang/core",init:ad,onEffects:bd,onSelfMsg:Zc,tag:"sub",subMap:dd};var ed=function(){function d(a){return a.replace(/[-\/\\^$*+?.()|[\]{}]/g,"\\$&")}function e(a){return new RegExp(a.source,"gi")}function f(a){return new RegExp(a,"g")}function g(a,b){return null!==b.match(a)}function h(a,b,c){a="All"===a.ctor?1/0:a._0;for(var d,e=[],f=0,g=c,h=b.lastIndex,i=-1;f++<a&&(d=b.exec(g))&&i!==b.lastIndex;){for(var j=d.length-1,k=new Array(j);j>0;){var l=d[j];k[--j]=void 0===l?B:C(l)}e.push({match:d[0],submatches:D.fromArray(k),index:d.index,number:f}),i=b.lastIndex}return b.lastIndex=h,D.fromArray(e)}function i(a,b,c,d){function e(b){if(f++>=a)return b;for(var d=arguments.length-3,e=new Array(d);d>0;){var g=arguments[d];e[--d]=void 0===g?B:C(g)}return c({match:b,submatches:D.fromArray(e),index:arguments[arguments.length-2],number:f})}a="All"===a.ctor?1/0:a._0;var f=0;return d.replace(b,e)}function j(a,b,c){if(a="All"===a.ctor?1/0:a._0,a===1/0)return D.fromArray(c.split(b));for(var d,e=c,f=[],g=b.lastIndex,h=b.lastIndex;a--&&(d=b.exec(e));)f.push(e.slice(g,d.index)),g=b.lastIndex;return f.push(e.slice(g)),b.lastIndex=h,D.fromArray(f)}return{regex:f,caseInsensitive:e,escape:d,contains:a(g),find:b(h),replace:c(i),split:b(j)}}(),fd=Ab.kill,gd=Ab.sleep,hd=Ab.spawn,id=(ed.split,ed.replace),jd=ed.find,kd=(ed.contains,ed.caseInsensitive,ed.regex),ld=(ed.escape,c(function(a,b,c,d){return{match:a,submatches:b,index:c,number:d}}),function(a){return{ctor:"AtMost",_0:a}}),md={ctor:"All"},nd=function(){function b(a){return encodeURIComponent(a)}function c(a){try{return C(decodeURIComponent(a))}catch(b){return B}}function d(a,b){return Ab.nativeBinding(function(c){var d=new XMLHttpRequest;e(d,b),d.addEventListener("error",function(){c(Ab.fail({ctor:"NetworkError"}))}),d.addEventListener("timeout",function(){c(Ab.fail({ctor:"Timeout"}))}),d.addEventListener("load",function(){c(h(d,a.expect.responseToResult))});try{d.open(a.method,a.url,!0)}catch(i){return c(Ab.fail({ctor:"BadUrl",_0:a.url}))}return f(d,a),g(d,a.body),function(){d.abort()}})}function e(a,b){"Nothing"!==b.ctor&&a.addEventListener("progress",function(a){a.lengthComputable&&Ab.rawSpawn(b._0({bytes:a.loaded,bytesExpected:a.total}))})}function f(a,b){function c(b){a.setRequestHeader(b._0,b._1)}i(O,c,b.headers),a.responseType=b.expect.responseType,a.withCredentials=b.withCredentials,"Just"===b.timeout.ctor&&(a.timeout=b.timeout._0)}function g(a,b){switch(b.ctor){case"EmptyBody":return void a.send();case"StringBody":return a.setRequestHeader("Content-Type",b._0),void a.send(b._1);case"FormDataBody":return void a.send(b._0)}}function h(a,b){var c=k(a);if(a.status<200||300<=a.status)return c.body=a.responseText,Ab.fail({ctor:"BadStatus",_0:c});var d=b(c);return"Ok"===d.ctor?Ab.succeed(d._0):(c.body=a.responseText,Ab.fail({ctor:"BadPayload",_0:d._0,_1:c}))}function k(a){return{status:{code:a.status,message:a.statusText},headers:l(a.getAllResponseHeaders()),url:a.responseURL,body:a.response}}function l(a){var b=Ga;if(!a)return b;for(var c=a.split("\r\n"),d=c.length;d--;){var e=c[d],f=e.indexOf(": ");if(f>0){var g=e.substring(0,f),h=e.substring(f+2);b=j(Wa,g,function(a){return C("Just"===a.ctor?h+", "+a._0:h)},b)}}return b}function m(a){return{responseType:"text",responseToResult:a}}function n(a,b){return{responseType:b.responseType,responseToResult:function(c){var d=b.responseToResult(c);return i(ja,a,d)}}}function o(a){for(var b=new FormData;"[]"!==a.ctor;){var c=a._0;b.append(c._0,c._1),a=a._1}return{ctor:"FormDataBody",_0:b}}return{toTask:a(d),expectStringResponse:m,mapExpect:a(n),multipart:o,encodeUri:b,decodeUri:c}}(),od=(a(function(a,b){return p.update(b,{expect:i(nd.mapExpect,a,b.expect)})}),f(function(a,b,c,d,e,f,g){return{method:a,headers:b,url:c,body:d,expect:e,timeout:f,withCredentials:g}}),function(a){return{ctor:"Request",_0:a}}),pd=(a(function(a,b){return{ctor:"StringBody",_0:a,_1:b}}),{ctor:"EmptyBody"}),qd=(a(function(a,b){return{ctor:"Header",_0:a,_1:b}}),nd.decodeUri,nd.encodeUri,nd.expectStringResponse),rd=function(a){return qd(function(b){return i(nb,a,b.body)})},sd=(qd(function(a){return ia(a.body)}),nd.multipart,pd),td=od,ud=(b(function(a,b,c){return td({method:"POST",headers:{ctor:"[]"},url:a,body:b,expect:rd(c),timeout:B,withCredentials:!1})}),a(function(a,b){return td({method:"GET",headers:{ctor:"[]"},url:a,body:sd,expect:rd(b),timeout:B,withCredentials:!1})})),vd=function(a){var b=a;return i(nd.toTask,b._0,B)},wd=a(function(a,b){return i(Sc,a,vd(b))}),xd=(c(function(a,b,c,d){return{url:a,status:b,headers:c,body:d}}),a(function(a,b){return{ctor:"BadPayload",_0:a,_1:b}}),a(function(a,b){return{ctor:"StringPart",_0:a,_1:b}}),function(){function c(a,b){return Ab.nativeBinding(function(c){try{var d=new WebSocket(a);d.elm_web_socket=!0}catch(e){return c(Ab.fail({ctor:"SecurityError"===e.name?"BadSecurity":"BadArgs",_0:e.message}))}return d.addEventListener("open",function(a){c(Ab.succeed(d))}),d.addEventListener("message",function(a){Ab.rawSpawn(i(b.onMessage,d,a.data))}),d.addEventListener("close",function(a){Ab.rawSpawn(b.onClose({code:a.code,reason:a.reason,wasClean:a.wasClean}))}),function(){d&&d.close&&d.close()}})}function d(a,b){return Ab.nativeBinding(function(c){var d=a.readyState===WebSocket.OPEN?B:C({ctor:"NotOpen"});try{a.send(b)}catch(e){d=C({ctor:"BadString"})}c(Ab.succeed(d))})}function e(a,b,c){return Ab.nativeBinding(function(d){try{c.close(a,b)}catch(e){return d(Ab.fail(C({ctor:"SyntaxError"===e.name?"BadReason":"BadCode"})))}d(Ab.succeed(B))})}function f(a){return Ab.nativeBinding(function(b){b(Ab.succeed(a.bufferedAmount))})}return{open:a(c),send:a(d),close:b(e),bytesQueued:f}}()),yd=(xd.bytesQueued,xd.send),zd=xd.close,Ad=function(a){return i(Kc,q({ctor:"_Tuple0"}),j(zd,1e3,"",a))},Bd=xd.open,Cd=(a(function(a,b){return{onMessage:a,onClose:b}}),function(a){var b=a;return"Opening"===b.ctor?fd(b._1):Ad(b._0)}),Dd=function(a){return p.cmp(a,1)<0?Jc({ctor:"_Tuple0"}):gd(u(10*Math.pow(2,a)))},Ed=a(function(a,b){return p.update(b,{queues:i(_a,a,b.queues)})}),Fd=b(function(a,b,c){return p.update(c,{sockets:j(Xa,a,b,c.sockets)})}),Gd=a(function(a,b){var c=b;return C("Nothing"===c.ctor?{ctor:"::",_0:a,_1:{ctor:"[]"}}:{ctor:"::",_0:a,_1:c._0})}),Hd=a(function(a,b){for(;;){var c=a;if("[]"===c.ctor)return b;if("Listen"!==c._0.ctor){var d=c._1,e=j(Wa,c._0._0,function(a){return C(i(A,{ctor:"[]"},a))},b);a=d,b=e}else{var f=c._1,g=j(Wa,c._0._0,Gd(c._0._1),b);a=f,b=g}}}),Id=Id||{};Id["&>"]=a(function(a,b){return i(Gc,function(a){return b},a)});var Jd=b(function(a,b,c){for(;;){var d=a;if("[]"===d.ctor)return Jc(c);var e=d._1,f=d._0._0,g=d._0._1,h=i(ta,f,b);if("Just"===h.ctor&&"Connected"===h._0.ctor)return i(Id["&>"],i(yd,h._0._0,g),j(Jd,e,b,c));var k=e,l=b,m=j(Wa,f,Gd(g),c);a=k,b=l,c=m}}),Kd=zb.leaf("WebSocket"),Ld=zb.leaf("WebSocket"),Md=b(function(a,b,c){return{sockets:a,queues:b,subs:c}}),Nd=Jc(j(Md,Ga,Ga,Ga)),Od=a(function(a,b){return{ctor:"Send",_0:a,_1:b}}),Pd=a(function(a,b){return Ld(i(Od,a,b))}),Qd=a(function(a,b){var c=b;return i(Od,c._0,c._1)}),Rd=function(a){return{ctor:"KeepAlive",_0:a}},Sd=a(function(a,b){return{ctor:"Listen",_0:a,_1:b}}),Td=a(function(a,b){return Kd(i(Sd,a,b))}),Ud=a(function(a,b){var c=b;return"Listen"===c.ctor?i(Sd,c._0,function(b){return a(c._1(b))}):Rd(c._0)}),Vd=function(a){return{ctor:"Connected",_0:a}},Wd=a(function(a,b){return{ctor:"Opening",_0:a,_1:b}}),Xd=function(a){return{ctor:"BadOpen",_0:a}},Yd=a(function(a,b){return{ctor:"GoodOpen",_0:a,_1:b}}),Zd=function(a){return{ctor:"Die",_0:a}},$d=a(function(a,b){return{ctor:"Receive",_0:a,_1:b}}),_d=a(function(b,c){return i(Bd,b,{onMessage:a(function(a,d){return i(Ib,c,i($d,b,d))}),onClose:function(a){return i(Ib,c,Zd(b))}})}),ae=b(function(a,b,c){var d=function(b){return i(Ib,a,Xd(c))},e=function(b){return i(Ib,a,i(Yd,c,b))},f=i(Fc,d,i(Gc,e,i(_d,c,a)));return hd(i(Id["&>"],Dd(b),f))}),be=c(function(d,e,f,g){var h=i(Hd,f,Ga),k=function(e){var f=b(function(a,b,c){return i(Id["&>"],Cd(b),c)}),k=c(function(a,b,c,d){return i(Kc,i(Xa,a,c),d)}),l=b(function(a,b,c){return i(Gc,function(b){return i(Gc,function(c){return Jc(j(Xa,a,i(Wd,0,c),b))},j(ae,d,0,a))},c)}),n=i(Ya,e,i(Sa,a(function(a,b){return{ctor:"[]"}}),h)),o=m(pa,l,k,f,n,g.sockets,Jc(Ga));return i(Gc,function(a){return Jc(j(Md,a,e,h))},o)},l=j(Jd,e,g.sockets,g.queues);return i(Gc,k,l)}),ce=b(function(b,c,d){var e=c;switch(e.ctor){case"Receive":var f=i(O,function(a){return i(Jb,b,a(e._1))},i(A,{ctor:"[]"},i(ta,e._0,d.subs)));return i(Id["&>"],Mc(f),Jc(d));case"Die":var g=e._0,h=i(ta,g,d.sockets);return"Nothing"===h.ctor?Jc(d):i(Gc,function(a){return Jc(j(Fd,g,i(Wd,0,a),d))},j(ae,b,0,g));case"GoodOpen":var k=e._1,l=e._0,m=i(ta,l,d.queues);return"Nothing"===m.ctor?Jc(j(Fd,l,Vd(k),d)):j(I,a(function(a,b){return i(Id["&>"],i(yd,k,a),b)}),Jc(i(Ed,l,j(Fd,l,Vd(k),d))),m._0);default:var n=e._0,o=i(ta,n,d.sockets);if("Nothing"===o.ctor)return Jc(d);if("Opening"===o._0.ctor){var p=o._0._0;return i(Gc,function(a){return Jc(j(Fd,n,i(Wd,p+1,a),d))},j(ae,b,p+1,n))}return Jc(d)}});zb.effectManagers.WebSocket={pkg:"elm-lang/websocket",init:Nd,onEffects:be,onSelfMsg:ce,tag:"fx",cmdMap:Qd,subMap:Ud};var de=function(b){var c=a(function(a,b){var c=a;return p.eq(b,c._0)?lb(c._1):kb("")}),d=function(a){return sb(i(O,function(b){return b(a)},i(O,c,b)))};return i(Cc,"keydown",i(jb,d,yc))},ee=27,fe=13,ge=c(function(a,b,c,d){return{name:a,fullName:b,$private:c,owner:d}}),he=h(function(a,b,c,d,e,f,g,h,i){return{id:a,htmlUrl:b,title:c,state:d,comments:e,body:f,labels:g,url:h,assignees:i}}),ie=b(function(a,b,c){return{id:a,login:b,avatarUrl:c}}),je=k(pb,ie,i(tb,"id",wb),i(tb,"login",yb),i(tb,"avatar_url",yb)),ke=l(ob,ge,i(tb,"name",yb),i(tb,"full_name",yb),i(tb,"private",xb),i(tb,"owner",je)),le=a(function(a,b){var c=i(s["++"],"https://api.github.com/user/repos?access_token=",a);return i(wd,b,i(ud,c,vb(ke)))}),me=b(function(a,b,c){return{name:a,color:b,url:c}}),ne=k(pb,me,i(tb,"name",yb),i(tb,"color",yb),i(tb,"url",yb)),oe=j(Ob,"assignees",vb(je),j(Ob,"url",yb,j(Ob,"labels",vb(ne),k(Nb,"body",yb,"",j(Ob,"comments",wb,j(Ob,"state",yb,j(Ob,"title",yb,j(Ob,"html_url",yb,j(Ob,"id",wb,Kb(he)))))))))),pe=d(function(a,b,c,d,e){var f=i(s["++"],"https://api.github.com/repos/",i(s["++"],b,i(s["++"],"/",i(s["++"],c,i(s["++"],"/issues/",i(s["++"],t(d),i(s["++"],"?access_token=",a)))))));return i(wd,e,i(ud,f,oe))}),qe=b(function(a,b,c){var d=i(s["++"],b,i(s["++"],"?access_token=",a));return i(wd,c,i(ud,d,oe))}),re=a(function(a,b){var c=i(s["++"],"https://api.github.com/user/issues?filter=all&state=all&sort=created&per_page=500&access_token=",a);return i(wd,b,i(ud,c,vb(oe)))}),se=i(ub,{ctor:"::",_0:"items",_1:{ctor:"[]"}},vb(oe)),te=b(function(a,b,c){var d=function(a){return j(id,md,kd("\\S+"),function(b){return a})},e=i(d,b,"+"),f=i(s["++"],"https://api.github.com/search/issues?q=",i(s["++"],e,i(s["++"],"&access_token=",a)));return i(wd,c,i(ud,f,se))}),ue=function(a){var b=a;switch(b.ctor){case"NotDragging":return B;case"Dragging":return B;default:return C(b._1)}},ve=function(a){var b=a;switch(b.ctor){case"NotDragging":return B;case"Dragging":return C(b._0);default:return C(b._0)}},we=a(function(a,b){return{ctor:"DraggedOver",_0:a,_1:b}}),xe=function(a){return{ctor:"Dragging",_0:a}},ye={ctor:"NotDragging"},ze=ye,Ae=b(function(a,b,c){var d={ctor:"_Tuple3",_0:b,_1:c,_2:a};a:do{if("_Tuple3"!==d.ctor)break a;switch(d._0.ctor){case"DragStart":return{ctor:"_Tuple2",_0:xe(d._0._0),_1:B};case"DragEnd":return"DraggedOver"===d._1.ctor&&d._2===!0?{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._1._1})}:{ctor:"_Tuple2",_0:ye,_1:B};case"DragEnter":switch(d._1.ctor){case"Dragging":return{ctor:"_Tuple2",_0:i(we,d._1._0,d._0._0),_1:B};case"DraggedOver":return{ctor:"_Tuple2",_0:i(we,d._1._0,d._0._0),_1:B};default:break a}case"DragLeave":if("DraggedOver"===d._1.ctor&&d._2===!1)return p.eq(d._0._0,d._1._1)?{ctor:"_Tuple2",_0:xe(d._1._0),_1:B}:{ctor:"_Tuple2",_0:c,_1:B};break a;default:switch(d._1.ctor){case"Dragging":return{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._0._0})};case"DraggedOver":return{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._0._0})};default:break a}}}while(!1);return{ctor:"_Tuple2",_0:c,_1:B}}),Be=(Ae(!1),Ae(!0)),Ce=function(a){return{ctor:"Drop",_0:a}},De=function(a){return{ctor:"DragLeave",_0:a}},Ee=function(a){return{ctor:"DragEnter",_0:a}},Fe=a(function(a,b){return{ctor:"::",_0:i(Cc,"dragenter",lb(a(Ee(b)))),_1:{ctor:"::",_0:i(Cc,"dragleave",lb(a(De(b)))),_1:{ctor:"::",_0:j(Bc,"drop",{stopPropagation:!0,preventDefault:!0},lb(a(Ce(b)))),_1:{ctor:"::",_0:i(kc,"ondragover","event.stopPropagation(); event.preventDefault();"),_1:{ctor:"[]"}}}}}}),Ge={ctor:"DragEnd"},He=function(a){return{ctor:"DragStart",_0:a}},Ie=a(function(a,b){return{ctor:"::",_0:i(kc,"draggable","true"),_1:{ctor:"::",_0:i(Cc,"dragstart",lb(a(He(b)))),_1:{ctor:"::",_0:i(Cc,"dragend",lb(a(Ge))),_1:{ctor:"::",_0:i(kc,"ondragstart","event.dataTransfer.setData('text/plain', '');"),_1:{ctor:"[]"}}}}}}),Je=function(a){return cb({ctor:"::",_0:{ctor:"_Tuple2",_0:"position",_1:eb(a.position)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"order",_1:eb(a.order)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"issueUrl",_1:fb(a.issue.url)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"issueId",_1:eb(a.issue.id)},_1:{ctor:"[]"}}}}})},Ke=function(a){return cb({ctor:"::",_0:{ctor:"_Tuple2",_0:"cards",_1:bb(i(O,Je,a.cards))},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"rows",_1:eb(a.rows)},_1:{ctor:"[]"}}})},Le={position:1,order:0},Me={id:0,url:"http:///issue-without-url"},Ne={ctor:"::",_0:"Story",_1:{ctor:"::",_0:"To do",_1:{ctor:"::",_0:"In progress",_1:{ctor:"::",_0:"Done",_1:{ctor:"[]"}}}}},Oe=(a(function(a,b){return{githubToken:a,websocketAddress:b}}),a(function(a,b){return{id:a,url:b}}),a(function(a,b){return{position:a,order:b}})),Pe=c(function(a,b,c,d){return{position:a,order:b,issue:c,askDelete:d}}),Qe=a(function(a,b){return{rows:a,cards:b}}),Re=c(function(a,b,c,d){return{position:a,order:b,issueUrl:c,issueId:d}}),Se=l(ob,Re,i(tb,"position",wb),i(tb,"order",wb),i(tb,"issueUrl",yb),i(tb,"issueId",wb)),Te=j(qb,Qe,i(tb,"rows",wb),i(tb,"cards",vb(Se))),Ue=function(a){return{ctor:"WsMessage",_0:a}},Ve={ctor:"CloseError"},We=function(a){return{ctor:"ShowIcelog",_0:a}},Xe=function(a){return{ctor:"IcelogFetched",_0:a}},Ye=function(a){return{ctor:"IcelogSearchChanged",_0:a}},Ze={ctor:"QueryIcelog"},$e=function(a){return{ctor:"RepositoriesFetched",_0:a}},_e=a(function(a,b){return{ctor:"IssueRefreshed",_0:a,_1:b}}),af=a(function(a,b){return{ctor:"IssueFetched",_0:a,_1:b}}),bf=function(a){return{ctor:"DelIssueCardCancel",_0:a}},cf=function(a){return{ctor:"DelIssueCardConfirm",_0:a}},df=function(a){return{ctor:"DelIssueCard",_0:a}},ef=function(a){return{ctor:"DragDrop",_0:a}},ff=b(function(a,b,c){return j(qe,b,c,af(a))}),gf=b(function(a,b,c){return j(qe,b,c,_e(a))}),hf=(d(function(a,b,c,d,e){return l(pe,b,c,d,e,af(a))}),function(a){var b=i(gb,2,Ke(a));return i(Pd,a.flags.websocketAddress,b)}),jf=c(function(a,b,c,d){var e=j(ff,b,a,c.url);return{ctor:"_Tuple2",_0:d,_1:e}}),kf=b(function(a,b,c){var d=function(c){return p.eq(c.issue.id,b.id)?p.update(c,{position:a.position,order:a.order}):c};return{ctor:"_Tuple2",_0:i(O,d,c),_1:Cb}}),lf=a(function(a,b){return i(G,function(b){return p.eq(b.issue.id,a.id)},b)}),mf=function(b){var c=a(function(a,b){return p.cmp(a.position,b.position)>0?y:p.cmp(a.position,b.position)<0?z:p.cmp(a.order,b.order)>0?y:z});return i(E,c,b)},nf=function(b){var c=a(function(a,b){return p.update(b,{order:2*a})}),d=i(_,1,200),e=mf(b);return j(F,c,d,e)},of=function(a){var b=u(J(Ne)),c=u(i(A,0,K(i(O,function(a){return a.position},a.cards)))),d=v((c+1)/b);return p.update(a,{rows:d+1})},pf=a(function(a,b){var c=a;switch(c.ctor){case"CloseError":return{ctor:"_Tuple2",_0:p.update(b,{error:B}),_1:Cb};case"RepositoriesFetched":return"Ok"===c._0.ctor?{ctor:"_Tuple2",_0:p.update(b,{repositories:c._0._0}),_1:Cb}:{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._0._0))}),_1:Cb};case"WsMessage":var d=i(nb,Te,c._0);if("Err"===d.ctor)return{ctor:"_Tuple2",_0:p.update(b,{error:C(d._0)}),_1:Cb};var e=d._0,f=function(a){return a},g=$a(i(O,function(a){return{ctor:"_Tuple2",_0:a.issue.id,_1:a}},b.cards)),h=function(a){var c=i(ta,a.issueId,g);return"Nothing"===c.ctor?{ctor:"_Tuple2",_0:B,_1:j(gf,i(Oe,a.position,a.order),b.flags.githubToken,a.issueUrl)}:{ctor:"_Tuple2",_0:C(p.update(c._0,{position:a.position})),_1:Cb}},l=V(i(O,h,e.cards)),m=l._0,n=l._1,o=i(R,f,m),q=of(p.update(b,{rows:e.rows,cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:Bb(n)};case"QueryIcelog":return{ctor:"_Tuple2",_0:p.update(b,{icelogFetching:!0}),_1:j(te,b.flags.githubToken,b.icelogQuery,Xe)};case"IcelogSearchChanged":return{ctor:"_Tuple2",_0:p.update(b,{icelogQuery:c._0}),_1:Cb};case"IssueFetched":if("Ok"===c._1.ctor){var r=c._0,u=c._1._0,v=i(P,function(a){return!p.eq(a.issue.id,u.id)},b.cards),w=k(Pe,r.position,r.order,u,!1),o=i(s["++"],v,{ctor:"::",_0:w,_1:{ctor:"[]"}}),q=of(p.update(b,{cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:hf(q)}}return{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._1._0))}),_1:Cb};case"IcelogFetched":return"Ok"===c._0.ctor?{ctor:"_Tuple2",_0:p.update(b,{icelog:c._0._0,icelogFetching:!1}),_1:Cb}:{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._0._0)),icelogFetching:!1}),_1:Cb};case"ShowIcelog":return{ctor:"_Tuple2",_0:p.update(b,{showIcelog:c._0}),_1:Cb};case"IssueRefreshed":if("Ok"===c._1.ctor){var x=c._0,y=c._1._0,v=i(P,function(a){return!p.eq(a.issue.id,y.id)},b.cards),w=k(Pe,x.position,x.order,y,!1),o=i(s["++"],v,{ctor:"::",_0:w,_1:{ctor:"[]"}}),q=of(p.update(b,{cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:Cb}}return{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._1._0))}),_1:Cb};case"DragDrop":var z=i(Be,c._0,b.dragDrop),D=z._0,E=z._1,F=i(A,Me,ve(D)),G=i(A,Le,ue(D)),H=i(lf,F,b.cards)?j(kf,G,F,b.cards):p.eq(F,Me)?{ctor:"_Tuple2",_0:b.cards,_1:Cb}:k(jf,b.flags.githubToken,G,F,b.cards),o=H._0,I=H._1,q=of(p.update(b,{dragDrop:D,cards:nf(o)})),J=function(){var a=E;return"Just"===a.ctor?hf(q):Cb}();return{ctor:"_Tuple2",_0:q,_1:Bb({ctor:"::",_0:J,_1:{ctor:"::",_0:I,_1:{ctor:"[]"}}})};case"DelIssueCard":var K=function(a){return p.eq(a.issue.id,c._0)?p.update(a,{askDelete:!0}):p.update(a,{askDelete:!1})},o=i(O,K,b.cards);return{ctor:"_Tuple2",_0:p.update(b,{cards:o}),_1:Cb};case"DelIssueCardConfirm":var o=i(P,function(a){return!p.eq(a.issue.id,c._0)},b.cards),q=of(p.update(b,{cards:o}));return{ctor:"_Tuple2",_0:q,_1:hf(q)};default:var o=i(O,function(a){return p.update(a,{askDelete:!1})},b.cards);return{ctor:"_Tuple2",_0:p.update(b,{cards:o}),_1:Cb}}}),qf=function(a){return i(Oe,a.position,a.order)},rf=function(a){return{id:a.id,url:a.url}},sf=function(a){return rf(a.issue)},tf=function(a){var b=M(j(jd,ld(1),kd("repos/[^/]+/([^/]+)/issues/(\\d+)$"),a)),c=b;return"Nothing"===c.ctor?"":i(ka,"/",i(O,A(""),c._0.submatches))},uf=function(a){return i(fc,{ctor:"::",_0:nc(i(s["++"],"fa fa-",a)),_1:{ctor:"::",_0:i(kc,"aria-hidden","true"),_1:{ctor:"[]"}}},{ctor:"[]"})},vf=function(a){var b={ctor:"::",_0:xc({ctor:"::",_0:{ctor:"_Tuple2",_0:"color",_1:i(s["++"],"#",a.color)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"border",_1:i(s["++"],"1px solid #",a.color)},_1:{ctor:"[]"}}}),_1:{ctor:"[]"}};return i(gc,{ctor:"::",_0:nc("card-label"),_1:b},{ctor:"::",_0:_b(a.name),_1:{ctor:"[]"}})},wf=function(a){var b=i(s["++"],a.avatarUrl,"&s=18");return i(hc,{ctor:"::",_0:pc(b),_1:{ctor:"::",_0:nc("avatar"),_1:{ctor:"[]"}}},{ctor:"[]"})},xf=function(a){var b=function(){var b=M(a.labels);return"Nothing"===b.ctor?"#C2E4EF":i(s["++"],"#",b._0.color)}();return xc({ctor:"::",_0:{ctor:"_Tuple2",_0:"border-left",_1:i(s["++"],"6px solid ",b)},_1:{ctor:"[]"}})},yf=a(function(a,b){var c=b.askDelete?i(gc,{ctor:"::",_0:nc("card-remove"),_1:{ctor:"[]"}},{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("card-remove-yes"),_1:{ctor:"::",_0:Ec(cf(b.issue.id)),_1:{ctor:"::",_0:oc("Confirm and remove from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("trash-o"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("card-remove-no"),_1:{ctor:"::",_0:Ec(bf(b.issue.id)),_1:{ctor:"::",_0:oc("Cancel removing from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("ban"),_1:{ctor:"[]"}}),_1:{ctor:"[]"}}}):i(gc,{ctor:"::",_0:Ec(df(b.issue.id)),_1:{ctor:"::",_0:nc("card-remove"),_1:{ctor:"::",_0:oc("Remove from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("trash-o"),_1:{ctor:"[]"}}),d=i(O,wf,b.issue.assignees),e=i(O,vf,b.issue.labels),f=i(Fe,ef,qf(b)),g=i(Ie,ef,sf(b)),h=nc(p.eq(i(A,Me,a),sf(b))?"card-placeholder":""),j=i(s["++"],{ctor:"::",_0:h,_1:{ctor:"::",_0:xf(b.issue),_1:{ctor:"::",_0:nc("card"),_1:g}}},f);return i(cc,j,{ctor:"::",_0:c,_1:{ctor:"::",_0:i(dc,{ctor:"::",_0:oc(b.issue.body),_1:{ctor:"::",_0:nc(i(s["++"],"card-title state-",b.issue.state)),_1:{ctor:"::",_0:sc(b.issue.htmlUrl),_1:{ctor:"::",_0:tc("_blank"),_1:{ctor:"[]"}}}}},{ctor:"::",_0:_b(b.issue.title),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-meta"),_1:{ctor:"[]"}},i(s["++"],e,{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},d),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(tf(b.issue.url)),_1:{ctor:"::",_0:uf("code-fork"),_1:{ctor:"[]"}}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(t(b.issue.comments)),_1:{ctor:"::",_0:uf("comments-o"),_1:{ctor:"[]"}}}),_1:{ctor:"[]"}}}})),_1:{ctor:"[]"}}}})}),zf=a(function(a,b){return i(P,function(b){return p.eq(b.position,a.position)},b)}),Af=function(a){var b=i(Fe,ef,a),c={ctor:"::",_0:nc("drop-helper"),_1:b};return i(cc,c,{ctor:"[]"})},Bf=b(function(a,b,c){var d=Af(i(Oe,c.position,1e3)),e=Af(i(Oe,c.position,0)),f={ctor:"[]"},g=i(s["++"],{ctor:"::",_0:nc("board-cell"),_1:{ctor:"[]"}},f),h=i(zf,c,b),j=i(O,yf(a),h);return i(cc,g,{ctor:"::",_0:e,_1:i(s["++"],j,{ctor:"::",_0:d,_1:{ctor:"[]"}})})}),Cf=b(function(a,b,c){var d=a,e=i(_,d._0.position,d._1.position),f=j(F,Oe,e,i(_,0,30)),g=i(O,i(Bf,b,c),f);return i(cc,{ctor:"::",_0:nc("board-row"),_1:{ctor:"[]"}},g)}),Df=function(a){var b=function(a){return i(ec,{ctor:"::",_0:nc("board-header-col"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(a),_1:{ctor:"[]"}})},c=i(O,b,a);return i(cc,{ctor:"::",_0:nc("board-header"),_1:{ctor:"[]"}},c)},Ef=function(a){var b=i(Ie,ef,rf(a)),c={ctor:"::",_0:xf(a),_1:{ctor:"::",_0:nc("card"),_1:b}},d=i(O,vf,a.labels),e=i(O,wf,a.assignees);return i(cc,c,{ctor:"::",_0:i(dc,{ctor:"::",_0:oc(a.body),_1:{ctor:"::",_0:nc(i(s["++"],"card-title state-",a.state)),_1:{ctor:"::",_0:sc(a.htmlUrl),_1:{ctor:"::",_0:tc("_blank"),_1:{ctor:"[]"}}}}},{ctor:"::",_0:_b(a.title),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-meta"),_1:{ctor:"[]"}},i(s["++"],d,{ctor:"::",_0:i(cc,{ctor:"::",
+switch(g){case"NO_REQUEST":throw new Error("Unexpected draw callback.\nPlease report this to <https://github.com/elm-lang/virtual-dom/issues>.");case"PENDING_REQUEST":ta(e),g="EXTRA_REQUEST";var c=b(f),i=F(h,c);return a=Q(a,h,i,d),void(h=c);case"EXTRA_REQUEST":return void(g="NO_REQUEST")}}var f,g="NO_REQUEST",h=c;return function(a){"NO_REQUEST"===g&&ta(e),g="PENDING_REQUEST",f=a}}function ca(a,b,c,d){b.fullscreen=function(b){var e={doc:void 0};return zb.initialize(d(a.init,b,document.body),a.update(da(e)),a.subscriptions,ea(c,document.body,e,a.view,a.viewIn,a.viewOut))},b.embed=function(b,e){var f={doc:void 0};return zb.initialize(d(a.init,e,b),a.update(da(f)),a.subscriptions,ea(c,b,f,a.view,a.viewIn,a.viewOut))}}function da(a){return Ab.nativeBinding(function(b){var c=a.doc;if(c){var d=c.getElementsByClassName("debugger-sidebar-messages")[0];d&&(d.scrollTop=d.scrollHeight)}b(Ab.succeed(p.Tuple0))})}function ea(a,b,c,d,e,f){return function(g,h){var i={tagger:g,parent:void 0},j={tagger:g,parent:void 0},k=d(h),l=y(k,i);b.appendChild(l);var m=ba(l,d,k,i),n=e(h)._1,o=y(n,j);b.appendChild(o);var p=ha(i,o,e),q=ba(o,p,n,j),r=fa(h,f,j,b,a,c);return function(a){m(a),q(a),r(a)}}}function fa(a,b,c,d,e,f){var g,h;return function(a){if(a.isDebuggerOpen){if(!f.doc)return g=b(a),void(h=ga(e,f,g,c));pa=f.doc;var d=b(a),i=F(g,d);h=Q(h,g,i,c),g=d,pa=document}}}function ga(a,b,c,d){function e(){b.doc=void 0,j.close()}var f=900,g=360,h=screen.width-f,i=screen.height-g,j=window.open("","","width="+f+",height="+g+",left="+h+",top="+i);pa=j.document,b.doc=pa,pa.title="Debugger - "+a,pa.body.style.margin="0",pa.body.style.padding="0";var k=y(c,d);return pa.body.appendChild(k),pa.addEventListener("keydown",function(a){a.metaKey&&82===a.which&&window.location.reload(),38===a.which&&(d.tagger({ctor:"Up"}),a.preventDefault()),40===a.which&&(d.tagger({ctor:"Down"}),a.preventDefault())}),window.addEventListener("unload",e),j.addEventListener("unload",function(){b.doc=void 0,window.removeEventListener("unload",e),d.tagger({ctor:"Close"})}),pa=document,k}function ha(a,b,c){var d,e=ka(b),f="Normal",g=a.tagger,h=function(){};return function(b){var i=c(b),j=i._0.ctor;return a.tagger="Normal"===j?g:h,f!==j&&(ia("removeEventListener",e,f),ia("addEventListener",e,j),"Normal"===f&&(d=document.body.style.overflow,document.body.style.overflow="hidden"),"Normal"===j&&(document.body.style.overflow=d),f=j),i._1}}function ia(a,b,c){switch(c){case"Normal":return;case"Pause":return ja(a,b,ua);case"Message":return ja(a,b,va)}}function ja(a,b,c){for(var d=0;d<c.length;d++)document.body[a](c[d],b,!0)}function ka(a){return function(b){if("keydown"!==b.type||!b.metaKey||82!==b.which){for(var c="scroll"===b.type||"wheel"===b.type,d=b.target;null!==d;){if("elm-overlay-message-details"===d.className&&c)return;if(d===a&&!c)return;d=d.parentNode}b.stopPropagation(),b.preventDefault()}}}var la="STYLE",ma="EVENT",na="ATTR",oa="ATTR_NS",pa="undefined"!=typeof document?document:{},qa="_elmW6BL",ra=W(Y),sa=W(Z),ta="undefined"!=typeof requestAnimationFrame?requestAnimationFrame:function(a){setTimeout(a,1e3/60)},ua=["click","dblclick","mousemove","mouseup","mousedown","mouseenter","mouseleave","touchstart","touchend","touchcancel","touchmove","pointerdown","pointerup","pointerover","pointerout","pointerenter","pointerleave","pointermove","pointercancel","dragstart","drag","dragend","dragenter","dragover","dragleave","drop","keyup","keydown","keypress","input","change","focus","blur"],va=ua.concat("wheel","scroll");return{node:e,text:d,custom:h,map:a(k),on:b(v),style:r,property:a(s),attribute:a(t),attributeNS:b(u),mapProperty:a(x),lazy:a(m),lazy2:b(n),lazy3:c(o),keyedNode:b(g),program:ra,programWithFlags:sa,staticProgram:X}}(),Qb=function(a){return i(Pb.programWithFlags,Fb,a)},Rb=function(a){return i(Pb.program,Eb,a)},Sb=(Pb.keyedNode,Pb.lazy3,Pb.lazy2,Pb.lazy,{stopPropagation:!1,preventDefault:!1}),Tb=Pb.on,Ub=a(function(a,b){return j(Tb,a,Sb,b)}),Vb=Pb.style,Wb=(Pb.mapProperty,Pb.attributeNS,Pb.attribute),Xb=Pb.property,Yb=(Pb.map,Pb.text),Zb=Pb.node,$b=(a(function(a,b){return{stopPropagation:a,preventDefault:b}}),Qb),_b=Yb,ac=Zb,bc=(ac("body"),ac("section"),ac("nav"),ac("article"),ac("aside"),ac("h1"),ac("h2"),ac("h3"),ac("h4"),ac("h5"),ac("h6"),ac("header"),ac("footer"),ac("address"),ac("main"),ac("p")),cc=(ac("hr"),ac("pre"),ac("blockquote"),ac("ol"),ac("ul"),ac("li"),ac("dl"),ac("dt"),ac("dd"),ac("figure"),ac("figcaption"),ac("div")),dc=ac("a"),ec=(ac("em"),ac("strong")),fc=(ac("small"),ac("s"),ac("cite"),ac("q"),ac("dfn"),ac("abbr"),ac("time"),ac("code"),ac("var"),ac("samp"),ac("kbd"),ac("sub"),ac("sup"),ac("i")),gc=(ac("b"),ac("u"),ac("mark"),ac("ruby"),ac("rt"),ac("rp"),ac("bdi"),ac("bdo"),ac("span")),hc=(ac("br"),ac("wbr"),ac("ins"),ac("del"),ac("img")),ic=(ac("iframe"),ac("embed"),ac("object"),ac("param"),ac("video"),ac("audio"),ac("source"),ac("track"),ac("canvas"),ac("math"),ac("table"),ac("caption"),ac("colgroup"),ac("col"),ac("tbody"),ac("thead"),ac("tfoot"),ac("tr"),ac("td"),ac("th"),ac("form"),ac("fieldset"),ac("legend"),ac("label"),ac("input")),jc=ac("button"),kc=(ac("select"),ac("datalist"),ac("optgroup"),ac("option"),ac("textarea"),ac("keygen"),ac("output"),ac("progress"),ac("meter"),ac("details"),ac("summary"),ac("menuitem"),ac("menu"),Wb),lc=Xb,mc=a(function(a,b){return i(lc,a,fb(b))}),nc=function(a){return i(mc,"className",a)},oc=function(a){return i(mc,"title",a)},pc=function(a){return i(mc,"src",a)},qc=function(a){return i(mc,"value",a)},rc=function(a){return i(mc,"placeholder",a)},sc=function(a){return i(mc,"href",a)},tc=function(a){return i(mc,"target",a)},uc=a(function(a,b){return i(lc,a,db(b))}),vc=function(a){return i(uc,"autofocus",a)},wc=function(a){return i(uc,"disabled",a)},xc=Vb,yc=i(tb,"keyCode",wb),zc=(i(ub,{ctor:"::",_0:"target",_1:{ctor:"::",_0:"checked",_1:{ctor:"[]"}}},xb),i(ub,{ctor:"::",_0:"target",_1:{ctor:"::",_0:"value",_1:{ctor:"[]"}}},yb)),Ac=Sb,Bc=Tb,Cc=Ub,Dc=(p.update(Ac,{preventDefault:!0}),function(a){return i(Cc,"input",i(rb,a,zc))}),Ec=function(a){return i(Cc,"click",lb(a))},Fc=(a(function(a,b){return{stopPropagation:a,preventDefault:b}}),Ab.onError),Gc=Ab.andThen,Hc=a(function(a,b){var c=b;return Ab.spawn(i(Gc,Jb(a),c._0))}),Ic=Ab.fail,Jc=(a(function(a,b){return i(Fc,function(b){return Ic(a(b))},b)}),Ab.succeed),Kc=a(function(a,b){return i(Gc,function(b){return Jc(a(b))},b)}),Lc=b(function(a,b,c){return i(Gc,function(b){return i(Gc,function(c){return Jc(i(a,b,c))},c)},b)}),Mc=(c(function(a,b,c,d){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return Jc(j(a,b,c,d))},d)},c)},b)}),d(function(a,b,c,d,e){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return i(Gc,function(e){return Jc(k(a,b,c,d,e))},e)},d)},c)},b)}),e(function(a,b,c,d,e,f){return i(Gc,function(b){return i(Gc,function(c){return i(Gc,function(d){return i(Gc,function(e){return i(Gc,function(f){return Jc(l(a,b,c,d,e,f))},f)},e)},d)},c)},b)}),function(b){var c=b;return"[]"===c.ctor?Jc({ctor:"[]"}):j(Lc,a(function(a,b){return{ctor:"::",_0:a,_1:b}}),c._0,Mc(c._1))}),Nc=b(function(a,b,c){return i(Kc,function(a){return{ctor:"_Tuple0"}},Mc(i(O,Hc(a),b)))}),Oc=Jc({ctor:"_Tuple0"}),Pc=b(function(a,b,c){return Jc({ctor:"_Tuple0"})}),Qc=zb.leaf("Task"),Rc=function(a){return{ctor:"Perform",_0:a}},Sc=(a(function(a,b){return Qc(Rc(i(Kc,a,b)))}),a(function(a,b){return Qc(Rc(i(Fc,function(b){return Jc(a(ha(b)))},i(Gc,function(b){return Jc(a(ia(b)))},b))))})),Tc=a(function(a,b){var c=b;return Rc(i(Kc,a,c._0))});zb.effectManagers.Task={pkg:"elm-lang/core",init:Oc,onEffects:Nc,onSelfMsg:Pc,tag:"cmd",cmdMap:Tc};var Uc=function(){function b(a,b){return Ab.nativeBinding(function(c){var d=setInterval(function(){Ab.rawSpawn(b)},a);return function(){clearInterval(d)}})}var c=Ab.nativeBinding(function(a){a(Ab.succeed(Date.now()))});return{now:c,setInterval_:a(b)}}(),Vc=Uc.setInterval_,Wc=b(function(a,b,c){var d=b;if("[]"===d.ctor)return Jc(c);var e=d._0,f=function(b){return j(Wc,a,d._1,j(Xa,e,b,c))},g=Ab.spawn(i(Vc,e,i(Ib,a,e)));return i(Gc,f,g)}),Xc=a(function(a,b){var c=a,d=c._1,e=c._0,f=i(ta,e,b);return"Nothing"===f.ctor?j(Xa,e,{ctor:"::",_0:d,_1:{ctor:"[]"}},b):j(Xa,e,{ctor:"::",_0:d,_1:f._0},b)}),Yc=Uc.now,Zc=b(function(a,b,c){var d=i(ta,b,c.taggers);if("Nothing"===d.ctor)return Jc(c);var e=function(b){return Mc(i(O,function(c){return i(Jb,a,c(b))},d._0))};return i(Gc,function(a){return Jc(c)},i(Gc,e,Yc))}),$c=zb.leaf("Time"),_c=a(function(a,b){return{taggers:a,processes:b}}),ad=Jc(i(_c,Ga,Ga)),bd=b(function(a,d,e){var f=e,g=b(function(a,b,c){var d=c;return{ctor:"_Tuple3",_0:d._0,_1:d._1,_2:i(Gc,function(a){return d._2},Ab.kill(b))}}),h=c(function(a,b,c,d){var e=d;return{ctor:"_Tuple3",_0:e._0,_1:j(Xa,a,c,e._1),_2:e._2}}),k=b(function(a,b,c){var d=c;return{ctor:"_Tuple3",_0:{ctor:"::",_0:a,_1:d._0},_1:d._1,_2:d._2}}),l=j(I,Xc,Ga,d),n=m(pa,k,h,g,l,f.processes,{ctor:"_Tuple3",_0:{ctor:"[]"},_1:Ga,_2:Jc({ctor:"_Tuple0"})}),o=n._0,p=n._1,q=n._2;return i(Gc,function(a){return Jc(i(_c,l,a))},i(Gc,function(b){return j(Wc,a,o,p)},q))}),cd=a(function(a,b){return{ctor:"Every",_0:a,_1:b}}),dd=(a(function(a,b){return $c(i(cd,a,b))}),a(function(a,b){var c=b;return i(cd,c._0,function(b){return a(c._1(b))})}));zb.effectManagers.Time={pkg:"elm-lang/core",init:ad,onEffects:bd,onSelfMsg:Zc,tag:"sub",subMap:dd};var ed=function(){function d(a){return a.replace(/[-\/\\^$*+?.()|[\]{}]/g,"\\$&")}function e(a){return new RegExp(a.source,"gi")}function f(a){return new RegExp(a,"g")}function g(a,b){return null!==b.match(a)}function h(a,b,c){a="All"===a.ctor?1/0:a._0;for(var d,e=[],f=0,g=c,h=b.lastIndex,i=-1;f++<a&&(d=b.exec(g))&&i!==b.lastIndex;){for(var j=d.length-1,k=new Array(j);j>0;){var l=d[j];k[--j]=void 0===l?B:C(l)}e.push({match:d[0],submatches:D.fromArray(k),index:d.index,number:f}),i=b.lastIndex}return b.lastIndex=h,D.fromArray(e)}function i(a,b,c,d){function e(b){if(f++>=a)return b;for(var d=arguments.length-3,e=new Array(d);d>0;){var g=arguments[d];e[--d]=void 0===g?B:C(g)}return c({match:b,submatches:D.fromArray(e),index:arguments[arguments.length-2],number:f})}a="All"===a.ctor?1/0:a._0;var f=0;return d.replace(b,e)}function j(a,b,c){if(a="All"===a.ctor?1/0:a._0,a===1/0)return D.fromArray(c.split(b));for(var d,e=c,f=[],g=b.lastIndex,h=b.lastIndex;a--&&(d=b.exec(e));)f.push(e.slice(g,d.index)),g=b.lastIndex;return f.push(e.slice(g)),b.lastIndex=h,D.fromArray(f)}return{regex:f,caseInsensitive:e,escape:d,contains:a(g),find:b(h),replace:c(i),split:b(j)}}(),fd=Ab.kill,gd=Ab.sleep,hd=Ab.spawn,id=(ed.split,ed.replace),jd=ed.find,kd=(ed.contains,ed.caseInsensitive,ed.regex),ld=(ed.escape,c(function(a,b,c,d){return{match:a,submatches:b,index:c,number:d}}),function(a){return{ctor:"AtMost",_0:a}}),md={ctor:"All"},nd=function(){function b(a){return encodeURIComponent(a)}function c(a){try{return C(decodeURIComponent(a))}catch(b){return B}}function d(a,b){return Ab.nativeBinding(function(c){var d=new XMLHttpRequest;e(d,b),d.addEventListener("error",function(){c(Ab.fail({ctor:"NetworkError"}))}),d.addEventListener("timeout",function(){c(Ab.fail({ctor:"Timeout"}))}),d.addEventListener("load",function(){c(h(d,a.expect.responseToResult))});try{d.open(a.method,a.url,!0)}catch(i){return c(Ab.fail({ctor:"BadUrl",_0:a.url}))}return f(d,a),g(d,a.body),function(){d.abort()}})}function e(a,b){"Nothing"!==b.ctor&&a.addEventListener("progress",function(a){a.lengthComputable&&Ab.rawSpawn(b._0({bytes:a.loaded,bytesExpected:a.total}))})}function f(a,b){function c(b){a.setRequestHeader(b._0,b._1)}i(O,c,b.headers),a.responseType=b.expect.responseType,a.withCredentials=b.withCredentials,"Just"===b.timeout.ctor&&(a.timeout=b.timeout._0)}function g(a,b){switch(b.ctor){case"EmptyBody":return void a.send();case"StringBody":return a.setRequestHeader("Content-Type",b._0),void a.send(b._1);case"FormDataBody":return void a.send(b._0)}}function h(a,b){var c=k(a);if(a.status<200||300<=a.status)return c.body=a.responseText,Ab.fail({ctor:"BadStatus",_0:c});var d=b(c);return"Ok"===d.ctor?Ab.succeed(d._0):(c.body=a.responseText,Ab.fail({ctor:"BadPayload",_0:d._0,_1:c}))}function k(a){return{status:{code:a.status,message:a.statusText},headers:l(a.getAllResponseHeaders()),url:a.responseURL,body:a.response}}function l(a){var b=Ga;if(!a)return b;for(var c=a.split("\r\n"),d=c.length;d--;){var e=c[d],f=e.indexOf(": ");if(f>0){var g=e.substring(0,f),h=e.substring(f+2);b=j(Wa,g,function(a){return C("Just"===a.ctor?h+", "+a._0:h)},b)}}return b}function m(a){return{responseType:"text",responseToResult:a}}function n(a,b){return{responseType:b.responseType,responseToResult:function(c){var d=b.responseToResult(c);return i(ja,a,d)}}}function o(a){for(var b=new FormData;"[]"!==a.ctor;){var c=a._0;b.append(c._0,c._1),a=a._1}return{ctor:"FormDataBody",_0:b}}return{toTask:a(d),expectStringResponse:m,mapExpect:a(n),multipart:o,encodeUri:b,decodeUri:c}}(),od=(a(function(a,b){return p.update(b,{expect:i(nd.mapExpect,a,b.expect)})}),f(function(a,b,c,d,e,f,g){return{method:a,headers:b,url:c,body:d,expect:e,timeout:f,withCredentials:g}}),function(a){return{ctor:"Request",_0:a}}),pd=(a(function(a,b){return{ctor:"StringBody",_0:a,_1:b}}),{ctor:"EmptyBody"}),qd=(a(function(a,b){return{ctor:"Header",_0:a,_1:b}}),nd.decodeUri,nd.encodeUri,nd.expectStringResponse),rd=function(a){return qd(function(b){return i(nb,a,b.body)})},sd=(qd(function(a){return ia(a.body)}),nd.multipart,pd),td=od,ud=(b(function(a,b,c){return td({method:"POST",headers:{ctor:"[]"},url:a,body:b,expect:rd(c),timeout:B,withCredentials:!1})}),a(function(a,b){return td({method:"GET",headers:{ctor:"[]"},url:a,body:sd,expect:rd(b),timeout:B,withCredentials:!1})})),vd=function(a){var b=a;return i(nd.toTask,b._0,B)},wd=a(function(a,b){return i(Sc,a,vd(b))}),xd=(c(function(a,b,c,d){return{url:a,status:b,headers:c,body:d}}),a(function(a,b){return{ctor:"BadPayload",_0:a,_1:b}}),a(function(a,b){return{ctor:"StringPart",_0:a,_1:b}}),function(){function c(a,b){return Ab.nativeBinding(function(c){try{var d=new WebSocket(a);d.elm_web_socket=!0}catch(e){return c(Ab.fail({ctor:"SecurityError"===e.name?"BadSecurity":"BadArgs",_0:e.message}))}return d.addEventListener("open",function(a){c(Ab.succeed(d))}),d.addEventListener("message",function(a){Ab.rawSpawn(i(b.onMessage,d,a.data))}),d.addEventListener("close",function(a){Ab.rawSpawn(b.onClose({code:a.code,reason:a.reason,wasClean:a.wasClean}))}),function(){d&&d.close&&d.close()}})}function d(a,b){return Ab.nativeBinding(function(c){var d=a.readyState===WebSocket.OPEN?B:C({ctor:"NotOpen"});try{a.send(b)}catch(e){d=C({ctor:"BadString"})}c(Ab.succeed(d))})}function e(a,b,c){return Ab.nativeBinding(function(d){try{c.close(a,b)}catch(e){return d(Ab.fail(C({ctor:"SyntaxError"===e.name?"BadReason":"BadCode"})))}d(Ab.succeed(B))})}function f(a){return Ab.nativeBinding(function(b){b(Ab.succeed(a.bufferedAmount))})}return{open:a(c),send:a(d),close:b(e),bytesQueued:f}}()),yd=(xd.bytesQueued,xd.send),zd=xd.close,Ad=function(a){return i(Kc,q({ctor:"_Tuple0"}),j(zd,1e3,"",a))},Bd=xd.open,Cd=(a(function(a,b){return{onMessage:a,onClose:b}}),function(a){var b=a;return"Opening"===b.ctor?fd(b._1):Ad(b._0)}),Dd=function(a){return p.cmp(a,1)<0?Jc({ctor:"_Tuple0"}):gd(u(10*Math.pow(2,a)))},Ed=a(function(a,b){return p.update(b,{queues:i(_a,a,b.queues)})}),Fd=b(function(a,b,c){return p.update(c,{sockets:j(Xa,a,b,c.sockets)})}),Gd=a(function(a,b){var c=b;return C("Nothing"===c.ctor?{ctor:"::",_0:a,_1:{ctor:"[]"}}:{ctor:"::",_0:a,_1:c._0})}),Hd=a(function(a,b){for(;;){var c=a;if("[]"===c.ctor)return b;if("Listen"!==c._0.ctor){var d=c._1,e=j(Wa,c._0._0,function(a){return C(i(A,{ctor:"[]"},a))},b);a=d,b=e}else{var f=c._1,g=j(Wa,c._0._0,Gd(c._0._1),b);a=f,b=g}}}),Id=Id||{};Id["&>"]=a(function(a,b){return i(Gc,function(a){return b},a)});var Jd=b(function(a,b,c){for(;;){var d=a;if("[]"===d.ctor)return Jc(c);var e=d._1,f=d._0._0,g=d._0._1,h=i(ta,f,b);if("Just"===h.ctor&&"Connected"===h._0.ctor)return i(Id["&>"],i(yd,h._0._0,g),j(Jd,e,b,c));var k=e,l=b,m=j(Wa,f,Gd(g),c);a=k,b=l,c=m}}),Kd=zb.leaf("WebSocket"),Ld=zb.leaf("WebSocket"),Md=b(function(a,b,c){return{sockets:a,queues:b,subs:c}}),Nd=Jc(j(Md,Ga,Ga,Ga)),Od=a(function(a,b){return{ctor:"Send",_0:a,_1:b}}),Pd=a(function(a,b){return Ld(i(Od,a,b))}),Qd=a(function(a,b){var c=b;return i(Od,c._0,c._1)}),Rd=function(a){return{ctor:"KeepAlive",_0:a}},Sd=a(function(a,b){return{ctor:"Listen",_0:a,_1:b}}),Td=a(function(a,b){return Kd(i(Sd,a,b))}),Ud=a(function(a,b){var c=b;return"Listen"===c.ctor?i(Sd,c._0,function(b){return a(c._1(b))}):Rd(c._0)}),Vd=function(a){return{ctor:"Connected",_0:a}},Wd=a(function(a,b){return{ctor:"Opening",_0:a,_1:b}}),Xd=function(a){return{ctor:"BadOpen",_0:a}},Yd=a(function(a,b){return{ctor:"GoodOpen",_0:a,_1:b}}),Zd=function(a){return{ctor:"Die",_0:a}},$d=a(function(a,b){return{ctor:"Receive",_0:a,_1:b}}),_d=a(function(b,c){return i(Bd,b,{onMessage:a(function(a,d){return i(Ib,c,i($d,b,d))}),onClose:function(a){return i(Ib,c,Zd(b))}})}),ae=b(function(a,b,c){var d=function(b){return i(Ib,a,Xd(c))},e=function(b){return i(Ib,a,i(Yd,c,b))},f=i(Fc,d,i(Gc,e,i(_d,c,a)));return hd(i(Id["&>"],Dd(b),f))}),be=c(function(d,e,f,g){var h=i(Hd,f,Ga),k=function(e){var f=b(function(a,b,c){return i(Id["&>"],Cd(b),c)}),k=c(function(a,b,c,d){return i(Kc,i(Xa,a,c),d)}),l=b(function(a,b,c){return i(Gc,function(b){return i(Gc,function(c){return Jc(j(Xa,a,i(Wd,0,c),b))},j(ae,d,0,a))},c)}),n=i(Ya,e,i(Sa,a(function(a,b){return{ctor:"[]"}}),h)),o=m(pa,l,k,f,n,g.sockets,Jc(Ga));return i(Gc,function(a){return Jc(j(Md,a,e,h))},o)},l=j(Jd,e,g.sockets,g.queues);return i(Gc,k,l)}),ce=b(function(b,c,d){var e=c;switch(e.ctor){case"Receive":var f=i(O,function(a){return i(Jb,b,a(e._1))},i(A,{ctor:"[]"},i(ta,e._0,d.subs)));return i(Id["&>"],Mc(f),Jc(d));case"Die":var g=e._0,h=i(ta,g,d.sockets);return"Nothing"===h.ctor?Jc(d):i(Gc,function(a){return Jc(j(Fd,g,i(Wd,0,a),d))},j(ae,b,0,g));case"GoodOpen":var k=e._1,l=e._0,m=i(ta,l,d.queues);return"Nothing"===m.ctor?Jc(j(Fd,l,Vd(k),d)):j(I,a(function(a,b){return i(Id["&>"],i(yd,k,a),b)}),Jc(i(Ed,l,j(Fd,l,Vd(k),d))),m._0);default:var n=e._0,o=i(ta,n,d.sockets);if("Nothing"===o.ctor)return Jc(d);if("Opening"===o._0.ctor){var p=o._0._0;return i(Gc,function(a){return Jc(j(Fd,n,i(Wd,p+1,a),d))},j(ae,b,p+1,n))}return Jc(d)}});zb.effectManagers.WebSocket={pkg:"elm-lang/websocket",init:Nd,onEffects:be,onSelfMsg:ce,tag:"fx",cmdMap:Qd,subMap:Ud};var de=function(b){var c=a(function(a,b){var c=a;return p.eq(b,c._0)?lb(c._1):kb("")}),d=function(a){return sb(i(O,function(b){return b(a)},i(O,c,b)))};return i(Cc,"keydown",i(jb,d,yc))},ee=27,fe=13,ge=c(function(a,b,c,d){return{name:a,fullName:b,$private:c,owner:d}}),he=h(function(a,b,c,d,e,f,g,h,i){return{id:a,htmlUrl:b,title:c,state:d,comments:e,body:f,labels:g,url:h,assignees:i}}),ie=b(function(a,b,c){return{id:a,login:b,avatarUrl:c}}),je=k(pb,ie,i(tb,"id",wb),i(tb,"login",yb),i(tb,"avatar_url",yb)),ke=l(ob,ge,i(tb,"name",yb),i(tb,"full_name",yb),i(tb,"private",xb),i(tb,"owner",je)),le=a(function(a,b){var c=i(s["++"],"https://api.github.com/user/repos?access_token=",a);return i(wd,b,i(ud,c,vb(ke)))}),me=b(function(a,b,c){return{name:a,color:b,url:c}}),ne=k(pb,me,i(tb,"name",yb),i(tb,"color",yb),i(tb,"url",yb)),oe=j(Ob,"assignees",vb(je),j(Ob,"url",yb,j(Ob,"labels",vb(ne),k(Nb,"body",yb,"",j(Ob,"comments",wb,j(Ob,"state",yb,j(Ob,"title",yb,j(Ob,"html_url",yb,j(Ob,"id",wb,Kb(he)))))))))),pe=d(function(a,b,c,d,e){var f=i(s["++"],"https://api.github.com/repos/",i(s["++"],b,i(s["++"],"/",i(s["++"],c,i(s["++"],"/issues/",i(s["++"],t(d),i(s["++"],"?access_token=",a)))))));return i(wd,e,i(ud,f,oe))}),qe=b(function(a,b,c){var d=i(s["++"],b,i(s["++"],"?access_token=",a));return i(wd,c,i(ud,d,oe))}),re=a(function(a,b){var c=i(s["++"],"https://api.github.com/user/issues?filter=all&state=all&sort=created&per_page=500&access_token=",a);return i(wd,b,i(ud,c,vb(oe)))}),se=i(ub,{ctor:"::",_0:"items",_1:{ctor:"[]"}},vb(oe)),te=b(function(a,b,c){var d=function(a){return j(id,md,kd("\\S+"),function(b){return a})},e=i(d,b,"+"),f=i(s["++"],"https://api.github.com/search/issues?q=",i(s["++"],e,i(s["++"],"&access_token=",a)));return i(wd,c,i(ud,f,se))}),ue=function(a){var b=a;switch(b.ctor){case"NotDragging":return B;case"Dragging":return B;default:return C(b._1)}},ve=function(a){var b=a;switch(b.ctor){case"NotDragging":return B;case"Dragging":return C(b._0);default:return C(b._0)}},we=a(function(a,b){return{ctor:"DraggedOver",_0:a,_1:b}}),xe=function(a){return{ctor:"Dragging",_0:a}},ye={ctor:"NotDragging"},ze=ye,Ae=b(function(a,b,c){var d={ctor:"_Tuple3",_0:b,_1:c,_2:a};a:do{if("_Tuple3"!==d.ctor)break a;switch(d._0.ctor){case"DragStart":return{ctor:"_Tuple2",_0:xe(d._0._0),_1:B};case"DragEnd":return"DraggedOver"===d._1.ctor&&d._2===!0?{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._1._1})}:{ctor:"_Tuple2",_0:ye,_1:B};case"DragEnter":switch(d._1.ctor){case"Dragging":return{ctor:"_Tuple2",_0:i(we,d._1._0,d._0._0),_1:B};case"DraggedOver":return{ctor:"_Tuple2",_0:i(we,d._1._0,d._0._0),_1:B};default:break a}case"DragLeave":if("DraggedOver"===d._1.ctor&&d._2===!1)return p.eq(d._0._0,d._1._1)?{ctor:"_Tuple2",_0:xe(d._1._0),_1:B}:{ctor:"_Tuple2",_0:c,_1:B};break a;default:switch(d._1.ctor){case"Dragging":return{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._0._0})};case"DraggedOver":return{ctor:"_Tuple2",_0:ye,_1:C({ctor:"_Tuple2",_0:d._1._0,_1:d._0._0})};default:break a}}}while(!1);return{ctor:"_Tuple2",_0:c,_1:B}}),Be=(Ae(!1),Ae(!0)),Ce=function(a){return{ctor:"Drop",_0:a}},De=function(a){return{ctor:"DragLeave",_0:a}},Ee=function(a){return{ctor:"DragEnter",_0:a}},Fe=a(function(a,b){return{ctor:"::",_0:i(Cc,"dragenter",lb(a(Ee(b)))),_1:{ctor:"::",_0:i(Cc,"dragleave",lb(a(De(b)))),_1:{ctor:"::",_0:j(Bc,"drop",{stopPropagation:!0,preventDefault:!0},lb(a(Ce(b)))),_1:{ctor:"::",_0:i(kc,"ondragover","event.stopPropagation(); event.preventDefault();"),_1:{ctor:"[]"}}}}}}),Ge={ctor:"DragEnd"},He=function(a){return{ctor:"DragStart",_0:a}},Ie=a(function(a,b){return{ctor:"::",_0:i(kc,"draggable","true"),_1:{ctor:"::",_0:i(Cc,"dragstart",lb(a(He(b)))),_1:{ctor:"::",_0:i(Cc,"dragend",lb(a(Ge))),_1:{ctor:"::",_0:i(kc,"ondragstart","event.dataTransfer.setData('text/plain', '');"),_1:{ctor:"[]"}}}}}}),Je=function(a){return cb({ctor:"::",_0:{ctor:"_Tuple2",_0:"position",_1:eb(a.position)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"order",_1:eb(a.order)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"issueUrl",_1:fb(a.issue.url)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"issueId",_1:eb(a.issue.id)},_1:{ctor:"[]"}}}}})},Ke=function(a){return cb({ctor:"::",_0:{ctor:"_Tuple2",_0:"cards",_1:bb(i(O,Je,a.cards))},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"rows",_1:eb(a.rows)},_1:{ctor:"[]"}}})},Le={position:1,order:0},Me={id:0,url:"http:///issue-without-url"},Ne={ctor:"::",_0:"Story",_1:{ctor:"::",_0:"To do",_1:{ctor:"::",_0:"In progress",_1:{ctor:"::",_0:"Done",_1:{ctor:"[]"}}}}},Oe=(a(function(a,b){return{githubToken:a,websocketAddress:b}}),a(function(a,b){return{id:a,url:b}}),a(function(a,b){return{position:a,order:b}})),Pe=c(function(a,b,c,d){return{position:a,order:b,issue:c,askDelete:d}}),Qe=a(function(a,b){return{rows:a,cards:b}}),Re=c(function(a,b,c,d){return{position:a,order:b,issueUrl:c,issueId:d}}),Se=l(ob,Re,i(tb,"position",wb),i(tb,"order",wb),i(tb,"issueUrl",yb),i(tb,"issueId",wb)),Te=j(qb,Qe,i(tb,"rows",wb),i(tb,"cards",vb(Se))),Ue=function(a){return{ctor:"WsMessage",_0:a}},Ve={ctor:"CloseError"},We=function(a){return{ctor:"ShowIcelog",_0:a}},Xe=function(a){return{ctor:"IcelogFetched",_0:a}},Ye=function(a){return{ctor:"IcelogSearchChanged",_0:a}},Ze={ctor:"QueryIcelog"},$e=function(a){return{ctor:"RepositoriesFetched",_0:a}},_e=a(function(a,b){return{ctor:"IssueRefreshed",_0:a,_1:b}}),af=a(function(a,b){return{ctor:"IssueFetched",_0:a,_1:b}}),bf=function(a){return{ctor:"DelIssueCardCancel",_0:a}},cf=function(a){return{ctor:"DelIssueCardConfirm",_0:a}},df=function(a){return{ctor:"DelIssueCard",_0:a}},ef=function(a){return{ctor:"DragDrop",_0:a}},ff=b(function(a,b,c){return j(qe,b,c,af(a))}),gf=b(function(a,b,c){return j(qe,b,c,_e(a))}),hf=(d(function(a,b,c,d,e){return l(pe,b,c,d,e,af(a))}),function(a){var b=i(gb,2,Ke(a));return i(Pd,a.flags.websocketAddress,b)}),jf=c(function(a,b,c,d){var e=j(ff,b,a,c.url);return{ctor:"_Tuple2",_0:d,_1:e}}),kf=b(function(a,b,c){var d=function(c){return p.eq(c.issue.id,b.id)?p.update(c,{position:a.position,order:a.order}):c};return{ctor:"_Tuple2",_0:i(O,d,c),_1:Cb}}),lf=a(function(a,b){return i(G,function(b){return p.eq(b.issue.id,a.id)},b)}),mf=function(b){var c=a(function(a,b){return p.cmp(a.position,b.position)>0?y:p.cmp(a.position,b.position)<0?z:p.cmp(a.order,b.order)>0?y:z});return i(E,c,b)},nf=function(b){var c=a(function(a,b){return p.update(b,{order:2*a})}),d=i(_,1,200),e=mf(b);return j(F,c,d,e)},of=function(a){var b=u(J(Ne)),c=u(i(A,0,K(i(O,function(a){return a.position},a.cards)))),d=v((c+1)/b);return p.update(a,{rows:d+1})},pf=a(function(a,b){var c=a;switch(c.ctor){case"CloseError":return{ctor:"_Tuple2",_0:p.update(b,{error:B}),_1:Cb};case"RepositoriesFetched":return"Ok"===c._0.ctor?{ctor:"_Tuple2",_0:p.update(b,{repositories:c._0._0}),_1:Cb}:{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._0._0))}),_1:Cb};case"WsMessage":var d={ctor:"_Tuple2",_0:i(nb,i(jb,function(a){return p.eq(a,"error")?i(tb,"error",yb):kb("not an error message")},i(tb,"type",yb)),c._0),_1:i(nb,Te,c._0)};if("Ok"===d._0.ctor)return{ctor:"_Tuple2",_0:p.update(b,{error:C(d._0._0)}),_1:Cb};if("Err"===d._1.ctor)return{ctor:"_Tuple2",_0:p.update(b,{error:C(d._1._0)}),_1:Cb};var e=d._1._0,f=function(a){return a},g=$a(i(O,function(a){return{ctor:"_Tuple2",_0:a.issue.id,_1:a}},b.cards)),h=function(a){var c=i(ta,a.issueId,g);return"Nothing"===c.ctor?{ctor:"_Tuple2",_0:B,_1:j(gf,i(Oe,a.position,a.order),b.flags.githubToken,a.issueUrl)}:{ctor:"_Tuple2",_0:C(p.update(c._0,{position:a.position})),_1:Cb}},l=V(i(O,h,e.cards)),m=l._0,n=l._1,o=i(R,f,m),q=of(p.update(b,{rows:e.rows,cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:Bb(n)};case"QueryIcelog":return{ctor:"_Tuple2",_0:p.update(b,{icelogFetching:!0}),_1:j(te,b.flags.githubToken,b.icelogQuery,Xe)};case"IcelogSearchChanged":return{ctor:"_Tuple2",_0:p.update(b,{icelogQuery:c._0}),_1:Cb};case"IssueFetched":if("Ok"===c._1.ctor){var r=c._0,u=c._1._0,v=i(P,function(a){return!p.eq(a.issue.id,u.id)},b.cards),w=k(Pe,r.position,r.order,u,!1),o=i(s["++"],v,{ctor:"::",_0:w,_1:{ctor:"[]"}}),q=of(p.update(b,{cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:hf(q)}}return{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._1._0))}),_1:Cb};case"IcelogFetched":return"Ok"===c._0.ctor?{ctor:"_Tuple2",_0:p.update(b,{icelog:c._0._0,icelogFetching:!1}),_1:Cb}:{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._0._0)),icelogFetching:!1}),_1:Cb};case"ShowIcelog":return{ctor:"_Tuple2",_0:p.update(b,{showIcelog:c._0}),_1:Cb};case"IssueRefreshed":if("Ok"===c._1.ctor){var x=c._0,y=c._1._0,v=i(P,function(a){return!p.eq(a.issue.id,y.id)},b.cards),w=k(Pe,x.position,x.order,y,!1),o=i(s["++"],v,{ctor:"::",_0:w,_1:{ctor:"[]"}}),q=of(p.update(b,{cards:nf(o)}));return{ctor:"_Tuple2",_0:q,_1:Cb}}return{ctor:"_Tuple2",_0:p.update(b,{error:C(t(c._1._0))}),_1:Cb};case"DragDrop":var z=i(Be,c._0,b.dragDrop),D=z._0,E=z._1,F=i(A,Me,ve(D)),G=i(A,Le,ue(D)),H=i(lf,F,b.cards)?j(kf,G,F,b.cards):p.eq(F,Me)?{ctor:"_Tuple2",_0:b.cards,_1:Cb}:k(jf,b.flags.githubToken,G,F,b.cards),o=H._0,I=H._1,q=of(p.update(b,{dragDrop:D,cards:nf(o)})),J=function(){var a=E;return"Just"===a.ctor?hf(q):Cb}();return{ctor:"_Tuple2",_0:q,_1:Bb({ctor:"::",_0:J,_1:{ctor:"::",_0:I,_1:{ctor:"[]"}}})};case"DelIssueCard":var K=function(a){return p.eq(a.issue.id,c._0)?p.update(a,{askDelete:!0}):p.update(a,{askDelete:!1})},o=i(O,K,b.cards);return{ctor:"_Tuple2",_0:p.update(b,{cards:o}),_1:Cb};case"DelIssueCardConfirm":var o=i(P,function(a){return!p.eq(a.issue.id,c._0)},b.cards),q=of(p.update(b,{cards:o}));return{ctor:"_Tuple2",_0:q,_1:hf(q)};default:var o=i(O,function(a){return p.update(a,{askDelete:!1})},b.cards);return{ctor:"_Tuple2",_0:p.update(b,{cards:o}),_1:Cb}}}),qf=function(a){return i(Oe,a.position,a.order)},rf=function(a){return{id:a.id,url:a.url}},sf=function(a){return rf(a.issue)},tf=function(a){var b=M(j(jd,ld(1),kd("repos/[^/]+/([^/]+)/issues/(\\d+)$"),a)),c=b;return"Nothing"===c.ctor?"":i(ka,"/",i(O,A(""),c._0.submatches))},uf=function(a){return i(fc,{ctor:"::",_0:nc(i(s["++"],"fa fa-",a)),_1:{ctor:"::",_0:i(kc,"aria-hidden","true"),_1:{ctor:"[]"}}},{ctor:"[]"})},vf=function(a){var b={ctor:"::",_0:xc({ctor:"::",_0:{ctor:"_Tuple2",_0:"color",_1:i(s["++"],"#",a.color)},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:"border",_1:i(s["++"],"1px solid #",a.color)},_1:{ctor:"[]"}}}),_1:{ctor:"[]"}};return i(gc,{ctor:"::",_0:nc("card-label"),_1:b},{ctor:"::",_0:_b(a.name),_1:{ctor:"[]"}})},wf=function(a){var b=i(s["++"],a.avatarUrl,"&s=18");return i(hc,{ctor:"::",_0:pc(b),_1:{ctor:"::",_0:nc("avatar"),_1:{ctor:"[]"}}},{ctor:"[]"})},xf=function(a){var b=function(){var b=M(a.labels);return"Nothing"===b.ctor?"#C2E4EF":i(s["++"],"#",b._0.color)}();return xc({ctor:"::",_0:{ctor:"_Tuple2",_0:"border-left",_1:i(s["++"],"6px solid ",b)},_1:{ctor:"[]"}})},yf=a(function(a,b){var c=b.askDelete?i(gc,{ctor:"::",_0:nc("card-remove"),_1:{ctor:"[]"}},{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("card-remove-yes"),_1:{ctor:"::",_0:Ec(cf(b.issue.id)),_1:{ctor:"::",_0:oc("Confirm and remove from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("trash-o"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("card-remove-no"),_1:{ctor:"::",_0:Ec(bf(b.issue.id)),_1:{ctor:"::",_0:oc("Cancel removing from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("ban"),_1:{ctor:"[]"}}),_1:{ctor:"[]"}}}):i(gc,{ctor:"::",_0:Ec(df(b.issue.id)),_1:{ctor:"::",_0:nc("card-remove"),_1:{ctor:"::",_0:oc("Remove from the board"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("trash-o"),_1:{ctor:"[]"}}),d=i(O,wf,b.issue.assignees),e=i(O,vf,b.issue.labels),f=i(Fe,ef,qf(b)),g=i(Ie,ef,sf(b)),h=nc(p.eq(i(A,Me,a),sf(b))?"card-placeholder":""),j=i(s["++"],{ctor:"::",_0:h,_1:{ctor:"::",_0:xf(b.issue),_1:{ctor:"::",_0:nc("card"),_1:g}}},f);return i(cc,j,{ctor:"::",_0:c,_1:{ctor:"::",_0:i(dc,{ctor:"::",_0:oc(b.issue.body),_1:{ctor:"::",_0:nc(i(s["++"],"card-title state-",b.issue.state)),_1:{ctor:"::",_0:sc(b.issue.htmlUrl),_1:{ctor:"::",_0:tc("_blank"),_1:{ctor:"[]"}}}}},{ctor:"::",_0:_b(b.issue.title),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-meta"),_1:{ctor:"[]"}},i(s["++"],e,{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},d),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(tf(b.issue.url)),_1:{ctor:"::",_0:uf("code-fork"),_1:{ctor:"[]"}}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(t(b.issue.comments)),_1:{ctor:"::",_0:uf("comments-o"),_1:{ctor:"[]"}}}),_1:{ctor:"[]"}}}})),_1:{ctor:"[]"}}}})}),zf=a(function(a,b){return i(P,function(b){return p.eq(b.position,a.position)},b)}),Af=function(a){var b=i(Fe,ef,a),c={ctor:"::",_0:nc("drop-helper"),_1:b};return i(cc,c,{ctor:"[]"})},Bf=b(function(a,b,c){var d=Af(i(Oe,c.position,1e3)),e=Af(i(Oe,c.position,0)),f={ctor:"[]"},g=i(s["++"],{ctor:"::",_0:nc("board-cell"),_1:{ctor:"[]"}},f),h=i(zf,c,b),j=i(O,yf(a),h);return i(cc,g,{ctor:"::",_0:e,_1:i(s["++"],j,{ctor:"::",_0:d,_1:{ctor:"[]"}})})}),Cf=b(function(a,b,c){var d=a,e=i(_,d._0.position,d._1.position),f=j(F,Oe,e,i(_,0,30)),g=i(O,i(Bf,b,c),f);return i(cc,{ctor:"::",_0:nc("board-row"),_1:{ctor:"[]"}},g)}),Df=function(a){var b=function(a){return i(ec,{ctor:"::",_0:nc("board-header-col"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(a),_1:{ctor:"[]"}})},c=i(O,b,a);return i(cc,{ctor:"::",_0:nc("board-header"),_1:{ctor:"[]"}},c)},Ef=function(a){var b=i(Ie,ef,rf(a)),c={ctor:"::",_0:xf(a),_1:{ctor:"::",_0:nc("card"),_1:b}},d=i(O,vf,a.labels),e=i(O,wf,a.assignees);return i(cc,c,{ctor:"::",_0:i(dc,{ctor:"::",_0:oc(a.body),_1:{ctor:"::",_0:nc(i(s["++"],"card-title state-",a.state)),_1:{ctor:"::",_0:sc(a.htmlUrl),_1:{ctor:"::",_0:tc("_blank"),_1:{ctor:"[]"}}}}},{ctor:"::",_0:_b(a.title),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-meta"),_1:{ctor:"[]"}},i(s["++"],d,{ctor:"::",_0:i(cc,{ctor:"::",
 _0:nc("card-metainfo"),_1:{ctor:"[]"}},e),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(tf(a.url)),_1:{ctor:"::",_0:uf("code-fork"),_1:{ctor:"[]"}}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("card-metainfo"),_1:{ctor:"[]"}},{ctor:"::",_0:_b(t(a.comments)),_1:{ctor:"::",_0:uf("comments-o"),_1:{ctor:"[]"}}}),_1:{ctor:"[]"}}}})),_1:{ctor:"[]"}}})},Ff=function(b){var c=a(function(a,c){return b.icelogFetching?a:c}),d=i(O,function(a){return a.issue.id},b.cards),e=function(a){return i(P,function(a){return!i(L,a.id,d)},a)},f=i(O,Ef,e(b.icelog));return i(cc,{ctor:"::",_0:nc("icelog-sidebar"),_1:{ctor:"[]"}},{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("icelog-toolbar"),_1:{ctor:"[]"}},{ctor:"::",_0:i(ic,{ctor:"::",_0:de({ctor:"::",_0:{ctor:"_Tuple2",_0:fe,_1:Ze},_1:{ctor:"::",_0:{ctor:"_Tuple2",_0:ee,_1:We(!1)},_1:{ctor:"[]"}}}),_1:{ctor:"::",_0:nc("icelog-query"),_1:{ctor:"::",_0:Dc(Ye),_1:{ctor:"::",_0:rc("Search GitHub issues"),_1:{ctor:"::",_0:qc(i(c,"feching issues...",b.icelogQuery)),_1:{ctor:"::",_0:vc(!0),_1:{ctor:"::",_0:wc(b.icelogFetching),_1:{ctor:"[]"}}}}}}}},{ctor:"[]"}),_1:{ctor:"::",_0:i(jc,{ctor:"::",_0:Ec(Ze),_1:{ctor:"::",_0:nc("icelog-query-btn"),_1:{ctor:"::",_0:wc(b.icelogFetching),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("search"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:i(bc,{ctor:"::",_0:nc("icelog-toolbar-help"),_1:{ctor:"[]"}},{ctor:"::",_0:_b("You can use "),_1:{ctor:"::",_0:i(dc,{ctor:"::",_0:tc("_blank"),_1:{ctor:"::",_0:sc("https://help.github.com/articles/searching-issues/"),_1:{ctor:"[]"}}},{ctor:"::",_0:_b("advanced search"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:_b(" formatting."),_1:{ctor:"[]"}}}}),_1:{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("toggle-icelog-btn"),_1:{ctor:"::",_0:Ec(We(!1)),_1:{ctor:"::",_0:oc("Hide icelog"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("list"),_1:{ctor:"[]"}}),_1:{ctor:"[]"}}}}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("icelog-issues"),_1:{ctor:"[]"}},f),_1:{ctor:"[]"}}})},Gf=function(a){var b=a;return"Just"===b.ctor?!1:!0},Hf=function(a){var b=function(){var b=a.error;return"Nothing"===b.ctor?i(cc,{ctor:"[]"},{ctor:"[]"}):i(cc,{ctor:"::",_0:nc("error"),_1:{ctor:"[]"}},{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("pull-right"),_1:{ctor:"::",_0:Ec(Ve),_1:{ctor:"[]"}}},{ctor:"::",_0:uf("times"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:_b(b._0),_1:{ctor:"[]"}}})}(),c=ve(a.dragDrop),d=a.showIcelog&&Gf(c)?Ff(a):_b(""),e=J(Ne),f=function(b){return j(Cf,{ctor:"_Tuple2",_0:i(Oe,e*b,0),_1:i(Oe,e*b+e-1,0)},c,a.cards)},g=i(O,f,i(_,0,a.rows-1));return i(cc,{ctor:"[]"},{ctor:"::",_0:b,_1:{ctor:"::",_0:i(cc,{ctor:"[]"},{ctor:"::",_0:i(gc,{ctor:"::",_0:nc("toggle-icelog-btn"),_1:{ctor:"::",_0:Ec(We(!a.showIcelog)),_1:{ctor:"::",_0:oc("Toggle icelog"),_1:{ctor:"[]"}}}},{ctor:"::",_0:uf("list"),_1:{ctor:"[]"}}),_1:{ctor:"::",_0:d,_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("board"),_1:{ctor:"[]"}},{ctor:"::",_0:Df(Ne),_1:g}),_1:{ctor:"[]"}}}}),_1:{ctor:"::",_0:i(cc,{ctor:"::",_0:nc("footer"),_1:{ctor:"[]"}},{ctor:"::",_0:i(dc,{ctor:"::",_0:sc("https://github.com/husio/scrumboard"),_1:{ctor:"::",_0:tc("_blank"),_1:{ctor:"[]"}}},{ctor:"::",_0:uf("github"),_1:{ctor:"::",_0:_b(" source code"),_1:{ctor:"[]"}}}),_1:{ctor:"[]"}}),_1:{ctor:"[]"}}}})},If=function(a){return i(Td,a.flags.websocketAddress,Ue)},Jf=function(a){var b=i(re,a.githubToken,Xe),c=i(le,a.githubToken,$e),d={cards:{ctor:"[]"},dragDrop:ze,rows:3,icelog:{ctor:"[]"},icelogQuery:"",icelogFetching:!1,showIcelog:!1,error:B,flags:a,repositories:{ctor:"[]"}};return{ctor:"_Tuple2",_0:d,_1:Bb({ctor:"::",_0:c,_1:{ctor:"::",_0:b,_1:{ctor:"[]"}}})}},Kf=$b({init:Jf,update:pf,view:Hf,subscriptions:If})(i(jb,function(a){return i(jb,function(b){return lb({githubToken:a,websocketAddress:b})},i(tb,"websocketAddress",yb))},i(tb,"githubToken",yb))),Lf={};if(Lf.Main=Lf.Main||{},"undefined"!=typeof Kf&&Kf(Lf.Main,"Main",void 0),"function"==typeof define&&define.amd)return void define([],function(){return Lf});if("object"==typeof module)return void(module.exports=Lf);var Mf=this.Elm;if("undefined"==typeof Mf)return void(this.Elm=Lf);for(var Nf in Lf){if(Nf in Mf)throw new Error("There are two Elm modules called `"+Nf+"` on this page! Rename one of them.");Mf[Nf]=Lf[Nf]}}).call(this);
\ No newline at end of file
@@ -103,6 +103,18 @@ decodeState =
         (field "cards" (Json.Decode.list decodeCardState))
 
 
+decodeServerError : Json.Decode.Decoder String
+decodeServerError =
+    field "type" Json.Decode.string
+        |> Json.Decode.andThen
+            (\t ->
+                if t == "error" then
+                    field "error" Json.Decode.string
+                else
+                    Json.Decode.fail "not an error message"
+            )
+
+
 type alias State =
     { rows : Int
     , cards : List CardState
@@ -22,11 +22,14 @@ update msg model =
             ( { model | error = Just (toString msg) }, Cmd.none )
 
         WsMessage content ->
-            case Json.Decode.decodeString decodeState content of
-                Err msg ->
+            case ( Json.Decode.decodeString decodeServerError content, Json.Decode.decodeString decodeState content ) of
+                ( Ok msg, _ ) ->
                     ( { model | error = Just msg }, Cmd.none )
 
-                Ok state ->
+                ( _, Err msg ) ->
+                    ( { model | error = Just msg }, Cmd.none )
+
+                ( _, Ok state ) ->
                     let
                         -- index existing cards by issue id
                         idx =
@@ -1,73 +1,478 @@
 package main
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/garyburd/redigo/redis"
 	"github.com/husio/scrumboard/server/auth"
 	"github.com/husio/scrumboard/server/cache"
+	"github.com/husio/scrumboard/server/health"
+	"github.com/husio/scrumboard/server/metrics"
+	"github.com/husio/scrumboard/server/migrate"
 	"github.com/husio/scrumboard/server/pubsub"
 	"github.com/husio/scrumboard/server/scrumboard"
 	"github.com/husio/scrumboard/server/surf"
+	_ "github.com/lib/pq"
 )
 
+var logger = surf.NewLogger(os.Stdout, "app", "main")
+
 func main() {
-	debug := env("DEBUG", "false") == "true"
-	httpPort := env("PORT", "8000")
-	staticPath := env("STATIC", "./dist")
-	redisUrl := env("REDIS_URL", "redis://localhost:6379/2")
-	templatesPath := env("TEMPLATES", "./templates/**.tmpl")
-	// registered as http://scrumboard.dev:8000 (edit your /etc/hosts)
-	githubClientId := env("GITHUB_CLIENT_ID", "f52ce2105e1023495aca")
-	githubSecret := env("GITHUB_SECRET", "8bb88273d8832e29194140c0926ccc5de1961371")
+	ctx := context.Background()
+
+	cfg, printConfig, args, err := loadConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "cannot load configuration",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	surf.ConfigureLogging(cfg.logConfig())
+
+	if printConfig {
+		raw, err := cfg.redacted()
+		if err != nil {
+			logger.Error(ctx, "cannot encode configuration",
+				"error", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", raw)
+		return
+	}
+	if cfg.Embedded {
+		if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+			logger.Error(ctx, "cannot create data directory",
+				"error", err.Error())
+			os.Exit(1)
+		}
+	}
 
 	redisPool := &redis.Pool{
 		MaxIdle:     3,
 		MaxActive:   10,
 		IdleTimeout: 90 * time.Second,
 		Wait:        true,
-		Dial:        func() (redis.Conn, error) { return redis.DialURL(redisUrl) },
+		Dial:        func() (redis.Conn, error) { return redis.DialURL(cfg.RedisURL) },
+	}
+	redisIdle := countIdleConnections(redisPool)
+
+	// redis connection is checked only if used
+	var redisChecked bool
+	redisDB := func() *redis.Pool {
+		if !redisChecked {
+			rc := redisPool.Get()
+			defer rc.Close()
+			if _, err := rc.Do("PING"); err != nil {
+				logger.Error(ctx, "cannot ping redis server",
+					"error", err.Error())
+				os.Exit(1)
+			}
+			redisChecked = true
+		}
+		return redisPool
+	}
+
+	// PostgreSQL connection is established only if used
+	var db *sql.DB
+	postgres := func() *sql.DB {
+		if db == nil {
+			db = openPostgres(cfg.DatabaseURL)
+		}
+		return db
+	}
+
+	if len(args) > 0 && args[0] == "migrate-redis-to-postgres" {
+		from := pubsub.Compress(pubsub.NewRedisSnapshotStore(redisDB(), "board:snapshot:"))
+		to := pubsub.NewPostgresSnapshotStore(postgres())
+		n, err := scrumboard.CopyRedisToPostgres(context.Background(), redisDB(), postgres(), from, to)
+		if err != nil {
+			logger.Error(ctx, "cannot copy data",
+				"copied", strconv.Itoa(n),
+				"error", err.Error())
+			os.Exit(1)
+		}
+		logger.Info(ctx, "boards copied",
+			"copied", strconv.Itoa(n))
+		return
+	}
+
+	if err := cfg.validate(); err != nil {
+		logger.Error(ctx, "invalid configuration",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	if cfg.GithubSecret == "" {
+		logger.Info(ctx, "GitHub OAuth application is not configured, login is not possible")
+	}
+
+	assets, err := surf.NewAssets(staticFiles(cfg.Static), "/static")
+	if err != nil {
+		logger.Error(ctx, "cannot load static files",
+			"error", err.Error())
+		os.Exit(1)
 	}
+	// embedded files cannot change
+	assets.Debug = cfg.Debug && cfg.Static != ""
 
-	rc := redisPool.Get()
-	defer rc.Close()
-	if _, err := rc.Do("PING"); err != nil {
-		log.Fatalf("cannot ping redis server: %s", err)
+	html := surf.LoadTemplates(cfg.Templates)
+	html.Debug = cfg.Debug
+	html.Assets = assets
+	if cfg.Debug {
+		go html.Watch(context.Background(), time.Second)
 	}
 
-	html := surf.LoadTemplates(templatesPath)
-	html.Debug = debug
-	boardStore := scrumboard.NewRedisBoardStore(redisPool)
+	var boardStore scrumboard.BoardStore
+	switch cfg.BoardStore {
+	case "redis":
+		boardStore = scrumboard.NewRedisBoardStore(redisDB())
+	case "postgres":
+		boardStore = scrumboard.NewPostgresBoardStore(postgres())
+	case "file":
+		boardStore, err = scrumboard.NewFileBoardStore(filepath.Join(cfg.DataDir, "boards.json"))
+		if err != nil {
+			logger.Error(ctx, "cannot create board store",
+				"error", err.Error())
+			os.Exit(1)
+		}
+	}
 	providers := []auth.Provider{
-		auth.GithubProvider(!debug, githubClientId, githubSecret),
+		auth.GithubProvider(!cfg.Debug, cfg.GithubClientID, cfg.GithubSecret),
+	}
+	var cacheStore cache.Cache
+	switch cfg.Cache {
+	case "redis":
+		cacheStore = cache.NewRedisCache(redisDB())
+	case "file":
+		cacheStore, err = cache.NewFileCache(filepath.Join(cfg.DataDir, "cache.json"))
+		if err != nil {
+			logger.Error(ctx, "cannot create cache",
+				"error", err.Error())
+			os.Exit(1)
+		}
+	}
+	authApp := auth.NewApp(cacheStore, html, providers, cfg.Debug)
+	var hub pubsub.Hub
+	hubOverflow, _ := pubsub.ParseOverflowPolicy(cfg.HubOverflow)
+	switch cfg.Hub {
+	case "memory":
+		hub = pubsub.NewMemoryHub(hubOverflow)
+	case "redis":
+		// required when running more than one process
+		hub = pubsub.NewRedisHub(redisDB(), hubOverflow)
+	}
+	var snapshots pubsub.SnapshotStore
+	switch cfg.SnapshotStore {
+	case "redis":
+		snapshots = pubsub.Compress(pubsub.NewRedisSnapshotStore(redisDB(), "board:snapshot:"))
+	case "file":
+		snapshots, err = pubsub.NewFileSnapshotStore(cfg.SnapshotDir)
+		if err != nil {
+			logger.Error(ctx, "cannot create snapshot store",
+				"error", err.Error())
+			os.Exit(1)
+		}
+		snapshots = pubsub.Compress(snapshots)
+	case "postgres":
+		// PostgreSQL is compressing large values itself
+		snapshots = pubsub.NewPostgresSnapshotStore(postgres())
+	case "memory":
+		snapshots = pubsub.NewMemorySnapshotStore()
+	}
+	snapshotHub := pubsub.Snapshot(scrumboard.TrackActivity(snapshots, boardStore), hub, cfg.SnapshotInterval)
+	hub = snapshotHub
+	// boards with no activity for given number of months are removed
+	if cfg.BoardRetentionMonths > 0 {
+		if cfg.BoardArchiveDir != "" {
+			if err := os.MkdirAll(cfg.BoardArchiveDir, 0700); err != nil {
+				logger.Error(ctx, "cannot create archive directory",
+					"error", err.Error())
+				os.Exit(1)
+			}
+		}
+		maxIdle := time.Duration(cfg.BoardRetentionMonths) * 30 * 24 * time.Hour
+		janitor := scrumboard.NewJanitor(boardStore, snapshots, maxIdle, cfg.BoardArchiveDir)
+		go janitor.Run(context.Background(), time.Hour)
+	}
+
+	scrumBoardApp := scrumboard.NewApp(html, authApp, boardStore, hub, cfg.Debug)
+
+	healthApp := health.NewApp()
+	healthApp.Register("templates", func(context.Context) error { return html.Err() })
+	healthApp.Register("hub", snapshotHub.Check)
+	if redisChecked {
+		healthApp.Register("redis", pingRedis(redisPool))
+
+		metrics.NewGaugeFunc("scrumboard_redis_pool_active_connections",
+			"Number of open redis connections, including idle ones.",
+			func() float64 { return float64(redisPool.ActiveCount()) })
+		metrics.NewGaugeFunc("scrumboard_redis_pool_idle_connections",
+			"Number of idle redis connections.",
+			func() float64 { return float64(redisIdle()) })
+	}
+	if db != nil {
+		healthApp.Register("postgres", db.PingContext)
 	}
-	cache := cache.NewRedisCache(redisPool)
-	authApp := auth.NewApp(cache, html, providers, debug)
-	hub := pubsub.Snapshot(redisPool, pubsub.NewMemoryHub())
-	scrumBoardApp := scrumboard.NewApp(html, authApp, boardStore, hub, debug)
 
 	rt := surf.NewRouter()
-	rt.Get(`/`, scrumBoardApp)
-	rt.Any(`/new`, scrumBoardApp)
-	rt.Get(`/ws/.*`, scrumBoardApp)
-	rt.Get(`/b/.*`, scrumBoardApp)
-	rt.Get(`/login`, authApp)
-	rt.Get(`/login/.*`, authApp)
-	rt.Get(`/logout`, authApp)
-	rt.Get(`/static/.*`, http.StripPrefix("/static", http.FileServer(http.Dir(staticPath))))
-
-	log.Printf("starting HTTP server: 0.0.0.0:%s", httpPort)
-	if err := http.ListenAndServe("0.0.0.0:"+httpPort, rt); err != nil {
-		log.Fatalf("server failed: %s", err)
+	rt.Use(
+		surf.RequestIDMiddleware,
+		surf.AccessLogMiddleware(surf.NewLogger(os.Stdout, "app", "http")),
+		surf.RecoverMiddleware(html, logger),
+		surf.SecurityHeadersMiddleware(securityPolicy(cfg.Debug)),
+	)
+	// every application owns its routes and serves only paths it knows
+	rt.Mount(`/`, scrumBoardApp)
+	rt.Mount(`/`, authApp)
+	rt.Mount(`/`, healthApp)
+	rt.Mount(`/static`, assets)
+	html.Routes = rt
+
+	srv := &http.Server{
+		Addr:    cfg.addr(),
+		Handler: rt,
+		// long lived websocket and event stream connections are not
+		// affected by timeouts
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  2 * time.Minute,
+	}
+
+	// board IDs are used as metric labels and are enough to access the
+	// board, so metrics are served only on a separate, private address
+	var metricsSrv *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsSrv = &http.Server{
+			Addr:    cfg.MetricsAddr,
+			Handler: metrics.Handler(),
+		}
+		go func() {
+			logger.Info(ctx, "starting metrics server",
+				"addr", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != http.ErrServerClosed {
+				logger.Error(ctx, "metrics server failed",
+					"error", err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+		<-sigc
+		logger.Info(ctx, "shutting down",
+			"drain_delay", cfg.ShutdownDrainDelay.String(),
+			"timeout", cfg.ShutdownTimeout.String())
+
+		// report the process as not ready while still serving, so that
+		// load balancers stop routing traffic to it before connections
+		// are refused. Another signal skips the delay.
+		healthApp.Drain()
+		select {
+		case <-time.After(cfg.ShutdownDrainDelay):
+		case <-sigc:
+		}
+
+		ctx, done := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer done()
+
+		// stop accepting connections and disconnect all board clients,
+		// so that they reconnect to another server
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scrumBoardApp.Shutdown(ctx); err != nil {
+				logger.Error(ctx, "cannot disconnect board clients",
+					"error", err.Error())
+			}
+		}()
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Error(ctx, "cannot shutdown HTTP server",
+				"error", err.Error())
+		}
+		wg.Wait()
+		if metricsSrv != nil {
+			metricsSrv.Close()
+		}
+
+		// write all pending board states before exiting, even if
+		// disconnecting clients used up the whole shutdown timeout
+		flushCtx, flushDone := context.WithTimeout(context.Background(), cfg.ShutdownFlushTimeout)
+		defer flushDone()
+		if err := snapshotHub.Close(flushCtx); err != nil {
+			logger.Error(flushCtx, "cannot flush snapshots",
+				"error", err.Error())
+		}
+	}()
+
+	logger.Info(ctx, "starting HTTP server",
+		"addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Error(ctx, "server failed",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	<-stopped
+}
+
+// contentSecurityPolicy allows only inline scripts with the nonce. Board
+// client is using GitHub API directly and icons are loaded from CDN. Websocket
+// connections are made to the same host and are allowed by 'self'.
+const contentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'nonce-{nonce}'; " +
+	"style-src 'self' 'unsafe-inline' https://maxcdn.bootstrapcdn.com; " +
+	"font-src 'self' https://maxcdn.bootstrapcdn.com; " +
+	"img-src 'self' https: data:; " +
+	"connect-src 'self' https://api.github.com; " +
+	"object-src 'none'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'"
+
+func securityPolicy(debug bool) surf.SecurityPolicy {
+	p := surf.SecurityPolicy{
+		ContentSecurityPolicy: contentSecurityPolicy,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "same-origin",
+	}
+	if !debug {
+		// local development is using plain HTTP
+		p.HSTSMaxAge = 365 * 24 * time.Hour
 	}
+	return p
 }
 
-func env(name, fallback string) string {
-	if v := os.Getenv(name); v != "" {
-		return v
+// pingRedis returns check of the redis server connection.
+func pingRedis(rp *redis.Pool) health.Check {
+	return func(ctx context.Context) error {
+		errc := make(chan error, 1)
+		go func() {
+			rc := rp.Get()
+			defer rc.Close()
+			_, err := rc.Do("PING")
+			errc <- err
+		}()
+
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// countIdleConnections instruments the pool, so that connections kept idle
+// can be counted, and returns the counter. Pool must not be used yet.
+//
+// Vendored pool does not expose the idle connections count. Connection is
+// idle from the moment it is returned to the pool, which always checks its
+// Err, until it is borrowed again, which always calls TestOnBorrow, or until
+// it is closed.
+func countIdleConnections(rp *redis.Pool) func() int {
+	var (
+		mu   sync.Mutex
+		idle int
+	)
+
+	dial := rp.Dial
+	rp.Dial = func() (redis.Conn, error) {
+		c, err := dial()
+		if err != nil {
+			return nil, err
+		}
+		return &countedConn{Conn: c, mu: &mu, idle: &idle}, nil
+	}
+
+	test := rp.TestOnBorrow
+	rp.TestOnBorrow = func(c redis.Conn, t time.Time) error {
+		if cc, ok := c.(*countedConn); ok {
+			cc.setIdle(false)
+		}
+		if test != nil {
+			return test(c, t)
+		}
+		return nil
+	}
+
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return idle
+	}
+}
+
+type countedConn struct {
+	redis.Conn
+
+	mu     *sync.Mutex
+	idle   *int
+	isIdle bool
+}
+
+func (c *countedConn) setIdle(idle bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case idle && !c.isIdle:
+		*c.idle++
+	case !idle && c.isIdle:
+		*c.idle--
+	}
+	c.isIdle = idle
+}
+
+// Err is called by the pool when the connection is returned.
+func (c *countedConn) Err() error {
+	c.setIdle(true)
+	return c.Conn.Err()
+}
+
+func (c *countedConn) Close() error {
+	c.setIdle(false)
+	return c.Conn.Close()
+}
+
+// openPostgres returns PostgreSQL database connection with all schema
+// migrations applied.
+func openPostgres(url string) *sql.DB {
+	ctx, done := context.WithTimeout(context.Background(), 30*time.Second)
+	defer done()
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		logger.Error(ctx, "cannot open database",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		logger.Error(ctx, "cannot ping database",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	if err := migrate.Run(ctx, db, "scrumboard", scrumboard.PostgresMigrations); err != nil {
+		logger.Error(ctx, "cannot migrate database",
+			"error", err.Error())
+		os.Exit(1)
+	}
+	if err := migrate.Run(ctx, db, "pubsub", pubsub.PostgresMigrations); err != nil {
+		logger.Error(ctx, "cannot migrate database",
+			"error", err.Error())
+		os.Exit(1)
 	}
-	return fallback
+	return db
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestCountIdleConnections(t *testing.T) {
+	rp := redistest.NewPool()
+	rp.MaxIdle = 2
+	idle := countIdleConnections(rp)
+
+	conns := []redis.Conn{rp.Get(), rp.Get(), rp.Get()}
+	if n := idle(); n != 0 {
+		t.Fatalf("want no idle connections, got %d", n)
+	}
+
+	conns[0].Close()
+	conns[1].Close()
+	if n := idle(); n != 2 {
+		t.Fatalf("want 2 idle connections, got %d", n)
+	}
+	// connection over the idle limit is closed
+	conns[2].Close()
+	if n, active := idle(), rp.ActiveCount(); n != 2 || active != 2 {
+		t.Fatalf("want 2 idle of 2 active connections, got %d of %d", n, active)
+	}
+
+	c := rp.Get()
+	if n := idle(); n != 1 {
+		t.Fatalf("want 1 idle connection, got %d", n)
+	}
+	c.Close()
+
+	rp.Close()
+	if n := idle(); n != 0 {
+		t.Fatalf("want no idle connections after close, got %d", n)
+	}
+}
@@ -0,0 +1,39 @@
+package atomicfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to the file at given path. Data is written to a
+// temporary file first, synced and only then the file is replaced, so that
+// interrupted write never leaves a corrupted file.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	fd, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-")
+	if err != nil {
+		return fmt.Errorf("cannot create file: %s", err)
+	}
+	defer os.Remove(fd.Name())
+
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		return fmt.Errorf("cannot write file: %s", err)
+	}
+	if err := fd.Chmod(perm); err != nil {
+		fd.Close()
+		return fmt.Errorf("cannot chmod file: %s", err)
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return fmt.Errorf("cannot sync file: %s", err)
+	}
+	if err := fd.Close(); err != nil {
+		return fmt.Errorf("cannot close file: %s", err)
+	}
+	if err := os.Rename(fd.Name(), path); err != nil {
+		return fmt.Errorf("cannot rename file: %s", err)
+	}
+	return nil
+}
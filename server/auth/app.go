@@ -11,7 +11,7 @@ import (
 type AuthApp struct {
 	cache     cache.Cache
 	html      surf.Renderer
-	mux       http.Handler
+	mux       *surf.Router
 	log       surf.Logger
 	debug     bool
 	providers map[string]Provider
@@ -39,10 +39,10 @@ func NewApp(
 		providers: providersmap,
 	}
 
-	rt.Get(`/login`, app.login)
-	rt.Get(`/login/<method>`, app.loginOAuth2)
-	rt.Get(`/login/<method>/success`, app.loginOAuth2Callback)
-	rt.Get(`/logout`, app.logout)
+	rt.Get(`/login`, app.login).Name("login")
+	rt.Get(`/login/<method>`, app.loginOAuth2).Name("login-provider")
+	rt.Get(`/login/<method>/success`, app.loginOAuth2Callback).Name("login-callback")
+	rt.Get(`/logout`, app.logout).Name("logout")
 
 	return app
 }
@@ -50,3 +50,13 @@ func NewApp(
 func (app *AuthApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	app.mux.ServeHTTP(w, r)
 }
+
+// Match returns true if application is serving given path.
+func (app *AuthApp) Match(path string) bool {
+	return app.mux.Match(path)
+}
+
+// URL returns path of the named application route.
+func (app *AuthApp) URL(name string, args ...string) (string, error) {
+	return app.mux.URL(name, args...)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/husio/scrumboard/server/cache"
+	"github.com/husio/scrumboard/server/metrics"
 	"github.com/husio/scrumboard/server/surf"
 
 	"golang.org/x/oauth2"
@@ -17,15 +18,25 @@ import (
 func (app *AuthApp) logout(w http.ResponseWriter, r *http.Request) {
 	// write delete cookie
 	http.SetCookie(w, &http.Cookie{
-		Name:    sessionCookieName,
-		Value:   "",
-		Path:    "/",
-		MaxAge:  -1,
-		Expires: time.Time{},
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Expires:  time.Time{},
+		HttpOnly: true,
+		Secure:   !app.debug,
+		SameSite: http.SameSiteLaxMode,
 	})
 
 	app.log.Info(r.Context(), "logging out")
-	http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+	indexURL, err := surf.ReverseURL(r.Context(), "index")
+	if err != nil {
+		app.log.Error(r.Context(), "cannot build index URL",
+			"error", err.Error())
+		app.html.RenderDefault(w, http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, indexURL, http.StatusTemporaryRedirect)
 }
 
 const sessionCookieName = "sid"
@@ -69,10 +80,14 @@ func (app *AuthApp) loginOAuth2(w http.ResponseWriter, r *http.Request) {
 
 	url := provider.Config(r).AuthCodeURL(state, oauth2.AccessTypeOnline)
 	http.SetCookie(w, &http.Cookie{
-		Name:    stateCookie,
-		Path:    "/",
-		Value:   state,
-		Expires: time.Now().Add(time.Minute * 10),
+		Name:     stateCookie,
+		Path:     "/",
+		Value:    state,
+		Expires:  time.Now().Add(time.Minute * 10),
+		HttpOnly: true,
+		Secure:   !app.debug,
+		// must be sent when the provider redirects back
+		SameSite: http.SameSiteLaxMode,
 	})
 
 	err := app.cache.Set(ctx, "auth:login:"+state, &authInfo{
@@ -97,6 +112,9 @@ type authInfo struct {
 
 const stateCookie = "oauthState"
 
+var loginCounter = metrics.NewCounter("scrumboard_auth_logins_total",
+	"Number of OAuth login attempts, by provider and result.", "provider", "result")
+
 func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -105,6 +123,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 		app.log.Info(ctx, "invalid oauth state",
 			"expected", state,
 			"got", r.FormValue("state"))
+		loginCounter.Inc("unknown", "invalid_state")
 		app.html.RenderDefault(w, http.StatusBadRequest)
 		return
 	} else {
@@ -115,6 +134,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 		app.log.Info(ctx, "invalid oauth state",
 			"cookie", state,
 			"form", r.FormValue("state"))
+		loginCounter.Inc("unknown", "invalid_state")
 		app.html.RenderDefault(w, http.StatusBadRequest)
 		return
 	}
@@ -124,11 +144,14 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 	case nil:
 		// all good
 	case cache.ErrMiss:
+		// login attempt expired
+		loginCounter.Inc("unknown", "invalid_state")
 		app.html.RenderDefault(w, http.StatusBadRequest)
 		return
 	default:
 		app.log.Error(ctx, "cannot get auth data from cache",
 			"error", err.Error())
+		loginCounter.Inc("unknown", "error")
 		app.html.RenderDefault(w, http.StatusInternalServerError)
 		return
 	}
@@ -137,6 +160,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 		app.log.Info(ctx, "invalid oauth state",
 			"cache", state,
 			"form", r.FormValue("state"))
+		loginCounter.Inc(info.Provider, "invalid_state")
 		app.html.RenderDefault(w, http.StatusBadRequest)
 		return
 	}
@@ -145,6 +169,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 	if !ok {
 		app.log.Error(ctx, "provider not found",
 			"provider", info.Provider)
+		loginCounter.Inc("unknown", "error")
 		app.html.RenderDefault(w, http.StatusBadRequest)
 		return
 	}
@@ -154,6 +179,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		app.log.Error(ctx, "oauth2 exchange failed",
 			"error", err.Error())
+		loginCounter.Inc(provider.Codename, "exchange_failed")
 		app.html.RenderDefault(w, http.StatusInternalServerError)
 		return
 	}
@@ -161,6 +187,7 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 	if !token.Valid() {
 		app.log.Error(ctx, "invalid token",
 			"token", fmt.Sprint(token))
+		loginCounter.Inc(provider.Codename, "exchange_failed")
 		app.html.RenderDefault(w, http.StatusInternalServerError)
 		return
 	}
@@ -173,12 +200,14 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 		app.log.Error(ctx, "invalid user profile",
 			"provider", provider.Codename,
 			"error", err.Error())
+		loginCounter.Inc(provider.Codename, "invalid_profile")
 		app.html.RenderDefault(w, http.StatusNotAcceptable)
 		return
 	default:
 		app.log.Error(ctx, "cannot GET user information",
 			"provider", provider.Codename,
 			"error", err.Error())
+		loginCounter.Inc(provider.Codename, "error")
 		app.html.RenderDefault(w, http.StatusInternalServerError)
 		return
 	}
@@ -193,19 +222,31 @@ func (app *AuthApp) loginOAuth2Callback(w http.ResponseWriter, r *http.Request)
 	if err := app.cache.Set(ctx, "auth:session:"+sessionToken, &account, time.Hour*12); err != nil {
 		app.log.Error(ctx, "cannot set token",
 			"error", err.Error())
+		loginCounter.Inc(provider.Codename, "error")
 		app.html.RenderDefault(w, http.StatusInternalServerError)
 		return
 	}
 
 	http.SetCookie(w, &http.Cookie{
-		Name:  sessionCookieName,
-		Value: string(sessionToken),
-		Path:  "/",
+		Name:     sessionCookieName,
+		Value:    string(sessionToken),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   !app.debug,
+		SameSite: http.SameSiteLaxMode,
 	})
 
+	loginCounter.Inc(provider.Codename, "success")
+
 	next := info.Next
 	if next == "" {
-		next = "/"
+		next, err = surf.ReverseURL(ctx, "index")
+		if err != nil {
+			app.log.Error(ctx, "cannot build index URL",
+				"error", err.Error())
+			app.html.RenderDefault(w, http.StatusInternalServerError)
+			return
+		}
 	}
 	http.Redirect(w, r, next, http.StatusTemporaryRedirect)
 }
@@ -218,6 +259,15 @@ func genToken() string {
 	return hex.EncodeToString(b)
 }
 
+// SessionID returns identifier of the request session or empty string.
+func SessionID(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
 // CurrentAccount returns account instance assigned to current session.
 // ErrNoSession is returned if session does not exist or cannot be returned.
 func (app *AuthApp) CurrentAccount(r *http.Request) (*Account, error) {
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/husio/scrumboard/server/surf"
+)
+
+type Authenticator interface {
+	CurrentAccount(*http.Request) (*Account, error)
+}
+
+var _ Authenticator = (*AuthApp)(nil)
+
+var logger = surf.NewLogger(os.Stdout, "app", "auth")
+
+// LoginRequired returns middleware that is redirecting to the login page all
+// requests without an authenticated session. Account of the session is
+// available to the wrapped handler via ContextAccount.
+func LoginRequired(a Authenticator) surf.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			account, err := a.CurrentAccount(r)
+			if err != nil {
+				loginURL, err := surf.ReverseURL(r.Context(), "login")
+				if err != nil {
+					logger.Error(r.Context(), "cannot build login URL",
+						"error", err.Error())
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				http.Redirect(w, r, loginURL, http.StatusTemporaryRedirect)
+				return
+			}
+			ctx := context.WithValue(r.Context(), accountKey, account)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ContextAccount returns account stored in the context by LoginRequired
+// middleware or nil.
+func ContextAccount(ctx context.Context) *Account {
+	account, _ := ctx.Value(accountKey).(*Account)
+	return account
+}
+
+var accountKey = struct{ name string }{"account"}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/husio/scrumboard/server/metrics"
 )
 
 // Cache represents high level cache client with custom serialization
@@ -36,3 +38,18 @@ var (
 	// which cause conflict.
 	ErrConflict = errors.New("conflict")
 )
+
+var getCounter = metrics.NewCounter("scrumboard_cache_gets_total",
+	"Number of cache reads, by result: hit, miss or error.", "cache", "result")
+
+// countGet counts the result of cache read.
+func countGet(cache string, err error) {
+	switch err {
+	case nil:
+		getCounter.Inc(cache, "hit")
+	case ErrMiss:
+		getCounter.Inc(cache, "miss")
+	default:
+		getCounter.Inc(cache, "error")
+	}
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestCacheImplementations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	implementations := map[string]func() Cache{
+		"localmem": func() Cache {
+			return NewLocalMemCache()
+		},
+		"redis": func() Cache {
+			return NewRedisCache(redistest.NewPool())
+		},
+		"file": func() Cache {
+			c, err := NewFileCache(filepath.Join(dir, "cache.json"))
+			if err != nil {
+				t.Fatalf("cannot create file cache: %s", err)
+			}
+			return c
+		},
+	}
+
+	for name, newCache := range implementations {
+		t.Run(name, func(t *testing.T) {
+			testCache(t, newCache())
+		})
+	}
+}
+
+func testCache(t *testing.T, c Cache) {
+	ctx := context.Background()
+
+	type item struct {
+		Name string
+	}
+
+	var it item
+	if err := c.Get(ctx, "a", &it); err != ErrMiss {
+		t.Fatalf("want ErrMiss, got %v", err)
+	}
+
+	if err := c.Set(ctx, "a", &item{Name: "first"}, time.Minute); err != nil {
+		t.Fatalf("cannot set: %s", err)
+	}
+	if err := c.Get(ctx, "a", &it); err != nil {
+		t.Fatalf("cannot get: %s", err)
+	}
+	if it.Name != "first" {
+		t.Fatalf("want first, got %q", it.Name)
+	}
+
+	if err := c.Add(ctx, "a", &item{Name: "second"}, time.Minute); err != ErrConflict {
+		t.Fatalf("want ErrConflict, got %v", err)
+	}
+	if err := c.Add(ctx, "b", &item{Name: "second"}, time.Minute); err != nil {
+		t.Fatalf("cannot add: %s", err)
+	}
+
+	if err := c.Del(ctx, "a"); err != nil {
+		t.Fatalf("cannot delete: %s", err)
+	}
+	if err := c.Del(ctx, "a"); err != ErrMiss {
+		t.Fatalf("want ErrMiss, got %v", err)
+	}
+	if err := c.Get(ctx, "a", &it); err != ErrMiss {
+		t.Fatalf("want ErrMiss, got %v", err)
+	}
+
+	if err := c.Set(ctx, "short", &item{Name: "short"}, 10*time.Millisecond); err != nil {
+		t.Fatalf("cannot set: %s", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := c.Get(ctx, "short", &it); err != ErrMiss {
+		t.Fatalf("want expired item to be ErrMiss, got %v", err)
+	}
+	if err := c.Add(ctx, "short", &item{Name: "again"}, time.Minute); err != nil {
+		t.Fatalf("cannot add expired key: %s", err)
+	}
+}
+
+func TestFileCachePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	path := filepath.Join(dir, "cache.json")
+
+	c, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("cannot create cache: %s", err)
+	}
+	if err := c.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("cannot set: %s", err)
+	}
+
+	c, err = NewFileCache(path)
+	if err != nil {
+		t.Fatalf("cannot open cache: %s", err)
+	}
+	var value string
+	if err := c.Get(ctx, "key", &value); err != nil {
+		t.Fatalf("cannot get: %s", err)
+	}
+	if value != "value" {
+		t.Fatalf("want value, got %q", value)
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/husio/scrumboard/server/atomicfile"
+)
+
+// FileCache is local memory cache, that writes all data to a file after every
+// change, so that it survives process restart.
+type FileCache struct {
+	// mu serialize file writes
+	mu   sync.Mutex
+	path string
+	mem  *LocalMemCache
+}
+
+var _ Cache = (*FileCache)(nil)
+
+// NewFileCache returns cache that keeps all data in a single JSON file. File is
+// created if it does not exist.
+func NewFileCache(path string) (*FileCache, error) {
+	c := &FileCache{
+		path: path,
+		mem:  NewLocalMemCache(),
+	}
+
+	switch raw, err := ioutil.ReadFile(path); {
+	case err == nil:
+		if err := json.Unmarshal(raw, &c.mem.mem); err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %s", path, err)
+		}
+	case os.IsNotExist(err):
+		// new cache
+	default:
+		return nil, fmt.Errorf("cannot read %s: %s", path, err)
+	}
+	return c, nil
+}
+
+// save writes all not expired items to the file.
+func (c *FileCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.mem.mu.Lock()
+	items := make(map[string]*cacheitem, len(c.mem.mem))
+	for key, it := range c.mem.mem {
+		if it.ValidTill.After(now) {
+			items[key] = it
+		}
+	}
+	raw, err := json.Marshal(items)
+	c.mem.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("cannot serialize: %s", err)
+	}
+	if err := atomicfile.WriteFile(c.path, raw, 0600); err != nil {
+		return fmt.Errorf("cannot write %s: %s", c.path, err)
+	}
+	return nil
+}
+
+func (c *FileCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.mem.get(key, dest)
+	countGet("file", err)
+	return err
+}
+
+func (c *FileCache) Set(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	if err := c.mem.Set(ctx, key, value, exp); err != nil {
+		return err
+	}
+	return c.save()
+}
+
+func (c *FileCache) Add(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	if err := c.mem.Add(ctx, key, value, exp); err != nil {
+		return err
+	}
+	return c.save()
+}
+
+func (c *FileCache) Del(ctx context.Context, key string) error {
+	if err := c.mem.Del(ctx, key); err != nil {
+		return err
+	}
+	return c.save()
+}
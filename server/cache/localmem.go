@@ -20,8 +20,9 @@ type cacheitem struct {
 
 var _ Cache = (*LocalMemCache)(nil)
 
-// NewLocalMemCache returns local memory cache intance. This is strictly for
-// testing and must not be used for end application.
+// NewLocalMemCache returns local memory cache intance. All data is lost when
+// the process exits and it is not shared between processes, so it should be
+// used only for testing or by single process installations.
 func NewLocalMemCache() *LocalMemCache {
 	return &LocalMemCache{
 		mem: make(map[string]*cacheitem),
@@ -29,6 +30,12 @@ func NewLocalMemCache() *LocalMemCache {
 }
 
 func (c *LocalMemCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.get(key, dest)
+	countGet("localmem", err)
+	return err
+}
+
+func (c *LocalMemCache) get(key string, dest interface{}) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -66,7 +73,7 @@ func (c *LocalMemCache) Add(ctx context.Context, key string, value interface{},
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, ok := c.mem[key]; ok {
+	if it, ok := c.mem[key]; ok && it.ValidTill.After(time.Now()) {
 		return ErrConflict
 	}
 
@@ -22,6 +22,12 @@ func NewRedisCache(pool *redis.Pool) *RedisCache {
 }
 
 func (rcs *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := rcs.get(key, dest)
+	countGet("redis", err)
+	return err
+}
+
+func (rcs *RedisCache) get(key string, dest interface{}) error {
 	rc := rcs.pool.Get()
 	defer rc.Close()
 
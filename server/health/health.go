@@ -0,0 +1,173 @@
+// Package health provides liveness, readiness and version endpoints, used by
+// orchestrators and load balancers to decide if the process should receive
+// traffic.
+package health
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/husio/scrumboard/server/surf"
+)
+
+// Check returns an error if the checked component is not able to serve
+// requests.
+type Check func(context.Context) error
+
+type HealthApp struct {
+	mux *surf.Router
+	log surf.Logger
+
+	mu     sync.Mutex
+	checks map[string]Check
+
+	draining int32
+}
+
+// NewApp returns application serving /healthz, /readyz and /version
+// endpoints.
+func NewApp() *HealthApp {
+	app := &HealthApp{
+		log:    surf.NewLogger(os.Stdout, "app", "health"),
+		checks: make(map[string]Check),
+	}
+
+	rt := surf.NewJSONRouter()
+	rt.Get(`/healthz`, app.healthz)
+	rt.Get(`/readyz`, app.readyz)
+	rt.Get(`/version`, app.version)
+	app.mux = rt
+
+	return app
+}
+
+func (app *HealthApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	app.mux.ServeHTTP(w, r)
+}
+
+// Match returns true if application is serving given path.
+func (app *HealthApp) Match(path string) bool {
+	return app.mux.Match(path)
+}
+
+// Register adds readiness check. Process is ready only if all registered
+// checks pass.
+func (app *HealthApp) Register(name string, check Check) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.checks[name] = check
+}
+
+// Drain marks the process as shutting down. Readiness check fails from now
+// on, so that no new traffic is routed to the process.
+func (app *HealthApp) Drain() {
+	atomic.StoreInt32(&app.draining, 1)
+}
+
+// healthz reports that the process is running and able to handle requests.
+func (app *HealthApp) healthz(w http.ResponseWriter, r *http.Request) {
+	surf.JSONResp(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{
+		Status: "ok",
+	})
+}
+
+// readyz runs all registered checks concurrently and reports result of each
+// of them. Errors might contain addresses of the checked services, so they are
+// only logged and never included in the response.
+func (app *HealthApp) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, done := context.WithTimeout(r.Context(), 2*time.Second)
+	defer done()
+
+	app.mu.Lock()
+	checks := make(map[string]Check, len(app.checks))
+	for name, check := range app.checks {
+		checks[name] = check
+	}
+	app.mu.Unlock()
+
+	results := make(map[string]string, len(checks))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := "ok"
+			if err := check(ctx); err != nil {
+				app.log.Error(ctx, "readiness check failed",
+					"check", name,
+					"error", err.Error())
+				result = "failed"
+			}
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&app.draining) == 1 {
+		results["shutdown"] = "draining"
+	}
+
+	resp := struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+		Failed []string          `json:"failed,omitempty"`
+	}{
+		Status: "ok",
+		Checks: results,
+	}
+	for name, result := range results {
+		if result != "ok" {
+			resp.Failed = append(resp.Failed, name)
+		}
+	}
+	code := http.StatusOK
+	if len(resp.Failed) != 0 {
+		sort.Strings(resp.Failed)
+		resp.Status = "unavailable"
+		code = http.StatusServiceUnavailable
+	}
+	surf.JSONResp(w, code, resp)
+}
+
+// version returns information about the running binary, as embedded by the
+// compiler.
+func (app *HealthApp) version(w http.ResponseWriter, r *http.Request) {
+	resp := struct {
+		Path      string            `json:"path,omitempty"`
+		Version   string            `json:"version,omitempty"`
+		GoVersion string            `json:"go_version"`
+		Settings  map[string]string `json:"settings,omitempty"`
+	}{
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		resp.Path = info.Main.Path
+		resp.Version = info.Main.Version
+		resp.Settings = make(map[string]string)
+		for _, s := range info.Settings {
+			// only version control information is useful to
+			// identify the build
+			switch s.Key {
+			case "vcs", "vcs.revision", "vcs.time", "vcs.modified":
+				resp.Settings[s.Key] = s.Value
+			}
+		}
+	}
+
+	surf.JSONResp(w, http.StatusOK, resp)
+}
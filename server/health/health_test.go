@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthz(t *testing.T) {
+	app := NewApp()
+	app.Register("failing", func(context.Context) error { return errors.New("failure") })
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("want %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	app := NewApp()
+	app.Register("ok", func(context.Context) error { return nil })
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("want %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+
+	app.Register("failing", func(context.Context) error {
+		return errors.New("dial tcp 10.0.0.5:6379: connection refused")
+	})
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body)
+	}
+	var resp struct {
+		Checks map[string]string
+		Failed []string
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("cannot decode response: %s", err)
+	}
+	// error details are not exposed
+	if resp.Checks["ok"] != "ok" || resp.Checks["failing"] != "failed" {
+		t.Fatalf("unexpected checks: %v", resp.Checks)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0] != "failing" {
+		t.Fatalf("unexpected failed checks: %v", resp.Failed)
+	}
+}
+
+func TestReadyzWhileDraining(t *testing.T) {
+	app := NewApp()
+	app.Drain()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body)
+	}
+}
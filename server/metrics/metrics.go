@@ -0,0 +1,354 @@
+// Package metrics provides counters, gauges and histograms exposed in the
+// Prometheus text format.
+//
+// Metrics are usually declared as package level variables and registered in
+// the default registry:
+//
+//	var requests = metrics.NewCounter("http_requests_total",
+//		"Number of handled HTTP requests.", "route", "code")
+//
+//	requests.Inc("/b/<board-id>", "200")
+//
+// Label values must be given in the same order as label names.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry is a set of metrics, exposed together.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	write(w io.Writer)
+}
+
+// NewRegistry returns empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// DefaultRegistry is used by all package level functions.
+var DefaultRegistry = NewRegistry()
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("metric %q already registered", name))
+	}
+	r.metrics[name] = m
+}
+
+// Write writes all registered metrics in the Prometheus text format.
+// Metrics are sorted by name.
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mu.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// ServeHTTP writes all registered metrics.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.Write(w)
+}
+
+// Handler returns handler exposing metrics from the default registry.
+func Handler() http.Handler {
+	return DefaultRegistry
+}
+
+// desc describes metric and keeps values of all its label combinations.
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labels []string
+	value  float64
+
+	// used only by histograms
+	buckets []uint64
+	count   uint64
+}
+
+func newDesc(name, help, kind string, labels []string) *desc {
+	d := &desc{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		series: make(map[string]*series),
+	}
+	if len(labels) == 0 {
+		// metric without labels is always exposed, even if never
+		// changed
+		d.get(nil)
+	}
+	return d
+}
+
+// get returns series for given label values. Must be called with lock held.
+func (d *desc) get(values []string) *series {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %q: want %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := d.series[key]
+	if !ok {
+		s = &series{labels: append([]string(nil), values...)}
+		d.series[key] = s
+	}
+	return s
+}
+
+func (d *desc) writeHeader(w io.Writer) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, help, d.name, d.kind)
+}
+
+// sorted returns all series ordered by label values. Must be called with lock
+// held.
+func (d *desc) sorted() []*series {
+	keys := make([]string, 0, len(d.series))
+	for key := range d.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	all := make([]*series, 0, len(keys))
+	for _, key := range keys {
+		all = append(all, d.series[key])
+	}
+	return all
+}
+
+func (d *desc) write(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.writeHeader(w)
+	for _, s := range d.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", d.name, formatLabels(d.labels, s.labels), formatFloat(s.value))
+	}
+}
+
+// Counter is a value that only increases.
+type Counter struct {
+	d *desc
+}
+
+// NewCounter returns counter registered in the default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return DefaultRegistry.NewCounter(name, help, labels...)
+}
+
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{d: newDesc(name, help, "counter", labels)}
+	r.register(name, c.d)
+	return c
+}
+
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *Counter) Add(v float64, labels ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metric %q: counter cannot decrease", c.d.name))
+	}
+	c.d.mu.Lock()
+	c.d.get(labels).value += v
+	c.d.mu.Unlock()
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	d *desc
+}
+
+// NewGauge returns gauge registered in the default registry.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help, labels...)
+}
+
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{d: newDesc(name, help, "gauge", labels)}
+	r.register(name, g.d)
+	return g
+}
+
+func (g *Gauge) Set(v float64, labels ...string) {
+	g.d.mu.Lock()
+	g.d.get(labels).value = v
+	g.d.mu.Unlock()
+}
+
+func (g *Gauge) Add(v float64, labels ...string) {
+	g.d.mu.Lock()
+	g.d.get(labels).value += v
+	g.d.mu.Unlock()
+}
+
+func (g *Gauge) Inc(labels ...string) {
+	g.Add(1, labels...)
+}
+
+func (g *Gauge) Dec(labels ...string) {
+	g.Add(-1, labels...)
+}
+
+// Delete removes value of given labels, so that it is no longer exposed.
+func (g *Gauge) Delete(labels ...string) {
+	g.d.mu.Lock()
+	delete(g.d.series, strings.Join(labels, "\xff"))
+	g.d.mu.Unlock()
+}
+
+// NewGaugeFunc registers in the default registry gauge, which value is
+// returned by given function every time metrics are collected.
+func NewGaugeFunc(name, help string, fn func() float64) {
+	DefaultRegistry.NewGaugeFunc(name, help, fn)
+}
+
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(name, &gaugeFunc{d: newDesc(name, help, "gauge", nil), fn: fn})
+}
+
+type gaugeFunc struct {
+	d  *desc
+	fn func() float64
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	g.d.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.d.name, formatFloat(g.fn()))
+}
+
+// Histogram counts observed values in configurable buckets.
+type Histogram struct {
+	d       *desc
+	buckets []float64
+}
+
+// DefaultBuckets are suitable for measuring request latency in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// NewHistogram returns histogram registered in the default registry. Buckets
+// are upper bounds of observed values and must be sorted.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return DefaultRegistry.NewHistogram(name, help, buckets, labels...)
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metric %q: buckets not sorted", name))
+	}
+	h := &Histogram{
+		d:       newDesc(name, help, "histogram", labels),
+		buckets: buckets,
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *Histogram) Observe(v float64, labels ...string) {
+	h.d.mu.Lock()
+	defer h.d.mu.Unlock()
+
+	s := h.d.get(labels)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.buckets))
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.d.mu.Lock()
+	defer h.d.mu.Unlock()
+
+	h.d.writeHeader(w)
+	names := append(append([]string(nil), h.d.labels...), "le")
+	for _, s := range h.d.sorted() {
+		values := append(append([]string(nil), s.labels...), "")
+		for i, upper := range h.buckets {
+			var n uint64
+			if s.buckets != nil {
+				n = s.buckets[i]
+			}
+			values[len(values)-1] = formatFloat(upper)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.d.name, formatLabels(names, values), n)
+		}
+		values[len(values)-1] = "+Inf"
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.d.name, formatLabels(names, values), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.d.name, formatLabels(h.d.labels, s.labels), formatFloat(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.d.name, formatLabels(h.d.labels, s.labels), s.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
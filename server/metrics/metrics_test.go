@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTo(t *testing.T) {
+	r := NewRegistry()
+
+	c := r.NewCounter("requests_total", "Number of requests.", "code")
+	c.Inc("200")
+	c.Add(2, "200")
+	c.Inc("404")
+
+	g := r.NewGauge("connections", "Open \"connections\".", "board")
+	g.Inc(`a"b`)
+	g.Inc("removed")
+	g.Delete("removed")
+
+	r.NewGaugeFunc("answer", "The answer.", func() float64 { return 42 })
+	r.NewCounter("errors_total", "Number of errors.")
+
+	h := r.NewHistogram("latency_seconds", "Request latency.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(3)
+
+	var b bytes.Buffer
+	r.Write(&b)
+
+	want := `# HELP answer The answer.
+# TYPE answer gauge
+answer 42
+# HELP connections Open "connections".
+# TYPE connections gauge
+connections{board="a\"b"} 1
+# HELP errors_total Number of errors.
+# TYPE errors_total counter
+errors_total 0
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{code="200"} 3
+requests_total{code="404"} 1
+`
+	if got := b.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDuplicatedMetric(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("requests_total", "Number of requests.")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicated metric registered")
+		}
+	}()
+	r.NewGauge("requests_total", "Number of requests.")
+}
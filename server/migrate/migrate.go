@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Migration is a single, named database schema change. Once applied,
+// migration must never be modified. Add a new one instead.
+type Migration struct {
+	Name string
+	SQL  string
+}
+
+// Run applies all not yet applied migrations of given component, in order.
+// Applied migrations are tracked in schema_migrations table. All migrations
+// are applied in a single transaction, so that either all of them succeed or
+// none is applied. Concurrent runs are serialized.
+func Run(ctx context.Context, db *sql.DB, component string, migrations []Migration) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			component  TEXT NOT NULL,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (component, name)
+		)
+	`); err != nil {
+		return fmt.Errorf("cannot create migrations table: %s", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot start transaction: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE schema_migrations IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("cannot lock migrations table: %s", err)
+	}
+
+	applied := make(map[string]bool)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name FROM schema_migrations WHERE component = $1
+	`, component)
+	if err != nil {
+		return fmt.Errorf("cannot list applied migrations: %s", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("cannot scan migration: %s", err)
+		}
+		applied[name] = true
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("cannot list applied migrations: %s", err)
+	}
+
+	for _, m := range migrations {
+		if applied[m.Name] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+			return fmt.Errorf("cannot apply %s migration %s: %s", component, m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (component, name) VALUES ($1, $2)
+		`, component, m.Name); err != nil {
+			return fmt.Errorf("cannot mark %s migration %s as applied: %s", component, m.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit: %s", err)
+	}
+	return nil
+}
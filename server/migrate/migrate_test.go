@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/husio/scrumboard/server/pgtest"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.Open(t)
+
+	migrations := []Migration{
+		{Name: "0001_first", SQL: `CREATE TABLE first (id INT PRIMARY KEY)`},
+	}
+	// applying the same migrations again is a no-op
+	for i := 0; i < 2; i++ {
+		if err := Run(ctx, db, "test", migrations); err != nil {
+			t.Fatalf("cannot migrate: %s", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO first (id) VALUES (1)`); err != nil {
+		t.Fatalf("table not created: %s", err)
+	}
+
+	// only new migrations are applied
+	migrations = append(migrations, Migration{
+		Name: "0002_second",
+		SQL:  `CREATE TABLE second (id INT PRIMARY KEY)`,
+	})
+	if err := Run(ctx, db, "test", migrations); err != nil {
+		t.Fatalf("cannot migrate: %s", err)
+	}
+	if _, err := db.Exec(`INSERT INTO second (id) VALUES (1)`); err != nil {
+		t.Fatalf("table not created: %s", err)
+	}
+
+	// migrations are tracked separately for every component
+	other := []Migration{
+		{Name: "0001_first", SQL: `CREATE TABLE other (id INT PRIMARY KEY)`},
+	}
+	if err := Run(ctx, db, "other", other); err != nil {
+		t.Fatalf("cannot migrate: %s", err)
+	}
+	if _, err := db.Exec(`INSERT INTO other (id) VALUES (1)`); err != nil {
+		t.Fatalf("table not created: %s", err)
+	}
+
+	var applied int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE component = 'test'`).Scan(&applied); err != nil {
+		t.Fatalf("cannot count migrations: %s", err)
+	}
+	if applied != 2 {
+		t.Fatalf("want 2 applied migrations, got %d", applied)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.Open(t)
+
+	migrations := []Migration{
+		{Name: "0001_first", SQL: `CREATE TABLE first (id INT PRIMARY KEY)`},
+		{Name: "0002_invalid", SQL: `CREATE TABLE`},
+	}
+	if err := Run(ctx, db, "test", migrations); err == nil {
+		t.Fatal("invalid migration applied")
+	}
+
+	// all migrations are rolled back together
+	if _, err := db.Exec(`SELECT 1 FROM first`); err == nil {
+		t.Fatal("migration not rolled back")
+	}
+	var applied int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatalf("cannot count migrations: %s", err)
+	}
+	if applied != 0 {
+		t.Fatalf("want no applied migrations, got %d", applied)
+	}
+}
@@ -0,0 +1,72 @@
+// Package pgtest provides PostgreSQL databases for tests. Database server is
+// configured using DATABASE_URL environment variable, and tests using it are
+// skipped when the variable is not set.
+package pgtest
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// Open returns connection to the database, using a new, empty schema that is
+// removed once the test is done. Test is skipped if DATABASE_URL is not set.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("cannot generate schema name: %s", err)
+	}
+	schema := "test_" + hex.EncodeToString(b)
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("cannot open database: %s", err)
+	}
+	defer admin.Close()
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("cannot create schema: %s", err)
+	}
+	t.Cleanup(func() {
+		admin, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Errorf("cannot open database: %s", err)
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("cannot drop schema: %s", err)
+		}
+	})
+
+	// every connection must be using the test schema, so it is set as
+	// a connection parameter
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("invalid DATABASE_URL: %s", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("cannot open database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("cannot ping database: %s", err)
+	}
+	return db
+}
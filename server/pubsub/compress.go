@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+)
+
+// Snapshots written by compressing store are prefixed with a header, that
+// describes the format of the data. First header byte is always zero, which
+// never is the first byte of a JSON encoded board state, so that snapshots
+// written before compression was introduced can be read as well.
+const (
+	formatMarker byte = 0
+	formatGzip   byte = 1
+)
+
+type compressStore struct {
+	store SnapshotStore
+}
+
+var _ SnapshotStore = (*compressStore)(nil)
+
+// Compress wraps snapshot store, so that all board states are compressed
+// before written. Both compressed and not compressed board states can be
+// loaded.
+func Compress(store SnapshotStore) SnapshotStore {
+	return &compressStore{store: store}
+}
+
+func (s *compressStore) Load(ctx context.Context, board string) ([]byte, error) {
+	data, err := s.store.Load(ctx, board)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || data[0] != formatMarker {
+		// written before compression was introduced
+		return data, nil
+	}
+	if len(data) < 2 {
+		return nil, fmt.Errorf("invalid snapshot header")
+	}
+
+	switch format := data[1]; format {
+	case formatGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data[2:]))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress: %s", err)
+		}
+		defer r.Close()
+		raw, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress: %s", err)
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("unknown snapshot format: %d", format)
+	}
+}
+
+func (s *compressStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	var b bytes.Buffer
+	b.WriteByte(formatMarker)
+	b.WriteByte(formatGzip)
+
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("cannot compress: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("cannot compress: %s", err)
+	}
+	return s.store.Store(ctx, board, b.Bytes(), version)
+}
+
+func (s *compressStore) Delete(ctx context.Context, board string) error {
+	return s.store.Delete(ctx, board)
+}
@@ -0,0 +1,116 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/husio/scrumboard/server/atomicfile"
+)
+
+type fileSnapshotStore struct {
+	// mu serialize writes, so that version check and write are atomic
+	mu  sync.Mutex
+	dir string
+}
+
+var _ SnapshotStore = (*fileSnapshotStore)(nil)
+
+// NewFileSnapshotStore returns snapshot store that keeps every board state in
+// a separate file inside of given directory. Directory is created if it does
+// not exist.
+func NewFileSnapshotStore(dir string) (SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create directory: %s", err)
+	}
+	return &fileSnapshotStore{dir: dir}, nil
+}
+
+func (s *fileSnapshotStore) path(board string) (string, error) {
+	// board ID is provided by the client and must not be trusted
+	for _, c := range board {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return "", fmt.Errorf("invalid board id: %q", board)
+		}
+	}
+	if board == "" {
+		return "", fmt.Errorf("invalid board id: %q", board)
+	}
+	return filepath.Join(s.dir, board+".snapshot"), nil
+}
+
+func (s *fileSnapshotStore) Load(ctx context.Context, board string) ([]byte, error) {
+	path, err := s.path(board)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		return data, nil
+	case os.IsNotExist(err):
+		return nil, ErrNoSnapshot
+	default:
+		return nil, fmt.Errorf("cannot read file: %s", err)
+	}
+}
+
+// Store writes board state atomically, so that interrupted write never leaves
+// a corrupted snapshot. State version is written to a separate file, after the
+// state.
+func (s *fileSnapshotStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	path, err := s.path(board)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch raw, err := ioutil.ReadFile(path + ".version"); {
+	case err == nil:
+		current, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version file: %s", err)
+		}
+		if current > version {
+			return ErrStaleSnapshot
+		}
+	case os.IsNotExist(err):
+		// written before versions were introduced
+	default:
+		return fmt.Errorf("cannot read version file: %s", err)
+	}
+
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path+".version", []byte(strconv.FormatInt(version, 10)), 0600)
+}
+
+func (s *fileSnapshotStore) Delete(ctx context.Context, board string) error {
+	path, err := s.path(board)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch err := os.Remove(path); {
+	case err == nil:
+		if err := os.Remove(path + ".version"); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove version file: %s", err)
+		}
+		return nil
+	case os.IsNotExist(err):
+		return ErrNoSnapshot
+	default:
+		return fmt.Errorf("cannot remove file: %s", err)
+	}
+}
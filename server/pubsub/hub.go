@@ -1,6 +1,17 @@
 package pubsub
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/husio/scrumboard/server/surf"
+)
+
+var logger = surf.NewLogger(os.Stdout, "app", "pubsub")
 
 type Hub interface {
 	Subscribe(board string, recv chan<- []byte) Subscription
@@ -10,6 +21,94 @@ type Subscription interface {
 	Broadcast([]byte) error
 	Send([]byte) error
 	Close() error
+
+	// Done returns channel that is closed when subscription is
+	// terminated. Hub terminates subscriptions of clients that are not able
+	// to keep up with the messages and such client must subscribe again to
+	// get the current state.
+	Done() <-chan struct{}
+}
+
+// DropCounter is implemented by hubs that count messages that were not
+// delivered to slow clients.
+type DropCounter interface {
+	Dropped() uint64
+}
+
+// Checker is implemented by hubs that depend on external services or
+// background workers. Check returns an error if the hub cannot deliver
+// messages.
+type Checker interface {
+	Check(context.Context) error
+}
+
+var (
+	ErrSlowClient = errors.New("slow client")
+	ErrClosed     = errors.New("subscription closed")
+
+	// ErrSnapshotFailed is returned when the board state cannot be
+	// persisted.
+	ErrSnapshotFailed = errors.New("snapshot failed")
+)
+
+// OverflowPolicy decides what to do with subscriber that does not consume
+// messages as fast as they are published.
+type OverflowPolicy struct {
+	mode overflowMode
+	size int
+}
+
+type overflowMode int
+
+const (
+	overflowDisconnect overflowMode = iota
+	overflowCoalesce
+	overflowQueue
+)
+
+var (
+	// DisconnectSlow terminates subscription as soon as message cannot be
+	// delivered to the subscriber.
+	DisconnectSlow = OverflowPolicy{mode: overflowDisconnect}
+
+	// CoalesceLatest keeps only the latest of not yet delivered messages.
+	// Because every message is the complete board state, subscriber never
+	// has to be disconnected.
+	CoalesceLatest = OverflowPolicy{mode: overflowCoalesce}
+)
+
+// QueueSlow buffers up to size not yet delivered messages for every
+// subscriber. Subscription is terminated when the queue is full.
+func QueueSlow(size int) OverflowPolicy {
+	return OverflowPolicy{mode: overflowQueue, size: size}
 }
 
-var ErrSlowClient = errors.New("slow client")
+func (p OverflowPolicy) String() string {
+	switch p.mode {
+	case overflowCoalesce:
+		return "coalesce"
+	case overflowQueue:
+		return "queue:" + strconv.Itoa(p.size)
+	default:
+		return "disconnect"
+	}
+}
+
+// ParseOverflowPolicy returns policy described by given name. Supported
+// values are "disconnect", "coalesce" and "queue:<size>".
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch {
+	case s == "disconnect":
+		return DisconnectSlow, nil
+	case s == "coalesce":
+		return CoalesceLatest, nil
+	case strings.HasPrefix(s, "queue:"):
+		size, err := strconv.Atoi(s[len("queue:"):])
+		if err != nil || size < 1 {
+			return DisconnectSlow, fmt.Errorf("invalid queue size: %q", s)
+		}
+		return QueueSlow(size), nil
+	default:
+		return DisconnectSlow, fmt.Errorf("unknown overflow policy: %q", s)
+	}
+}
@@ -1,83 +1,314 @@
 package pubsub
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/husio/scrumboard/server/metrics"
+)
+
+var (
+	subscribersGauge = metrics.NewGauge("scrumboard_hub_subscribers",
+		"Number of subscribers of every board with at least one subscriber, including internal snapshot watchers.", "board")
+	broadcastCounter = metrics.NewCounter("scrumboard_hub_messages_broadcast_total",
+		"Number of messages broadcasted to board subscribers.")
+	droppedCounter = metrics.NewCounter("scrumboard_hub_messages_dropped_total",
+		"Number of messages not delivered, because the subscriber was too slow.")
+)
 
 // in memory hub register, because when running on free heroku, we use single
 // process anyway
+//
+// Every board is using separate lock, so that broadcasting to a board with
+// many subscribers does not block other boards. Hub lock is held only when
+// the board is looked up, created or removed and no other lock is acquired
+// while holding it. Board lock can be held while acquiring hub lock.
 type memhub struct {
-	mu            sync.Mutex
-	subscriptions map[string]map[*memsub]struct{}
+	mu       sync.RWMutex
+	boards   map[string]*memboard
+	overflow OverflowPolicy
+	dropped  uint64
 }
 
 var _ Hub = (*memhub)(nil)
+var _ DropCounter = (*memhub)(nil)
+
+// memboard holds all subscriptions of a single board. Board lock is held
+// during the whole message delivery, so that all subscribers receive
+// messages in the same order.
+type memboard struct {
+	mu   sync.Mutex
+	subs map[*memsub]struct{}
+	// dead is set when the last subscription was removed and the board
+	// is no longer registered in the hub
+	dead bool
+}
+
+// NewMemoryHub returns hub that is able to pass messages only between
+// subscribers of the same process. Overflow policy decides what happens with
+// subscribers that are not consuming messages fast enough.
+func NewMemoryHub(overflow OverflowPolicy) Hub {
+	return newMemhub(overflow)
+}
 
-func NewMemoryHub() Hub {
+func newMemhub(overflow OverflowPolicy) *memhub {
 	return &memhub{
-		subscriptions: make(map[string]map[*memsub]struct{}),
+		boards:   make(map[string]*memboard),
+		overflow: overflow,
 	}
 }
 
 func (h *memhub) Subscribe(board string, recv chan<- []byte) Subscription {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	return h.subscribe(board, recv)
+}
 
+func (h *memhub) subscribe(board string, recv chan<- []byte) *memsub {
 	sub := &memsub{
 		hub:   h,
 		board: board,
 		recv:  recv,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
 	}
-	if _, ok := h.subscriptions[board]; !ok {
-		h.subscriptions[board] = make(map[*memsub]struct{})
+
+	for {
+		h.mu.Lock()
+		b, ok := h.boards[board]
+		if !ok {
+			b = &memboard{subs: make(map[*memsub]struct{})}
+			h.boards[board] = b
+		}
+		h.mu.Unlock()
+
+		b.mu.Lock()
+		if b.dead {
+			// last subscriber left in the meantime and the board
+			// was removed, so a new one must be created
+			b.mu.Unlock()
+			continue
+		}
+		sub.shard = b
+		b.subs[sub] = struct{}{}
+		subscribersGauge.Inc(board)
+		b.mu.Unlock()
+		break
+	}
+
+	if h.overflow.mode != overflowDisconnect {
+		go sub.pump()
 	}
-	h.subscriptions[board][sub] = struct{}{}
 	return sub
 }
 
+// Dropped returns the number of messages that were not delivered, because
+// the receiver was too slow.
+func (h *memhub) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// drop counts message that was not delivered.
+func (h *memhub) drop() {
+	atomic.AddUint64(&h.dropped, 1)
+	droppedCounter.Inc()
+}
+
+// unsubscribe removes subscription from the hub. Must not be called with
+// board lock acquired.
+func (h *memhub) unsubscribe(s *memsub) {
+	b := s.shard
+	b.mu.Lock()
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		// if this is the last subscription, delete the whole board
+		if len(b.subs) == 0 {
+			b.dead = true
+			subscribersGauge.Delete(s.board)
+
+			h.mu.Lock()
+			if h.boards[s.board] == b {
+				delete(h.boards, s.board)
+			}
+			h.mu.Unlock()
+		} else {
+			subscribersGauge.Dec(s.board)
+		}
+	}
+	b.mu.Unlock()
+
+	s.once.Do(func() { close(s.done) })
+}
+
+// publish delivers message to all subscribers of given board, except the
+// given one.
+func (h *memhub) publish(board string, data []byte, except *memsub) {
+	h.mu.RLock()
+	b, ok := h.boards[board]
+	h.mu.RUnlock()
+	if !ok {
+		return
+	}
+	broadcastCounter.Inc()
+
+	var slow []*memsub
+
+	b.mu.Lock()
+	for sub := range b.subs {
+		if sub == except {
+			continue
+		}
+		if !sub.deliver(data) {
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.Unlock()
+
+	// clients that cannot keep up must be disconnected, because otherwise
+	// their state would silently diverge
+	for _, sub := range slow {
+		h.unsubscribe(sub)
+	}
+}
+
+// disconnectAll terminates all subscriptions.
+func (h *memhub) disconnectAll() {
+	h.mu.RLock()
+	boards := make([]*memboard, 0, len(h.boards))
+	for _, b := range h.boards {
+		boards = append(boards, b)
+	}
+	h.mu.RUnlock()
+
+	var subs []*memsub
+	for _, b := range boards {
+		b.mu.Lock()
+		for sub := range b.subs {
+			subs = append(subs, sub)
+		}
+		b.mu.Unlock()
+	}
+
+	for _, sub := range subs {
+		h.unsubscribe(sub)
+	}
+}
+
 type memsub struct {
 	hub   *memhub
 	board string
+	shard *memboard
 	recv  chan<- []byte
+
+	// queue holds messages waiting for the delivery, if overflow policy
+	// requires buffering
+	mu    sync.Mutex
+	queue [][]byte
+	wake  chan struct{}
+
+	once sync.Once
+	done chan struct{}
 }
 
 var _ Subscription = (*memsub)(nil)
 
 func (s *memsub) Broadcast(data []byte) error {
-	s.hub.mu.Lock()
-	defer s.hub.mu.Unlock()
+	// do not broadcast message to myself
+	s.hub.publish(s.board, data, s)
+	return nil
+}
 
-	for sub := range s.hub.subscriptions[s.board] {
-		// do not broadcast message to myself
-		if sub == s {
-			continue
+func (s *memsub) Send(data []byte) error {
+	select {
+	case <-s.done:
+		return ErrClosed
+	default:
+	}
+
+	s.shard.mu.Lock()
+	ok := s.deliver(data)
+	s.shard.mu.Unlock()
+
+	if !ok {
+		s.hub.unsubscribe(s)
+		return ErrSlowClient
+	}
+	return nil
+}
+
+// deliver passes message to the subscriber, using hub's overflow policy. It
+// returns false if the message cannot be delivered and subscriber must be
+// disconnected.
+func (s *memsub) deliver(data []byte) bool {
+	switch s.hub.overflow.mode {
+	case overflowCoalesce:
+		s.mu.Lock()
+		if len(s.queue) != 0 {
+			// previous message was not consumed yet and it is
+			// replaced by the latest one
+			s.hub.drop()
+		}
+		s.queue = append(s.queue[:0], data)
+		s.mu.Unlock()
+	case overflowQueue:
+		s.mu.Lock()
+		if len(s.queue) >= s.hub.overflow.size {
+			s.mu.Unlock()
+			s.hub.drop()
+			return false
 		}
+		s.queue = append(s.queue, data)
+		s.mu.Unlock()
+	default:
 		select {
-		case sub.recv <- data:
+		case s.recv <- data:
+			return true
 		default:
-			// ignore slow clients
+			s.hub.drop()
+			return false
 		}
 	}
 
-	return nil
-}
-
-func (s *memsub) Send(data []byte) error {
 	select {
-	case s.recv <- data:
-		return nil
+	case s.wake <- struct{}{}:
 	default:
-		return ErrSlowClient
+		// pump is already notified
 	}
+	return true
 }
 
-func (s *memsub) Close() error {
-	s.hub.mu.Lock()
-	defer s.hub.mu.Unlock()
+// pump moves queued messages to the receiver until subscription is closed.
+func (s *memsub) pump() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		}
 
-	delete(s.hub.subscriptions[s.board], s)
-	// if this is the last subscription, delete the whole channel
-	if len(s.hub.subscriptions[s.board]) == 0 {
-		delete(s.hub.subscriptions, s.board)
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			data := s.queue[0]
+			s.queue[0] = nil
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			select {
+			case s.recv <- data:
+			case <-s.done:
+				return
+			}
+		}
 	}
+}
+
+func (s *memsub) Done() <-chan struct{} {
+	return s.done
+}
 
+func (s *memsub) Close() error {
+	s.hub.unsubscribe(s)
 	return nil
 }
@@ -0,0 +1,366 @@
+package pubsub
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemhubBroadcast(t *testing.T) {
+	hub := NewMemoryHub(DisconnectSlow)
+
+	ra, rb, rc := make(chan []byte, 4), make(chan []byte, 4), make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+	b := hub.Subscribe("board", rb)
+	defer b.Close()
+	c := hub.Subscribe("other", rc)
+	defer c.Close()
+
+	if err := a.Broadcast([]byte("hello")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	if msg := receive(t, rb); msg != "hello" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	// message is not echoed back and not delivered to other boards
+	assertNoMessage(t, ra)
+	assertNoMessage(t, rc)
+
+	if err := a.Send([]byte("private")); err != nil {
+		t.Fatalf("cannot send: %s", err)
+	}
+	if msg := receive(t, ra); msg != "private" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	assertNoMessage(t, rb)
+}
+
+func TestMemhubCloseStopsDelivery(t *testing.T) {
+	hub := NewMemoryHub(DisconnectSlow)
+
+	ra, rb := make(chan []byte, 4), make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+	b := hub.Subscribe("board", rb)
+	if err := b.Close(); err != nil {
+		t.Fatalf("cannot close: %s", err)
+	}
+
+	select {
+	case <-b.Done():
+	default:
+		t.Fatal("closed subscription is not done")
+	}
+	a.Broadcast([]byte("hello"))
+	assertNoMessage(t, rb)
+
+	if err := b.Send([]byte("hello")); err != ErrClosed {
+		t.Fatalf("want ErrClosed, got %v", err)
+	}
+}
+
+func TestMemhubDisconnectSlow(t *testing.T) {
+	hub := NewMemoryHub(DisconnectSlow)
+
+	pub := hub.Subscribe("board", make(chan []byte, 4))
+	defer pub.Close()
+	slow := hub.Subscribe("board", make(chan []byte, 2))
+	defer slow.Close()
+
+	for i := 0; i < 3; i++ {
+		pub.Broadcast([]byte(strconv.Itoa(i)))
+	}
+
+	select {
+	case <-slow.Done():
+	case <-time.After(time.Second):
+		t.Fatal("slow subscriber not disconnected")
+	}
+	if n := hub.(DropCounter).Dropped(); n != 1 {
+		t.Fatalf("want 1 dropped message, got %d", n)
+	}
+}
+
+func TestMemhubCoalesceLatest(t *testing.T) {
+	hub := NewMemoryHub(CoalesceLatest)
+
+	pub := hub.Subscribe("board", make(chan []byte, 4))
+	defer pub.Close()
+	recv := make(chan []byte)
+	slow := hub.Subscribe("board", recv)
+	defer slow.Close()
+
+	for i := 0; i < 10; i++ {
+		pub.Broadcast([]byte(strconv.Itoa(i)))
+	}
+
+	// first message might be already waiting for the receiver, but the
+	// latest one must always be delivered
+	var last string
+	for last != "9" {
+		last = receive(t, recv)
+	}
+	select {
+	case <-slow.Done():
+		t.Fatal("subscriber disconnected")
+	default:
+	}
+	if n := hub.(DropCounter).Dropped(); n < 8 {
+		t.Fatalf("want at least 8 dropped messages, got %d", n)
+	}
+}
+
+func TestMemhubQueueSlow(t *testing.T) {
+	hub := NewMemoryHub(QueueSlow(5))
+
+	pub := hub.Subscribe("board", make(chan []byte, 4))
+	defer pub.Close()
+	recv := make(chan []byte)
+	slow := hub.Subscribe("board", recv)
+	defer slow.Close()
+
+	for i := 0; i < 5; i++ {
+		pub.Broadcast([]byte(strconv.Itoa(i)))
+	}
+	for i := 0; i < 5; i++ {
+		if msg := receive(t, recv); msg != strconv.Itoa(i) {
+			t.Fatalf("want %d, got %q", i, msg)
+		}
+	}
+
+	// unbuffered receiver is not consuming, so queue overflows
+	for i := 0; i < 7; i++ {
+		pub.Broadcast([]byte(strconv.Itoa(i)))
+	}
+	select {
+	case <-slow.Done():
+	case <-time.After(time.Second):
+		t.Fatal("slow subscriber not disconnected")
+	}
+}
+
+func TestMemhubBusyBoardDoesNotBlockOthers(t *testing.T) {
+	hub := newMemhub(CoalesceLatest)
+	busy := hub.subscribe("busy", make(chan []byte, 4))
+	defer busy.Close()
+
+	// holding the board lock is what a long broadcast does
+	busy.shard.mu.Lock()
+	blocked := make(chan Subscription, 2)
+	go func() { blocked <- hub.Subscribe("busy", make(chan []byte, 4)) }()
+	go func() {
+		busy.Close()
+		blocked <- nil
+	}()
+	// give both goroutines time to wait for the board lock
+	time.Sleep(50 * time.Millisecond)
+
+	quiet := make(chan struct{})
+	go func() {
+		hub.Subscribe("quiet", make(chan []byte, 4)).Close()
+		close(quiet)
+	}()
+	select {
+	case <-quiet:
+	case <-time.After(2 * time.Second):
+		t.Error("quiet board blocked by the busy one")
+	}
+
+	busy.shard.mu.Unlock()
+	<-quiet
+	for i := 0; i < 2; i++ {
+		if sub := <-blocked; sub != nil {
+			defer sub.Close()
+		}
+	}
+}
+
+func TestMemhubResubscribeRemovedBoard(t *testing.T) {
+	hub := NewMemoryHub(DisconnectSlow)
+
+	// board is removed together with the last subscription and created
+	// again by the next subscriber
+	for i := 0; i < 3; i++ {
+		hub.Subscribe("board", make(chan []byte, 4)).Close()
+	}
+
+	ra, rb := make(chan []byte, 4), make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+	b := hub.Subscribe("board", rb)
+	defer b.Close()
+	if err := a.Broadcast([]byte("hello")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	select {
+	case msg := <-rb:
+		if string(msg) != "hello" {
+			t.Fatalf("unexpected message: %s", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	cases := map[string]OverflowPolicy{
+		"disconnect": DisconnectSlow,
+		"coalesce":   CoalesceLatest,
+		"queue:64":   QueueSlow(64),
+	}
+	for raw, want := range cases {
+		got, err := ParseOverflowPolicy(raw)
+		if err != nil {
+			t.Errorf("%s: %s", raw, err)
+		} else if got != want {
+			t.Errorf("%s: want %s, got %s", raw, want, got)
+		}
+	}
+
+	for _, raw := range []string{"", "queue", "queue:0", "queue:x", "drop"} {
+		if _, err := ParseOverflowPolicy(raw); err == nil {
+			t.Errorf("%q: want error", raw)
+		}
+	}
+}
+
+func receive(t *testing.T, recv <-chan []byte) string {
+	t.Helper()
+	select {
+	case msg := <-recv:
+		return string(msg)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+		return ""
+	}
+}
+
+func assertNoMessage(t *testing.T, recv <-chan []byte) {
+	t.Helper()
+	select {
+	case msg := <-recv:
+		t.Fatalf("unexpected message: %q", msg)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func BenchmarkMemhubBroadcast(b *testing.B) {
+	for _, subscribers := range []int{10, 1000, 5000} {
+		b.Run(strconv.Itoa(subscribers), func(b *testing.B) {
+			hub := NewMemoryHub(CoalesceLatest)
+			stop := subscribe(hub, "board", subscribers)
+			defer stop()
+
+			pub := hub.Subscribe("board", make(chan []byte, 4))
+			defer pub.Close()
+
+			msg := []byte(`{"rows": 3, "cards": []}`)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pub.Broadcast(msg)
+			}
+		})
+	}
+}
+
+// BenchmarkMemhubSubscribeDuringBroadcast measures how much subscribing to
+// quiet boards is slowed down by constant broadcasting to a busy board. With
+// busy subscriber, other clients are at the same time subscribing to the busy
+// board and waiting for its broadcast to finish.
+func BenchmarkMemhubSubscribeDuringBroadcast(b *testing.B) {
+	for _, busySubscriber := range []bool{false, true} {
+		name := "quiet"
+		if busySubscriber {
+			name = "busy-subscriber"
+		}
+		b.Run(name, func(b *testing.B) {
+			hub := NewMemoryHub(CoalesceLatest)
+			stop := subscribe(hub, "busy", 5000)
+			defer stop()
+
+			pub := hub.Subscribe("busy", make(chan []byte, 4))
+			defer pub.Close()
+
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				msg := []byte(`{"rows": 3, "cards": []}`)
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						pub.Broadcast(msg)
+					}
+				}
+			}()
+			if busySubscriber {
+				for i := 0; i < 4; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						recv := make(chan []byte, 4)
+						for {
+							select {
+							case <-done:
+								return
+							default:
+								hub.Subscribe("busy", recv).Close()
+							}
+						}
+					}()
+				}
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				recv := make(chan []byte, 4)
+				for i := 0; pb.Next(); i++ {
+					sub := hub.Subscribe(fmt.Sprintf("quiet-%d", i%100), recv)
+					sub.Close()
+				}
+			})
+			b.StopTimer()
+
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// subscribe creates given number of board subscribers, consuming all
+// received messages. Returned function closes all subscriptions.
+func subscribe(hub Hub, board string, subscribers int) func() {
+	var wg sync.WaitGroup
+	subs := make([]Subscription, 0, subscribers)
+	for i := 0; i < subscribers; i++ {
+		recv := make(chan []byte, 4)
+		sub := hub.Subscribe(board, recv)
+		subs = append(subs, sub)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-recv:
+				case <-sub.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return func() {
+		for _, sub := range subs {
+			sub.Close()
+		}
+		wg.Wait()
+	}
+}
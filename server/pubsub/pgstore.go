@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/husio/scrumboard/server/migrate"
+)
+
+// PostgresMigrations creates schema required by PostgreSQL snapshot store.
+var PostgresMigrations = []migrate.Migration{
+	{
+		Name: "0001_snapshots",
+		SQL: `
+			CREATE TABLE snapshots (
+				board_id   TEXT PRIMARY KEY,
+				data       BYTEA NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+			);
+		`,
+	},
+	{
+		Name: "0002_snapshots_version",
+		SQL: `
+			ALTER TABLE snapshots ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+		`,
+	},
+}
+
+type pgSnapshotStore struct {
+	db *sql.DB
+}
+
+var _ SnapshotStore = (*pgSnapshotStore)(nil)
+
+// NewPostgresSnapshotStore returns snapshot store using PostgreSQL database.
+// Database schema must be created using PostgresMigrations.
+func NewPostgresSnapshotStore(db *sql.DB) SnapshotStore {
+	return &pgSnapshotStore{db: db}
+}
+
+func (s *pgSnapshotStore) Load(ctx context.Context, board string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT data FROM snapshots WHERE board_id = $1
+	`, board).Scan(&data)
+	switch err {
+	case nil:
+		return data, nil
+	case sql.ErrNoRows:
+		return nil, ErrNoSnapshot
+	default:
+		return nil, fmt.Errorf("cannot get from db: %s", err)
+	}
+}
+
+func (s *pgSnapshotStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO snapshots (board_id, data, version, updated_at) VALUES ($1, $2, $3, now())
+		ON CONFLICT (board_id) DO UPDATE
+			SET data = EXCLUDED.data, version = EXCLUDED.version, updated_at = now()
+			WHERE snapshots.version <= EXCLUDED.version
+	`, board, data, version)
+	if err != nil {
+		return fmt.Errorf("cannot store in db: %s", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("cannot store in db: %s", err)
+	} else if n == 0 {
+		return ErrStaleSnapshot
+	}
+	return nil
+}
+
+func (s *pgSnapshotStore) Delete(ctx context.Context, board string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM snapshots WHERE board_id = $1`, board)
+	if err != nil {
+		return fmt.Errorf("cannot delete from db: %s", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("cannot delete from db: %s", err)
+	} else if n == 0 {
+		return ErrNoSnapshot
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// redisHub is using redis PUBLISH/SUBSCRIBE to pass messages between
+// subscribers connected to different processes. Every process is keeping a
+// single redis connection, subscribed to all boards that have at least one
+// local subscriber. Messages received from redis are delivered to local
+// subscribers using memory hub.
+type redisHub struct {
+	rp    *redis.Pool
+	local *memhub
+	node  string
+
+	mu     sync.Mutex
+	psc    *redis.PubSubConn
+	boards map[string]int
+	subs   map[uint64]*memsub
+	lastID uint64
+}
+
+var _ Hub = (*redisHub)(nil)
+var _ DropCounter = (*redisHub)(nil)
+var _ Checker = (*redisHub)(nil)
+
+// NewRedisHub returns hub that is using redis to broadcast messages, so that
+// they are delivered to subscribers of all processes using the same redis
+// database. Overflow policy is applied to local subscribers.
+func NewRedisHub(rp *redis.Pool, overflow OverflowPolicy) Hub {
+	h := &redisHub{
+		rp:     rp,
+		local:  newMemhub(overflow),
+		node:   genNodeID(),
+		boards: make(map[string]int),
+		subs:   make(map[uint64]*memsub),
+	}
+	go h.run()
+	return h
+}
+
+func genNodeID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func boardChannel(board string) string {
+	return "board:pubsub:" + board
+}
+
+func (h *redisHub) Subscribe(board string, recv chan<- []byte) Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	sub := &redisSub{
+		hub: h,
+		id:  h.lastID,
+		sub: h.local.subscribe(board, recv),
+	}
+	h.subs[sub.id] = sub.sub
+
+	h.boards[board]++
+	if h.boards[board] == 1 && h.psc != nil {
+		// if failed, connection is broken and all boards are
+		// subscribed again once the connection is restored
+		h.psc.Subscribe(boardChannel(board))
+	}
+	return sub
+}
+
+func (h *redisHub) unsubscribe(s *redisSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[s.id]; !ok {
+		return
+	}
+	delete(h.subs, s.id)
+
+	board := s.sub.board
+	h.boards[board]--
+	if h.boards[board] == 0 {
+		delete(h.boards, board)
+		if h.psc != nil {
+			h.psc.Unsubscribe(boardChannel(board))
+		}
+	}
+}
+
+func (h *redisHub) Dropped() uint64 {
+	return h.local.Dropped()
+}
+
+// Check returns an error if the hub is not connected to redis and messages
+// from other processes are not received.
+func (h *redisHub) Check(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.psc == nil {
+		return errors.New("not connected to redis")
+	}
+	return nil
+}
+
+// run receives messages from redis until the process exits. Broken connection
+// is restored, but because messages might be lost in the meantime, all local
+// subscriptions are terminated.
+func (h *redisHub) run() {
+	for {
+		if err := h.receive(); err != nil {
+			logger.Error(context.Background(), "redis hub connection failed",
+				"error", err.Error())
+		}
+		h.local.disconnectAll()
+		time.Sleep(time.Second)
+	}
+}
+
+func (h *redisHub) receive() error {
+	psc := &redis.PubSubConn{Conn: h.rp.Get()}
+	defer psc.Close()
+
+	h.mu.Lock()
+	channels := make([]interface{}, 0, len(h.boards)+1)
+	// subscribe to private channel, because redis does not allow to
+	// subscribe to an empty channels list
+	channels = append(channels, "hub:"+h.node)
+	for board := range h.boards {
+		channels = append(channels, boardChannel(board))
+	}
+	err := psc.Subscribe(channels...)
+	if err == nil {
+		h.psc = psc
+	}
+	h.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("cannot subscribe: %s", err)
+	}
+
+	defer func() {
+		h.mu.Lock()
+		h.psc = nil
+		h.mu.Unlock()
+	}()
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			h.deliver(v.Channel, v.Data)
+		case error:
+			return v
+		}
+	}
+}
+
+// deliver passes message received from redis to all local subscribers of the
+// board, except the author of the message.
+func (h *redisHub) deliver(channel string, raw []byte) {
+	board := channel[len(boardChannel("")):]
+	node, subID, data, err := decodeMessage(raw)
+	if err != nil {
+		logger.Error(context.Background(), "cannot decode message",
+			"channel", channel,
+			"error", err.Error())
+		return
+	}
+
+	var except *memsub
+	if node == h.node {
+		h.mu.Lock()
+		except = h.subs[subID]
+		h.mu.Unlock()
+	}
+	h.local.publish(board, data, except)
+}
+
+// encodeMessage returns message data prefixed with the author information,
+// using format "<node> <subscription>\n<data>"
+func encodeMessage(node string, subID uint64, data []byte) []byte {
+	b := make([]byte, 0, len(node)+len(data)+22)
+	b = append(b, node...)
+	b = append(b, ' ')
+	b = strconv.AppendUint(b, subID, 10)
+	b = append(b, '\n')
+	return append(b, data...)
+}
+
+func decodeMessage(raw []byte) (string, uint64, []byte, error) {
+	n := bytes.IndexByte(raw, '\n')
+	if n == -1 {
+		return "", 0, nil, fmt.Errorf("missing header")
+	}
+	header := bytes.SplitN(raw[:n], []byte(" "), 2)
+	if len(header) != 2 {
+		return "", 0, nil, fmt.Errorf("invalid header")
+	}
+	subID, err := strconv.ParseUint(string(header[1]), 10, 64)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("invalid subscription id: %s", err)
+	}
+	return string(header[0]), subID, raw[n+1:], nil
+}
+
+type redisSub struct {
+	hub *redisHub
+	id  uint64
+	sub *memsub
+}
+
+var _ Subscription = (*redisSub)(nil)
+
+func (s *redisSub) Broadcast(data []byte) error {
+	rc := s.hub.rp.Get()
+	defer rc.Close()
+
+	msg := encodeMessage(s.hub.node, s.id, data)
+	if _, err := rc.Do("PUBLISH", boardChannel(s.sub.board), msg); err != nil {
+		return fmt.Errorf("cannot publish: %s", err)
+	}
+	return nil
+}
+
+func (s *redisSub) Send(data []byte) error {
+	return s.sub.Send(data)
+}
+
+func (s *redisSub) Done() <-chan struct{} {
+	return s.sub.Done()
+}
+
+func (s *redisSub) Close() error {
+	s.hub.unsubscribe(s)
+	return s.sub.Close()
+}
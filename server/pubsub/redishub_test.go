@@ -0,0 +1,126 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestRedisHubBroadcast(t *testing.T) {
+	db := redistest.NewDB()
+	first := NewRedisHub(db.Pool(), DisconnectSlow)
+	second := NewRedisHub(db.Pool(), DisconnectSlow)
+	waitConnected(t, first)
+	waitConnected(t, second)
+
+	ra, rb, rc := make(chan []byte, 4), make(chan []byte, 4), make(chan []byte, 4)
+	a := first.Subscribe("board", ra)
+	defer a.Close()
+	b := second.Subscribe("board", rb)
+	defer b.Close()
+	c := first.Subscribe("board", rc)
+	defer c.Close()
+
+	if err := a.Broadcast([]byte("hello")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	// message is delivered to subscribers of both processes, but not
+	// echoed back to the author
+	if msg := receive(t, rb); msg != "hello" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	if msg := receive(t, rc); msg != "hello" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	assertNoMessage(t, ra)
+
+	if err := b.Broadcast([]byte("reply")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	if msg := receive(t, ra); msg != "reply" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	if msg := receive(t, rc); msg != "reply" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+	assertNoMessage(t, rb)
+}
+
+func TestRedisHubUnsubscribe(t *testing.T) {
+	db := redistest.NewDB()
+	first := NewRedisHub(db.Pool(), DisconnectSlow)
+	second := NewRedisHub(db.Pool(), DisconnectSlow)
+	waitConnected(t, first)
+	waitConnected(t, second)
+
+	ra, rb := make(chan []byte, 4), make(chan []byte, 4)
+	a := first.Subscribe("board", ra)
+	defer a.Close()
+	b := second.Subscribe("board", rb)
+	if err := b.Close(); err != nil {
+		t.Fatalf("cannot close: %s", err)
+	}
+
+	if err := a.Broadcast([]byte("hello")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	assertNoMessage(t, rb)
+
+	// board channel is no longer subscribed by the second process
+	rc := db.Conn()
+	defer rc.Close()
+	if n, err := rc.Do("PUBLISH", boardChannel("board"), encodeMessage("other", 1, []byte("x"))); err != nil || n.(int64) != 1 {
+		t.Fatalf("want one receiver, got %v, %v", n, err)
+	}
+}
+
+func TestRedisHubReconnect(t *testing.T) {
+	db := redistest.NewDB()
+	hub := NewRedisHub(db.Pool(), DisconnectSlow)
+	waitConnected(t, hub)
+
+	ra := make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+
+	db.DisconnectSubscribers()
+
+	// messages might be lost while the connection was broken, so all
+	// local subscriptions are terminated
+	select {
+	case <-a.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription not terminated")
+	}
+
+	waitConnected(t, hub)
+
+	rb, rc := make(chan []byte, 4), make(chan []byte, 4)
+	b := hub.Subscribe("board", rb)
+	defer b.Close()
+	c := hub.Subscribe("board", rc)
+	defer c.Close()
+	if err := b.Broadcast([]byte("hello")); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	if msg := receive(t, rc); msg != "hello" {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+}
+
+func waitConnected(t *testing.T, hub Hub) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		err := hub.(Checker).Check(context.Background())
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hub not connected: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
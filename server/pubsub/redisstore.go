@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+type redisSnapshotStore struct {
+	rp     *redis.Pool
+	prefix string
+}
+
+var _ SnapshotStore = (*redisSnapshotStore)(nil)
+
+// NewRedisSnapshotStore returns snapshot store that keeps every board state
+// under separate key, build by prefixing board ID with given prefix. State
+// version is kept under the same key with ":version" suffix.
+func NewRedisSnapshotStore(rp *redis.Pool, keyPrefix string) SnapshotStore {
+	return &redisSnapshotStore{
+		rp:     rp,
+		prefix: keyPrefix,
+	}
+}
+
+func (s *redisSnapshotStore) Load(ctx context.Context, board string) ([]byte, error) {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	switch data, err := redis.Bytes(rc.Do("GET", s.prefix+board)); err {
+	case nil:
+		return data, nil
+	case redis.ErrNil:
+		return nil, ErrNoSnapshot
+	default:
+		return nil, fmt.Errorf("cannot get from db: %s", err)
+	}
+}
+
+func (s *redisSnapshotStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	key := s.prefix + board
+	for {
+		// transaction fails if the version was changed after it
+		// was checked, in which case the check must be repeated
+		if _, err := rc.Do("WATCH", key+":version"); err != nil {
+			return fmt.Errorf("cannot watch: %s", err)
+		}
+		current, err := redis.Int64(rc.Do("GET", key+":version"))
+		if err != nil && err != redis.ErrNil {
+			return fmt.Errorf("cannot get version: %s", err)
+		}
+		if current > version {
+			rc.Do("UNWATCH")
+			return ErrStaleSnapshot
+		}
+
+		rc.Send("MULTI")
+		rc.Send("SET", key, data)
+		rc.Send("SET", key+":version", version)
+		switch _, err := redis.Values(rc.Do("EXEC")); err {
+		case nil:
+			return nil
+		case redis.ErrNil:
+			// version changed
+		default:
+			return fmt.Errorf("cannot store in db: %s", err)
+		}
+	}
+}
+
+func (s *redisSnapshotStore) Delete(ctx context.Context, board string) error {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	n, err := redis.Int(rc.Do("DEL", s.prefix+board, s.prefix+board+":version"))
+	if err != nil {
+		return fmt.Errorf("cannot delete from db: %s", err)
+	}
+	if n == 0 {
+		return ErrNoSnapshot
+	}
+	return nil
+}
@@ -1,53 +1,384 @@
 package pubsub
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"sync"
+	"time"
 
-	"github.com/garyburd/redigo/redis"
+	"github.com/husio/scrumboard/server/metrics"
 )
 
+var (
+	snapshotWriteDuration = metrics.NewHistogram("scrumboard_snapshot_write_duration_seconds",
+		"Time spent writing board state to the store.", metrics.DefaultBuckets)
+	snapshotWriteErrors = metrics.NewCounter("scrumboard_snapshot_write_errors_total",
+		"Number of failed board state writes.")
+)
+
+// SnapshotHub is a hub that keeps the latest state of every board.
+type SnapshotHub interface {
+	Hub
+	Checker
+
+	// Flush writes all pending board states to the store.
+	Flush(context.Context) error
+
+	// Close writes all pending board states to the store and stops the
+	// background writer.
+	Close(context.Context) error
+}
+
+// hubSnapshot is write-behind cache of the latest board states. Broadcasted
+// messages are not written immediately, but kept in memory until flushed.
+// Flush happens periodically, when the last board subscriber leaves and when
+// the hub is closed. Rapid updates are coalesced, so that only the latest
+// state is written.
+//
+// Wrapped hub might be shared by many processes, so every board with local
+// subscribers is watched for messages broadcasted by other processes as well.
+// Every state is versioned, so that a process flushing older state never
+// overwrites newer state written by another process.
 type hubSnapshot struct {
-	hub Hub
-	rp  *redis.Pool
+	hub   Hub
+	store SnapshotStore
+
+	mu     sync.Mutex
+	boards map[string]*pendingSnapshot
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+type pendingSnapshot struct {
+	// flushing serialize writes of the same board state, so that older
+	// state never overwrites newer one
+	flushing sync.Mutex
+
+	data        []byte
+	dirty       bool
+	version     int64
+	subscribers int
+	// err is the result of the last failed write
+	err error
+	// unwatch stops watching the board, it is nil if the board has no
+	// subscribers
+	unwatch chan struct{}
+}
+
+var _ SnapshotHub = (*hubSnapshot)(nil)
+var _ DropCounter = (*hubSnapshot)(nil)
+var _ Checker = (*hubSnapshot)(nil)
+
+// Snapshot wraps hub, so that every broadcasted message is stored as the
+// latest board state and sent to every new subscriber. Pending states are
+// written to the store with given interval.
+func Snapshot(store SnapshotStore, hub Hub, interval time.Duration) SnapshotHub {
+	s := &hubSnapshot{
+		hub:     hub,
+		store:   store,
+		boards:  make(map[string]*pendingSnapshot),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run(interval)
+	return s
 }
 
-var _ Hub = (*hubSnapshot)(nil)
+func (s *hubSnapshot) run(interval time.Duration) {
+	defer close(s.stopped)
 
-func Snapshot(rp *redis.Pool, hub Hub) Hub {
-	return &hubSnapshot{
-		hub: hub,
-		rp:  rp,
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			ctx, done := context.WithTimeout(context.Background(), interval)
+			if err := s.Flush(ctx); err != nil {
+				logger.Error(ctx, "cannot flush snapshots",
+					"error", err.Error())
+			}
+			done()
+		}
 	}
 }
 
+func (s *hubSnapshot) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	boards := make([]string, 0, len(s.boards))
+	for board := range s.boards {
+		boards = append(boards, board)
+	}
+	s.mu.Unlock()
+
+	var failed int
+	for _, board := range boards {
+		if err := s.flush(ctx, board); err != nil {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d snapshots not stored", failed, len(boards))
+	}
+	return nil
+}
+
+// flush writes pending state of a single board. Board state is forgotten if
+// it was stored and there are no subscribers left.
+func (s *hubSnapshot) flush(ctx context.Context, board string) error {
+	s.mu.Lock()
+	p, ok := s.boards[board]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	p.flushing.Lock()
+	defer p.flushing.Unlock()
+
+	s.mu.Lock()
+	data, dirty, version := p.data, p.dirty, p.version
+	s.mu.Unlock()
+
+	var err, stale error
+	if dirty {
+		start := time.Now()
+		err = s.store.Store(ctx, board, data, version)
+		snapshotWriteDuration.Observe(time.Since(start).Seconds())
+		if err == ErrStaleSnapshot {
+			// other process has already written newer state
+			err, stale = nil, err
+		}
+		if err != nil {
+			snapshotWriteErrors.Inc()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dirty {
+		p.err = err
+		if err == nil && p.version == version {
+			p.dirty = false
+			if stale != nil {
+				// newer state must be loaded from the store
+				p.data = nil
+			}
+		}
+	}
+	// board might have been flushed and subscribed again while
+	// writing, so only the written state can be forgotten
+	if s.boards[board] == p && !p.dirty && p.subscribers == 0 {
+		delete(s.boards, board)
+	}
+	return err
+}
+
+func (s *hubSnapshot) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.stopped
+	return s.Flush(ctx)
+}
+
 func (s *hubSnapshot) Subscribe(board string, recv chan<- []byte) Subscription {
-	sub := s.hub.Subscribe(board, recv)
+	s.mu.Lock()
+	p, ok := s.boards[board]
+	if !ok {
+		p = &pendingSnapshot{}
+		s.boards[board] = p
+	}
+	p.subscribers++
+	if p.unwatch == nil {
+		p.unwatch = make(chan struct{})
+		go s.watch(board, p, p.unwatch)
+	}
+	s.mu.Unlock()
+
+	sub := &subSnapshot{
+		board: board,
+		hub:   s,
+		sub:   s.hub.Subscribe(board, recv),
+	}
+	if err := sub.sendSnapshot(); err != nil {
+		logger.Error(context.Background(), "cannot send snapshot",
+			"board", board,
+			"error", err.Error())
+	}
+	return sub
+}
+
+// Dropped returns the number of dropped messages, as counted by the wrapped
+// hub.
+func (s *hubSnapshot) Dropped() uint64 {
+	if dc, ok := s.hub.(DropCounter); ok {
+		return dc.Dropped()
+	}
+	return 0
+}
+
+// Check returns an error if the hub was closed or if the wrapped hub is not
+// healthy.
+func (s *hubSnapshot) Check(ctx context.Context) error {
+	select {
+	case <-s.stop:
+		return errors.New("hub closed")
+	default:
+	}
+	if c, ok := s.hub.(Checker); ok {
+		return c.Check(ctx)
+	}
+	return nil
+}
+
+// load returns the latest board state. Pending state is used if present,
+// otherwise state is loaded from the store. If the board was never updated,
+// EmptyState is returned.
+func (s *hubSnapshot) load(board string) ([]byte, error) {
+	s.mu.Lock()
+	if p, ok := s.boards[board]; ok && p.data != nil {
+		data := p.data
+		s.mu.Unlock()
+		return data, nil
+	}
+	s.mu.Unlock()
+
+	ctx, done := context.WithTimeout(context.Background(), 2*time.Second)
+	defer done()
+
+	switch data, err := s.store.Load(ctx, board); err {
+	case nil:
+		return data, nil
+	case ErrNoSnapshot:
+		return EmptyState, nil
+	default:
+		return nil, err
+	}
+}
 
-	subsnap := &subSnapshot{
-		key: "board:snapshot:" + board,
-		rp:  s.rp,
-		sub: sub,
+// update sets given data as the latest board state. Returned error is the
+// result of the last failed write of the board state.
+func (s *hubSnapshot) update(board string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.boards[board]
+	if !ok {
+		p = &pendingSnapshot{}
+		s.boards[board] = p
+	}
+	p.data = data
+	p.dirty = true
+	p.version = nextVersion(p.version)
+	return p.err
+}
+
+// nextVersion returns state version that is greater than the previous one.
+// Time is used, so that versions are comparable between processes.
+func nextVersion(prev int64) int64 {
+	if v := time.Now().UnixNano(); v > prev {
+		return v
+	}
+	return prev + 1
+}
+
+// watch keeps the board state up to date with messages delivered by the
+// wrapped hub, until unwatch is closed or the hub is closed. Messages
+// broadcasted by other processes are stored by the author, so observed state
+// is not marked as dirty.
+func (s *hubSnapshot) watch(board string, p *pendingSnapshot, unwatch <-chan struct{}) {
+	for {
+		recv := make(chan []byte, 16)
+		sub := s.hub.Subscribe(board, recv)
+	receive:
+		for {
+			select {
+			case data := <-recv:
+				s.observe(p, data)
+			case <-sub.Done():
+				break receive
+			case <-unwatch:
+				sub.Close()
+				return
+			case <-s.stop:
+				sub.Close()
+				return
+			}
+		}
+		sub.Close()
+
+		// messages might have been lost, so the state must be loaded
+		// from the store unless it is waiting to be written
+		s.mu.Lock()
+		if !p.dirty {
+			p.data = nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+// observe sets state delivered by the hub as the latest board state.
+func (s *hubSnapshot) observe(p *pendingSnapshot, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if bytes.Equal(p.data, data) {
+		// broadcasted by a local subscriber and already updated
+		return
+	}
+	p.data = data
+	p.version = nextVersion(p.version)
+}
+
+// leave is called when board subscription is closed. Board state is flushed
+// if this was the last subscriber.
+func (s *hubSnapshot) leave(board string) {
+	s.mu.Lock()
+	p, ok := s.boards[board]
+	if !ok || p.subscribers == 0 {
+		s.mu.Unlock()
+		return
+	}
+	p.subscribers--
+	last := p.subscribers == 0
+	if last {
+		close(p.unwatch)
+		p.unwatch = nil
+	}
+	s.mu.Unlock()
+
+	if !last {
+		return
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), 2*time.Second)
+	defer done()
+	if err := s.flush(ctx, board); err != nil {
+		logger.Error(ctx, "cannot flush snapshot",
+			"board", board,
+			"error", err.Error())
 	}
-	subsnap.sendSnapshot()
-	return subsnap
 }
 
 type subSnapshot struct {
-	key string
-	sub Subscription
-	rp  *redis.Pool
+	board string
+	hub   *hubSnapshot
+	sub   Subscription
+	once  sync.Once
 }
 
+// sendSnapshot sends the latest board state to the subscriber.
 func (s *subSnapshot) sendSnapshot() error {
-	rc := s.rp.Get()
-	defer rc.Close()
-
-	data, err := redis.Bytes(rc.Do("GET", s.key))
+	data, err := s.hub.load(s.board)
 	if err != nil {
-		return fmt.Errorf("cannot get from db: %s", err)
+		return err
 	}
-
 	return s.Send(data)
 }
 
@@ -55,17 +386,27 @@ func (s *subSnapshot) Send(data []byte) error {
 	return s.sub.Send(data)
 }
 
+// Broadcast sends message to all other subscribers and stores it as the
+// latest board state. Message is broadcasted even if the board state
+// cannot be stored, but ErrSnapshotFailed is returned.
 func (s *subSnapshot) Broadcast(data []byte) error {
-	rc := s.rp.Get()
-	defer rc.Close()
+	serr := s.hub.update(s.board, data)
 
-	if _, err := rc.Do("SET", s.key, data); err != nil {
-		log.Printf("cannot create snapshot: %s", err)
+	if err := s.sub.Broadcast(data); err != nil {
+		return err
 	}
+	if serr != nil {
+		return ErrSnapshotFailed
+	}
+	return nil
+}
 
-	return s.sub.Broadcast(data)
+func (s *subSnapshot) Done() <-chan struct{} {
+	return s.sub.Done()
 }
 
-func (s subSnapshot) Close() error {
-	return s.sub.Close()
+func (s *subSnapshot) Close() error {
+	err := s.sub.Close()
+	s.once.Do(func() { s.hub.leave(s.board) })
+	return err
 }
@@ -0,0 +1,393 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/migrate"
+	"github.com/husio/scrumboard/server/pgtest"
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestSnapshotStoreImplementations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	implementations := map[string]func(t *testing.T) SnapshotStore{
+		"memory": func(t *testing.T) SnapshotStore {
+			return NewMemorySnapshotStore()
+		},
+		"redis": func(t *testing.T) SnapshotStore {
+			return NewRedisSnapshotStore(redistest.NewPool(), "board:snapshot:")
+		},
+		"postgres": func(t *testing.T) SnapshotStore {
+			db := pgtest.Open(t)
+			if err := migrate.Run(context.Background(), db, "pubsub", PostgresMigrations); err != nil {
+				t.Fatalf("cannot migrate: %s", err)
+			}
+			return NewPostgresSnapshotStore(db)
+		},
+		"file": func(t *testing.T) SnapshotStore {
+			store, err := NewFileSnapshotStore(dir)
+			if err != nil {
+				t.Fatalf("cannot create file store: %s", err)
+			}
+			return store
+		},
+		"compress": func(t *testing.T) SnapshotStore {
+			return Compress(NewMemorySnapshotStore())
+		},
+	}
+
+	for name, newStore := range implementations {
+		t.Run(name, func(t *testing.T) {
+			testSnapshotStore(t, newStore(t))
+		})
+	}
+}
+
+func testSnapshotStore(t *testing.T, store SnapshotStore) {
+	ctx := context.Background()
+
+	if _, err := store.Load(ctx, "first-board"); err != ErrNoSnapshot {
+		t.Fatalf("want ErrNoSnapshot, got %v", err)
+	}
+
+	for i, state := range []string{`{"cards":[],"rows":4}`, `{"cards":[],"rows":5}`} {
+		if err := store.Store(ctx, "first-board", []byte(state), int64(10+i)); err != nil {
+			t.Fatalf("cannot store: %s", err)
+		}
+		data, err := store.Load(ctx, "first-board")
+		if err != nil {
+			t.Fatalf("cannot load: %s", err)
+		}
+		if string(data) != state {
+			t.Fatalf("want %s, got %s", state, data)
+		}
+	}
+
+	// older state never replaces newer one, same version can be written
+	// again
+	if err := store.Store(ctx, "first-board", []byte(`{"cards":[],"rows":1}`), 9); err != ErrStaleSnapshot {
+		t.Fatalf("want ErrStaleSnapshot, got %v", err)
+	}
+	if data, err := store.Load(ctx, "first-board"); err != nil || string(data) != `{"cards":[],"rows":5}` {
+		t.Fatalf("stale state written: %s, %v", data, err)
+	}
+	if err := store.Store(ctx, "first-board", []byte(`{"cards":[],"rows":5}`), 11); err != nil {
+		t.Fatalf("cannot store: %s", err)
+	}
+
+	if err := store.Delete(ctx, "first-board"); err != nil {
+		t.Fatalf("cannot delete: %s", err)
+	}
+	if err := store.Delete(ctx, "first-board"); err != ErrNoSnapshot {
+		t.Fatalf("want ErrNoSnapshot, got %v", err)
+	}
+	if _, err := store.Load(ctx, "first-board"); err != ErrNoSnapshot {
+		t.Fatalf("want ErrNoSnapshot, got %v", err)
+	}
+}
+
+func TestCompressLoadsUncompressedState(t *testing.T) {
+	ctx := context.Background()
+	raw := NewMemorySnapshotStore()
+
+	state := `{"cards":[],"rows":4}`
+	if err := raw.Store(ctx, "board", []byte(state), 1); err != nil {
+		t.Fatalf("cannot store: %s", err)
+	}
+	data, err := Compress(raw).Load(ctx, "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != state {
+		t.Fatalf("want %s, got %s", state, data)
+	}
+}
+
+func TestSnapshotSendsLatestState(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	hub := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute)
+	defer hub.Close(context.Background())
+
+	ra := make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+	if msg := receive(t, ra); msg != string(EmptyState) {
+		t.Fatalf("want empty state, got %s", msg)
+	}
+
+	state := `{"cards":[],"rows":5}`
+	if err := a.Broadcast([]byte(state)); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+
+	// pending state is sent before it is written to the store
+	rb := make(chan []byte, 4)
+	b := hub.Subscribe("board", rb)
+	defer b.Close()
+	if msg := receive(t, rb); msg != state {
+		t.Fatalf("want %s, got %s", state, msg)
+	}
+	if _, err := store.Load(context.Background(), "board"); err != ErrNoSnapshot {
+		t.Fatalf("state written before flush: %v", err)
+	}
+}
+
+func TestSnapshotFlushedWhenLastSubscriberLeaves(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	hub := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute)
+	defer hub.Close(context.Background())
+
+	a := hub.Subscribe("board", make(chan []byte, 4))
+	b := hub.Subscribe("board", make(chan []byte, 4))
+
+	state := `{"cards":[],"rows":5}`
+	a.Broadcast([]byte(state))
+
+	a.Close()
+	if _, err := store.Load(context.Background(), "board"); err != ErrNoSnapshot {
+		t.Fatalf("state written while board has subscribers: %v", err)
+	}
+	b.Close()
+
+	data, err := store.Load(context.Background(), "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != state {
+		t.Fatalf("want %s, got %s", state, data)
+	}
+}
+
+func TestSnapshotCloseFlushesPendingState(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	hub := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute)
+
+	a := hub.Subscribe("board", make(chan []byte, 4))
+	defer a.Close()
+	state := `{"cards":[],"rows":5}`
+	a.Broadcast([]byte(state))
+
+	if err := hub.Close(context.Background()); err != nil {
+		t.Fatalf("cannot close: %s", err)
+	}
+	data, err := store.Load(context.Background(), "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != state {
+		t.Fatalf("want %s, got %s", state, data)
+	}
+}
+
+func TestSnapshotCloseTwice(t *testing.T) {
+	hub := Snapshot(NewMemorySnapshotStore(), NewMemoryHub(DisconnectSlow), time.Minute)
+	if err := hub.Close(context.Background()); err != nil {
+		t.Fatalf("cannot close: %s", err)
+	}
+	if err := hub.Close(context.Background()); err != nil {
+		t.Fatalf("cannot close: %s", err)
+	}
+}
+
+func TestSnapshotFlushKeepsNewerPendingState(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	hub := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute).(*hubSnapshot)
+	defer hub.Close(context.Background())
+
+	a := hub.Subscribe("board", make(chan []byte, 4))
+	a.Broadcast([]byte(`{"v":1}`))
+
+	// last subscriber leaves while other flush of the board is writing
+	hub.mu.Lock()
+	p := hub.boards["board"]
+	hub.mu.Unlock()
+	p.flushing.Lock()
+	left := make(chan struct{})
+	go func() {
+		a.Close()
+		close(left)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// other flush stored the state and forgot the board, which is
+	// subscribed and modified again before the leaving flush finished
+	hub.mu.Lock()
+	p.dirty = false
+	delete(hub.boards, "board")
+	hub.mu.Unlock()
+	b := hub.Subscribe("board", make(chan []byte, 4))
+	defer b.Close()
+	b.Broadcast([]byte(`{"v":2}`))
+
+	p.flushing.Unlock()
+	<-left
+
+	if err := hub.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush: %s", err)
+	}
+	data, err := store.Load(context.Background(), "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != `{"v":2}` {
+		t.Fatalf("want newer state, got %s", data)
+	}
+}
+
+type failingStore struct {
+	SnapshotStore
+}
+
+func (failingStore) Store(context.Context, string, []byte, int64) error {
+	return errors.New("store failure")
+}
+
+func TestSnapshotFailureReported(t *testing.T) {
+	hub := Snapshot(failingStore{NewMemorySnapshotStore()}, NewMemoryHub(DisconnectSlow), time.Minute)
+
+	ra, rb := make(chan []byte, 4), make(chan []byte, 4)
+	a := hub.Subscribe("board", ra)
+	defer a.Close()
+	b := hub.Subscribe("board", rb)
+	defer b.Close()
+	receive(t, ra)
+	receive(t, rb)
+
+	if err := a.Broadcast([]byte(`{"cards":[],"rows":4}`)); err != nil {
+		t.Fatalf("cannot broadcast: %s", err)
+	}
+	if err := hub.Flush(context.Background()); err == nil {
+		t.Fatal("want flush error")
+	}
+
+	// message is delivered even if it cannot be stored
+	if err := a.Broadcast([]byte(`{"cards":[],"rows":5}`)); err != ErrSnapshotFailed {
+		t.Fatalf("want ErrSnapshotFailed, got %v", err)
+	}
+	receive(t, rb)
+	if msg := receive(t, rb); msg != `{"cards":[],"rows":5}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestSnapshotSharedHub(t *testing.T) {
+	db := redistest.NewDB()
+	store := NewRedisSnapshotStore(db.Pool(), "board:snapshot:")
+	first := Snapshot(store, NewRedisHub(db.Pool(), DisconnectSlow), time.Minute)
+	defer first.Close(context.Background())
+	second := Snapshot(store, NewRedisHub(db.Pool(), DisconnectSlow), time.Minute)
+	defer second.Close(context.Background())
+	waitConnected(t, first)
+	waitConnected(t, second)
+
+	ra, rb := make(chan []byte, 4), make(chan []byte, 4)
+	alice := first.Subscribe("board", ra)
+	defer alice.Close()
+	bob := second.Subscribe("board", rb)
+	defer bob.Close()
+	receive(t, ra)
+	receive(t, rb)
+
+	alice.Broadcast([]byte(`{"v":1}`))
+	if msg := receive(t, rb); msg != `{"v":1}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+	bob.Broadcast([]byte(`{"v":2}`))
+	if msg := receive(t, ra); msg != `{"v":2}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+
+	// state broadcasted by other process is sent to new subscribers
+	waitPendingState(t, first, "board", `{"v":2}`)
+	rc := make(chan []byte, 4)
+	carol := first.Subscribe("board", rc)
+	defer carol.Close()
+	if msg := receive(t, rc); msg != `{"v":2}` {
+		t.Fatalf("want latest state, got %s", msg)
+	}
+
+	// order of flushes does not matter, the latest state is stored
+	if err := second.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush: %s", err)
+	}
+	if err := first.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush: %s", err)
+	}
+	data, err := store.Load(context.Background(), "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != `{"v":2}` {
+		t.Fatalf("want latest state stored, got %s", data)
+	}
+}
+
+func TestSnapshotStaleFlushIgnored(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	first := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute)
+	defer first.Close(context.Background())
+	second := Snapshot(store, NewMemoryHub(DisconnectSlow), time.Minute)
+	defer second.Close(context.Background())
+
+	// processes are not connected, so the first process is not aware
+	// of the newer state
+	alice := first.Subscribe("board", make(chan []byte, 4))
+	defer alice.Close()
+	bob := second.Subscribe("board", make(chan []byte, 4))
+	defer bob.Close()
+	alice.Broadcast([]byte(`{"v":1}`))
+	bob.Broadcast([]byte(`{"v":2}`))
+
+	if err := second.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush: %s", err)
+	}
+	if err := first.Flush(context.Background()); err != nil {
+		t.Fatalf("stale flush failed: %s", err)
+	}
+	data, err := store.Load(context.Background(), "board")
+	if err != nil {
+		t.Fatalf("cannot load: %s", err)
+	}
+	if string(data) != `{"v":2}` {
+		t.Fatalf("older state stored: %s", data)
+	}
+
+	// newer state is loaded from the store by the stale process
+	rc := make(chan []byte, 4)
+	carol := first.Subscribe("board", rc)
+	defer carol.Close()
+	if msg := receive(t, rc); msg != `{"v":2}` {
+		t.Fatalf("want latest state, got %s", msg)
+	}
+}
+
+func waitPendingState(t *testing.T, hub SnapshotHub, board, state string) {
+	t.Helper()
+	s := hub.(*hubSnapshot)
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		var data []byte
+		if p, ok := s.boards[board]; ok {
+			data = p.data
+		}
+		s.mu.Unlock()
+		if string(data) == state {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("want %s pending state, got %s", state, data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
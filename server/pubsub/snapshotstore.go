@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// SnapshotStore persists the latest state of every board, so that it can be
+// sent to new subscribers.
+type SnapshotStore interface {
+	// Load returns the latest stored state of the board. ErrNoSnapshot is
+	// returned if board state was never stored.
+	Load(ctx context.Context, board string) ([]byte, error)
+
+	// Store overwrites board state with given one. Stored state is kept
+	// and ErrStaleSnapshot is returned if it has a higher version, so
+	// that processes flushing concurrently never replace newer state
+	// with an older one.
+	Store(ctx context.Context, board string, data []byte, version int64) error
+
+	// Delete removes board state. ErrNoSnapshot is returned if board state
+	// was never stored.
+	Delete(ctx context.Context, board string) error
+}
+
+var (
+	ErrNoSnapshot    = errors.New("no snapshot")
+	ErrStaleSnapshot = errors.New("stale snapshot")
+)
+
+// EmptyState is the initial state of every board, used when no snapshot was
+// stored yet.
+var EmptyState = []byte(`{"cards":[],"rows":3}`)
+
+type memSnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]memSnapshot
+}
+
+type memSnapshot struct {
+	data    []byte
+	version int64
+}
+
+var _ SnapshotStore = (*memSnapshotStore)(nil)
+
+// NewMemorySnapshotStore returns snapshot store that keeps all data in
+// process memory. All data is lost when the process exits.
+func NewMemorySnapshotStore() SnapshotStore {
+	return &memSnapshotStore{
+		snapshots: make(map[string]memSnapshot),
+	}
+}
+
+func (s *memSnapshotStore) Load(ctx context.Context, board string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap, ok := s.snapshots[board]
+	if !ok {
+		return nil, ErrNoSnapshot
+	}
+	return snap.data, nil
+}
+
+func (s *memSnapshotStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snapshots[board].version > version {
+		return ErrStaleSnapshot
+	}
+	s.snapshots[board] = memSnapshot{
+		data:    append([]byte(nil), data...),
+		version: version,
+	}
+	return nil
+}
+
+func (s *memSnapshotStore) Delete(ctx context.Context, board string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.snapshots[board]; !ok {
+		return ErrNoSnapshot
+	}
+	delete(s.snapshots, board)
+	return nil
+}
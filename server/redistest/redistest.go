@@ -0,0 +1,781 @@
+// Package redistest provides in memory implementation of redis server, that
+// supports only commands used by the application, including publish/subscribe
+// and transactions. It is meant to be used by tests only.
+package redistest
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// DB is in memory redis database. All connections created by the same
+// database share the data.
+type DB struct {
+	mu   sync.Mutex
+	keys map[string]*value
+	// channels holds connections subscribed to every channel
+	channels map[string]map[*conn]struct{}
+	// revisions are incremented on every key modification, so that
+	// watched keys can be checked
+	revisions map[string]uint64
+}
+
+type value struct {
+	str       []byte
+	hash      map[string][]byte
+	set       map[string]struct{}
+	zset      map[string]float64
+	expiresAt time.Time
+}
+
+// NewDB returns empty database.
+func NewDB() *DB {
+	return &DB{
+		keys:      make(map[string]*value),
+		channels:  make(map[string]map[*conn]struct{}),
+		revisions: make(map[string]uint64),
+	}
+}
+
+// NewPool returns redis pool connected to a new, empty database.
+func NewPool() *redis.Pool {
+	return NewDB().Pool()
+}
+
+// Pool returns redis pool with all connections using the database.
+func (db *DB) Pool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle: 3,
+		Dial:    func() (redis.Conn, error) { return db.Conn(), nil },
+	}
+}
+
+// Conn returns new connection to the database.
+func (db *DB) Conn() redis.Conn {
+	return &conn{
+		db:     db,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// DisconnectSubscribers closes all connections subscribed to any channel, as
+// if the connection to the server was lost. Data is not modified.
+func (db *DB) DisconnectSubscribers() {
+	db.mu.Lock()
+	conns := make(map[*conn]struct{})
+	for _, subscribers := range db.channels {
+		for c := range subscribers {
+			conns[c] = struct{}{}
+		}
+	}
+	db.mu.Unlock()
+
+	for c := range conns {
+		c.Close()
+	}
+}
+
+// conn is a single connection. Replies are queued until received, so that
+// messages published to subscribed channels can be received as well.
+type conn struct {
+	db *DB
+
+	mu      sync.Mutex
+	replies []interface{}
+	// subscribed is the number of subscribed channels
+	subscribed int
+	closed     bool
+	notify     chan struct{}
+	done       chan struct{}
+
+	// channels is guarded by database lock
+	channels map[string]struct{}
+
+	// watched holds revisions of the watched keys
+	watched map[string]uint64
+	// queued holds commands of the started transaction
+	queued [][]string
+	multi  bool
+}
+
+// push queues replies, waking up blocked receiver.
+func (c *conn) push(replies ...interface{}) {
+	c.mu.Lock()
+	c.replies = append(c.replies, replies...)
+	c.mu.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *conn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+
+	c.db.unsubscribe(c, nil)
+	return nil
+}
+
+func (c *conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return fmt.Errorf("connection closed")
+	}
+	return nil
+}
+
+func (c *conn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if cmd != "" {
+		if err := c.Send(cmd, args...); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.Lock()
+	replies := c.replies
+	c.replies = nil
+	c.mu.Unlock()
+
+	if cmd == "" {
+		return replies, nil
+	}
+	// return only the reply of the last command
+	reply := replies[len(replies)-1]
+	if err, ok := reply.(redis.Error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *conn) Send(cmd string, args ...interface{}) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	strargs := make([]string, len(args))
+	for i, a := range args {
+		switch a := a.(type) {
+		case []byte:
+			strargs[i] = string(a)
+		default:
+			strargs[i] = fmt.Sprint(a)
+		}
+	}
+	switch cmd = strings.ToUpper(cmd); cmd {
+	case "SUBSCRIBE":
+		if len(strargs) == 0 {
+			c.push(errArgs(cmd))
+			return nil
+		}
+		c.push(c.db.subscribe(c, strargs)...)
+	case "UNSUBSCRIBE":
+		c.push(c.db.unsubscribe(c, strargs)...)
+	case "PUNSUBSCRIBE":
+		// pattern subscriptions are not supported
+		c.mu.Lock()
+		n := c.subscribed
+		c.mu.Unlock()
+		c.push([]interface{}{[]byte("punsubscribe"), nil, int64(n)})
+	case "WATCH":
+		if len(strargs) == 0 {
+			c.push(errArgs(cmd))
+			return nil
+		}
+		if c.watched == nil {
+			c.watched = make(map[string]uint64)
+		}
+		c.db.mu.Lock()
+		for _, key := range strargs {
+			c.watched[key] = c.db.revisions[key]
+		}
+		c.db.mu.Unlock()
+		c.push("OK")
+	case "UNWATCH":
+		c.watched = nil
+		c.push("OK")
+	case "MULTI":
+		if c.multi {
+			c.push(redis.Error("ERR MULTI calls can not be nested"))
+			return nil
+		}
+		c.multi = true
+		c.push("OK")
+	case "DISCARD":
+		if !c.multi {
+			c.push(redis.Error("ERR DISCARD without MULTI"))
+			return nil
+		}
+		c.multi, c.queued, c.watched = false, nil, nil
+		c.push("OK")
+	case "EXEC":
+		if !c.multi {
+			c.push(redis.Error("ERR EXEC without MULTI"))
+			return nil
+		}
+		c.push(c.db.execTx(c.watched, c.queued))
+		c.multi, c.queued, c.watched = false, nil, nil
+	default:
+		if c.multi {
+			c.queued = append(c.queued, append([]string{cmd}, strargs...))
+			c.push("QUEUED")
+			return nil
+		}
+		c.push(c.db.exec(cmd, strargs))
+	}
+	return nil
+}
+
+func (c *conn) Flush() error {
+	return nil
+}
+
+// Receive returns the oldest not received reply. Subscribed connection is
+// waiting for the published messages.
+func (c *conn) Receive() (interface{}, error) {
+	for {
+		c.mu.Lock()
+		if len(c.replies) != 0 {
+			reply := c.replies[0]
+			c.replies = c.replies[1:]
+			c.mu.Unlock()
+			if err, ok := reply.(redis.Error); ok {
+				return nil, err
+			}
+			return reply, nil
+		}
+		closed, subscribed := c.closed, c.subscribed != 0
+		c.mu.Unlock()
+
+		switch {
+		case closed:
+			return nil, fmt.Errorf("connection closed")
+		case !subscribed:
+			return nil, fmt.Errorf("no pending replies")
+		}
+		select {
+		case <-c.notify:
+		case <-c.done:
+		}
+	}
+}
+
+// subscribe adds connection to subscribers of given channels and returns
+// subscription confirmations.
+func (db *DB) subscribe(c *conn, channels []string) []interface{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if c.channels == nil {
+		c.channels = make(map[string]struct{})
+	}
+	replies := make([]interface{}, 0, len(channels))
+	for _, ch := range channels {
+		if db.channels[ch] == nil {
+			db.channels[ch] = make(map[*conn]struct{})
+		}
+		db.channels[ch][c] = struct{}{}
+		c.channels[ch] = struct{}{}
+		replies = append(replies, []interface{}{[]byte("subscribe"), []byte(ch), int64(len(c.channels))})
+	}
+	c.mu.Lock()
+	c.subscribed = len(c.channels)
+	c.mu.Unlock()
+	return replies
+}
+
+// unsubscribe removes connection from subscribers of given channels, or all
+// channels if none is given, and returns unsubscription confirmations.
+func (db *DB) unsubscribe(c *conn, channels []string) []interface{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if len(channels) == 0 {
+		for ch := range c.channels {
+			channels = append(channels, ch)
+		}
+		sort.Strings(channels)
+	}
+	var replies []interface{}
+	for _, ch := range channels {
+		delete(c.channels, ch)
+		delete(db.channels[ch], c)
+		if len(db.channels[ch]) == 0 {
+			delete(db.channels, ch)
+		}
+		replies = append(replies, []interface{}{[]byte("unsubscribe"), []byte(ch), int64(len(c.channels))})
+	}
+	if len(replies) == 0 {
+		replies = append(replies, []interface{}{[]byte("unsubscribe"), nil, int64(0)})
+	}
+	c.mu.Lock()
+	c.subscribed = len(c.channels)
+	c.mu.Unlock()
+	return replies
+}
+
+var (
+	errWrongType = redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errSyntax    = redis.Error("ERR syntax error")
+	errNotInt    = redis.Error("ERR value is not an integer or out of range")
+	errNotFloat  = redis.Error("ERR value is not a valid float")
+)
+
+func errArgs(cmd string) redis.Error {
+	return redis.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+}
+
+// get returns value stored under given key, or nil if key is not used.
+// Must be called with lock acquired.
+func (db *DB) get(key string) *value {
+	v, ok := db.keys[key]
+	if !ok {
+		return nil
+	}
+	if !v.expiresAt.IsZero() && !v.expiresAt.After(time.Now()) {
+		delete(db.keys, key)
+		return nil
+	}
+	return v
+}
+
+// execTx executes all commands atomically, unless any of the watched keys
+// was modified, in which case nil is returned.
+func (db *DB) execTx(watched map[string]uint64, cmds [][]string) interface{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for key, rev := range watched {
+		if db.revisions[key] != rev {
+			return nil
+		}
+	}
+	replies := make([]interface{}, 0, len(cmds))
+	for _, cmd := range cmds {
+		replies = append(replies, db.execLocked(cmd[0], cmd[1:]))
+	}
+	return replies
+}
+
+func (db *DB) exec(cmd string, args []string) interface{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.execLocked(cmd, args)
+}
+
+// execLocked executes a single command. Database lock must be held.
+func (db *DB) execLocked(cmd string, args []string) interface{} {
+	switch cmd {
+	case "SET", "DEL", "HMSET", "SADD", "SREM", "ZADD", "ZREM":
+		keys := args
+		if cmd != "DEL" && len(args) > 0 {
+			keys = args[:1]
+		}
+		for _, key := range keys {
+			db.revisions[key]++
+		}
+	}
+
+	switch cmd {
+	case "PING":
+		return "PONG"
+	case "GET":
+		if len(args) != 1 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			return nil
+		}
+		if v.str == nil {
+			return errWrongType
+		}
+		return v.str
+	case "SET":
+		return db.set(args)
+	case "DEL":
+		if len(args) == 0 {
+			return errArgs(cmd)
+		}
+		var n int64
+		for _, key := range args {
+			if db.get(key) != nil {
+				delete(db.keys, key)
+				n++
+			}
+		}
+		return n
+	case "HMSET":
+		if len(args) < 3 || len(args)%2 != 1 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			v = &value{hash: make(map[string][]byte)}
+			db.keys[args[0]] = v
+		}
+		if v.hash == nil {
+			return errWrongType
+		}
+		for i := 1; i < len(args); i += 2 {
+			v.hash[args[i]] = []byte(args[i+1])
+		}
+		return "OK"
+	case "HGETALL":
+		if len(args) != 1 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			return []interface{}{}
+		}
+		if v.hash == nil {
+			return errWrongType
+		}
+		fields := make([]string, 0, len(v.hash))
+		for f := range v.hash {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		reply := make([]interface{}, 0, len(fields)*2)
+		for _, f := range fields {
+			reply = append(reply, []byte(f), v.hash[f])
+		}
+		return reply
+	case "SADD", "SREM":
+		if len(args) < 2 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			if cmd == "SREM" {
+				return int64(0)
+			}
+			v = &value{set: make(map[string]struct{})}
+			db.keys[args[0]] = v
+		}
+		if v.set == nil {
+			return errWrongType
+		}
+		var n int64
+		for _, m := range args[1:] {
+			_, ok := v.set[m]
+			if cmd == "SADD" && !ok {
+				v.set[m] = struct{}{}
+				n++
+			} else if cmd == "SREM" && ok {
+				delete(v.set, m)
+				n++
+			}
+		}
+		if len(v.set) == 0 {
+			delete(db.keys, args[0])
+		}
+		return n
+	case "SMEMBERS":
+		if len(args) != 1 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			return []interface{}{}
+		}
+		if v.set == nil {
+			return errWrongType
+		}
+		members := make([]string, 0, len(v.set))
+		for m := range v.set {
+			members = append(members, m)
+		}
+		sort.Strings(members)
+		return bulks(members)
+	case "ZADD":
+		if len(args) < 1 {
+			return errArgs(cmd)
+		}
+		key, args := args[0], args[1:]
+		// only NX option is supported
+		nx := len(args) != 0 && strings.ToUpper(args[0]) == "NX"
+		if nx {
+			args = args[1:]
+		}
+		if len(args) < 2 || len(args)%2 != 0 {
+			return errArgs(cmd)
+		}
+		v := db.get(key)
+		if v == nil {
+			v = &value{zset: make(map[string]float64)}
+			db.keys[key] = v
+		}
+		if v.zset == nil {
+			return errWrongType
+		}
+		var n int64
+		for i := 0; i < len(args); i += 2 {
+			score, err := strconv.ParseFloat(args[i], 64)
+			if err != nil {
+				return errNotFloat
+			}
+			_, ok := v.zset[args[i+1]]
+			if ok && nx {
+				continue
+			}
+			if !ok {
+				n++
+			}
+			v.zset[args[i+1]] = score
+		}
+		return n
+	case "ZREM":
+		if len(args) < 2 {
+			return errArgs(cmd)
+		}
+		v := db.get(args[0])
+		if v == nil {
+			return int64(0)
+		}
+		if v.zset == nil {
+			return errWrongType
+		}
+		var n int64
+		for _, m := range args[1:] {
+			if _, ok := v.zset[m]; ok {
+				delete(v.zset, m)
+				n++
+			}
+		}
+		if len(v.zset) == 0 {
+			delete(db.keys, args[0])
+		}
+		return n
+	case "ZRANGE":
+		return db.zrange(args)
+	case "ZRANGEBYSCORE":
+		return db.zrangebyscore(args)
+	case "SCAN":
+		// whole key space is always returned in a single iteration
+		if len(args) < 1 {
+			return errArgs(cmd)
+		}
+		pattern := "*"
+		for i := 1; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range db.keys {
+			if db.get(key) == nil {
+				continue
+			}
+			if ok, _ := path.Match(pattern, key); ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		return []interface{}{[]byte("0"), bulks(keys)}
+	case "PUBLISH":
+		if len(args) != 2 {
+			return errArgs(cmd)
+		}
+		msg := []interface{}{[]byte("message"), []byte(args[0]), []byte(args[1])}
+		for c := range db.channels[args[0]] {
+			c.push(msg)
+		}
+		return int64(len(db.channels[args[0]]))
+	case "ECHO":
+		if len(args) != 1 {
+			return errArgs(cmd)
+		}
+		return []byte(args[0])
+	default:
+		return redis.Error(fmt.Sprintf("ERR unknown command '%s'", cmd))
+	}
+}
+
+func (db *DB) set(args []string) interface{} {
+	if len(args) < 2 {
+		return errArgs("SET")
+	}
+	var (
+		expiresAt time.Time
+		nx        bool
+	)
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "PX", "EX":
+			if i+1 >= len(args) {
+				return errSyntax
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return errNotInt
+			}
+			unit := time.Millisecond
+			if strings.ToUpper(args[i]) == "EX" {
+				unit = time.Second
+			}
+			expiresAt = time.Now().Add(time.Duration(n) * unit)
+			i++
+		default:
+			return errSyntax
+		}
+	}
+	if nx && db.get(args[0]) != nil {
+		return nil
+	}
+	db.keys[args[0]] = &value{str: []byte(args[1]), expiresAt: expiresAt}
+	return "OK"
+}
+
+func (db *DB) zrange(args []string) interface{} {
+	if len(args) < 3 {
+		return errArgs("ZRANGE")
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errNotInt
+	}
+	withScores := len(args) == 4 && strings.ToUpper(args[3]) == "WITHSCORES"
+
+	v := db.get(args[0])
+	if v == nil {
+		return []interface{}{}
+	}
+	if v.zset == nil {
+		return errWrongType
+	}
+	members := sortedMembers(v.zset)
+	if start < 0 {
+		start += len(members)
+	}
+	if stop < 0 {
+		stop += len(members)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= len(members) {
+		stop = len(members) - 1
+	}
+
+	var reply []string
+	for i := start; i <= stop; i++ {
+		reply = append(reply, members[i])
+		if withScores {
+			reply = append(reply, strconv.FormatFloat(v.zset[members[i]], 'f', -1, 64))
+		}
+	}
+	return bulks(reply)
+}
+
+func (db *DB) zrangebyscore(args []string) interface{} {
+	if len(args) < 3 {
+		return errArgs("ZRANGEBYSCORE")
+	}
+	min, minExcl, err := parseScore(args[1])
+	if err != nil {
+		return errNotFloat
+	}
+	max, maxExcl, err := parseScore(args[2])
+	if err != nil {
+		return errNotFloat
+	}
+	offset, count := 0, -1
+	if len(args) == 6 && strings.ToUpper(args[3]) == "LIMIT" {
+		var err1, err2 error
+		offset, err1 = strconv.Atoi(args[4])
+		count, err2 = strconv.Atoi(args[5])
+		if err1 != nil || err2 != nil {
+			return errNotInt
+		}
+	} else if len(args) != 3 {
+		return errSyntax
+	}
+
+	v := db.get(args[0])
+	if v == nil {
+		return []interface{}{}
+	}
+	if v.zset == nil {
+		return errWrongType
+	}
+
+	var reply []string
+	for _, m := range sortedMembers(v.zset) {
+		score := v.zset[m]
+		if score < min || (minExcl && score == min) {
+			continue
+		}
+		if score > max || (maxExcl && score == max) {
+			continue
+		}
+		reply = append(reply, m)
+	}
+	if offset > len(reply) {
+		offset = len(reply)
+	}
+	reply = reply[offset:]
+	if count >= 0 && count < len(reply) {
+		reply = reply[:count]
+	}
+	return bulks(reply)
+}
+
+func parseScore(s string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+	switch s {
+	case "-inf":
+		return -1 << 62, exclusive, nil
+	case "+inf", "inf":
+		return 1 << 62, exclusive, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, exclusive, err
+}
+
+// sortedMembers returns sorted set members ordered by score.
+func sortedMembers(zset map[string]float64) []string {
+	members := make([]string, 0, len(zset))
+	for m := range zset {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if zset[members[i]] == zset[members[j]] {
+			return members[i] < members[j]
+		}
+		return zset[members[i]] < zset[members[j]]
+	})
+	return members
+}
+
+func bulks(values []string) []interface{} {
+	reply := make([]interface{}, len(values))
+	for i, v := range values {
+		reply[i] = []byte(v)
+	}
+	return reply
+}
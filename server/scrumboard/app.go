@@ -1,8 +1,11 @@
 package scrumboard
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/husio/scrumboard/server/auth"
 	"github.com/husio/scrumboard/server/pubsub"
@@ -12,20 +15,30 @@ import (
 type ScrumBoardApp struct {
 	html  surf.Renderer
 	debug bool
-	mux   http.Handler
+	mux   *surf.Router
 	log   surf.Logger
 	hub   pubsub.Hub
-	auth  Authenticator
 	bs    BoardStore
-}
 
-type Authenticator interface {
-	CurrentAccount(*http.Request) (*auth.Account, error)
+	streams *eventStreams
+	// pollTimeout and pollExpire configure long-polling clients
+	pollTimeout time.Duration
+	pollExpire  time.Duration
+
+	// closing is closed when the server is shutting down and all board
+	// clients must be disconnected
+	closing chan struct{}
+	// clients tracks running websocket, event stream and polling handlers
+	clients sync.WaitGroup
+	// mu guards closed flag, so that no client is added to the wait group
+	// after Shutdown started waiting for it
+	mu     sync.Mutex
+	closed bool
 }
 
 func NewApp(
 	html surf.Renderer,
-	auth Authenticator,
+	authenticator auth.Authenticator,
 	bs BoardStore,
 	hub pubsub.Hub,
 	debug bool,
@@ -33,17 +46,28 @@ func NewApp(
 	app := ScrumBoardApp{
 		html:  html,
 		log:   surf.NewLogger(os.Stdout, "app", "scrumboard"),
-		auth:  auth,
 		hub:   hub,
 		debug: debug,
 		bs:    bs,
+
+		streams:     newEventStreams(),
+		pollTimeout: defaultPollTimeout,
+		pollExpire:  defaultPollExpire,
+		closing:     make(chan struct{}),
 	}
 
 	rt := surf.NewRouter()
-	rt.Get(`/`, app.index)
-	rt.Post(`/new`, app.newBoard)
-	rt.Get(`/b/<board-id>`, app.board)
-	rt.Get(`/ws/<board-id>`, app.handleClient)
+	pages := rt.Group(
+		auth.LoginRequired(authenticator),
+		surf.CSRFMiddleware(html, auth.SessionID, !debug),
+	)
+	pages.Get(`/`, app.index).Name("index")
+	pages.Post(`/new`, app.newBoard).Name("new-board")
+	pages.Get(`/b/<board-id>`, app.board).Name("board")
+	rt.Get(`/ws/<board-id>`, app.handleClient).Name("board-websocket")
+	rt.Get(`/events/<board-id>`, app.handleEvents).Name("board-events")
+	rt.Post(`/events/<board-id>`, app.handleEventsUpdate)
+	rt.Get(`/poll/<board-id>`, app.handlePoll).Name("board-poll")
 	app.mux = rt
 
 	return &app
@@ -52,3 +76,52 @@ func NewApp(
 func (app *ScrumBoardApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	app.mux.ServeHTTP(w, r)
 }
+
+// Match returns true if application is serving given path.
+func (app *ScrumBoardApp) Match(path string) bool {
+	return app.mux.Match(path)
+}
+
+// URL returns path of the named application route.
+func (app *ScrumBoardApp) URL(name string, args ...string) (string, error) {
+	return app.mux.URL(name, args...)
+}
+
+// Shutdown disconnects all board clients, asking them to reconnect, and waits
+// until all their subscriptions are closed. New clients are rejected.
+//
+// Websocket connections are hijacked and therefore not tracked by
+// http.Server, so Shutdown must be called together with server shutdown.
+func (app *ScrumBoardApp) Shutdown(ctx context.Context) error {
+	app.mu.Lock()
+	if !app.closed {
+		app.closed = true
+		close(app.closing)
+	}
+	app.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		app.clients.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// connect registers new board client. False is returned if the server is
+// shutting down and client must not be served.
+func (app *ScrumBoardApp) connect() bool {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	if app.closed {
+		return false
+	}
+	app.clients.Add(1)
+	return true
+}
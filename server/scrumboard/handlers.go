@@ -17,11 +17,7 @@ func (app *ScrumBoardApp) index(w http.ResponseWriter, r *http.Request) {
 	ctx, done := context.WithTimeout(r.Context(), 2*time.Second)
 	defer done()
 
-	account, err := app.auth.CurrentAccount(r)
-	if err != nil {
-		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
-		return
-	}
+	account := auth.ContextAccount(ctx)
 
 	boards, err := app.bs.UserBoards(ctx, strconv.Itoa(account.AccountID))
 	if err != nil {
@@ -32,13 +28,15 @@ func (app *ScrumBoardApp) index(w http.ResponseWriter, r *http.Request) {
 	}
 
 	content := struct {
-		Account *auth.Account
-		Boards  []*Board
-		Debug   bool
+		Account   *auth.Account
+		Boards    []*Board
+		Debug     bool
+		CSRFToken string
 	}{
-		Account: account,
-		Boards:  boards,
-		Debug:   app.debug,
+		Account:   account,
+		Boards:    boards,
+		Debug:     app.debug,
+		CSRFToken: surf.CSRFToken(ctx),
 	}
 	app.html.Render(w, http.StatusOK, "index.tmpl", content)
 }
@@ -47,11 +45,7 @@ func (app *ScrumBoardApp) board(w http.ResponseWriter, r *http.Request) {
 	ctx, done := context.WithTimeout(r.Context(), 2*time.Second)
 	defer done()
 
-	account, err := app.auth.CurrentAccount(r)
-	if err != nil {
-		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
-		return
-	}
+	account := auth.ContextAccount(ctx)
 
 	boardID := surf.PathArg(r, 0)
 
@@ -63,14 +57,35 @@ func (app *ScrumBoardApp) board(w http.ResponseWriter, r *http.Request) {
 			"error", err.Error())
 	}
 
+	wsPath, err := surf.ReverseURL(ctx, "board-websocket", boardID)
+	if err != nil {
+		app.log.Error(ctx, "cannot build websocket URL",
+			"board", boardID,
+			"error", err.Error())
+		app.html.RenderDefault(w, http.StatusInternalServerError)
+		return
+	}
+	// flags are passed to the client application, which completes the
+	// websocket address with the host used by the browser
+	type flags struct {
+		GithubToken   string `json:"githubToken"`
+		WebsocketPath string `json:"websocketPath"`
+	}
 	content := struct {
 		Account *auth.Account
 		Debug   bool
 		BoardID string
+		Nonce   string
+		Flags   flags
 	}{
 		Account: account,
 		Debug:   app.debug,
 		BoardID: boardID,
+		Nonce:   surf.CSPNonce(ctx),
+		Flags: flags{
+			GithubToken:   account.AccessToken,
+			WebsocketPath: wsPath,
+		},
 	}
 	app.html.Render(w, http.StatusOK, "board.tmpl", content)
 }
@@ -79,11 +94,7 @@ func (app *ScrumBoardApp) newBoard(w http.ResponseWriter, r *http.Request) {
 	ctx, done := context.WithTimeout(r.Context(), 2*time.Second)
 	defer done()
 
-	account, err := app.auth.CurrentAccount(r)
-	if err != nil {
-		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
-		return
-	}
+	account := auth.ContextAccount(ctx)
 
 	name := strings.TrimSpace(r.FormValue("name"))
 	if name == "" {
@@ -109,7 +120,15 @@ func (app *ScrumBoardApp) newBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, "/b/"+board.ID, http.StatusSeeOther)
+	boardURL, err := surf.ReverseURL(ctx, "board", board.ID)
+	if err != nil {
+		app.log.Error(ctx, "cannot build board URL",
+			"board", board.ID,
+			"error", err.Error())
+		app.html.RenderDefault(w, http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, boardURL, http.StatusSeeOther)
 }
 
 func genBoardID() string {
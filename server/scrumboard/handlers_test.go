@@ -0,0 +1,220 @@
+package scrumboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/auth"
+	"github.com/husio/scrumboard/server/pubsub"
+	"github.com/husio/scrumboard/server/surf"
+)
+
+type fakeAuth struct {
+	account *auth.Account
+}
+
+func (a *fakeAuth) CurrentAccount(*http.Request) (*auth.Account, error) {
+	if a.account == nil {
+		return nil, auth.ErrNoSession
+	}
+	return a.account, nil
+}
+
+// newTestApp returns application using in memory storage and account
+// authenticated if given. Returned router is serving the application together
+// with authentication routes, as in production.
+func newTestApp(t *testing.T, account *auth.Account) (*ScrumBoardApp, *surf.Router, BoardStore) {
+	t.Helper()
+
+	html := surf.LoadTemplates("../../templates/*.tmpl")
+	assets, err := surf.NewAssets(os.DirFS("../../dist"), "/static")
+	if err != nil {
+		t.Fatalf("cannot load assets: %s", err)
+	}
+	html.Assets = assets
+	bs := NewMemoryBoardStore()
+	hub := pubsub.Snapshot(
+		pubsub.NewMemorySnapshotStore(),
+		pubsub.NewMemoryHub(pubsub.CoalesceLatest),
+		time.Minute)
+	app := NewApp(html, &fakeAuth{account: account}, bs, hub, false)
+
+	rt := surf.NewRouter()
+	rt.Use(surf.SecurityHeadersMiddleware(surf.SecurityPolicy{
+		ContentSecurityPolicy: "script-src 'nonce-{nonce}'",
+	}))
+	rt.Mount(`/`, app)
+	rt.Get(`/login`, http.NotFound).Name("login")
+	rt.Get(`/logout`, http.NotFound).Name("logout")
+	html.Routes = rt
+
+	return app, rt, bs
+}
+
+var testAccount = &auth.Account{
+	AccountID:   42,
+	Name:        "Bob",
+	AccessToken: "github-token",
+}
+
+func TestLoginRequired(t *testing.T) {
+	_, rt, _ := newTestApp(t, nil)
+
+	for _, path := range []string{"/", "/b/first-board-identifier"} {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusTemporaryRedirect {
+			t.Errorf("%s: want %d, got %d", path, http.StatusTemporaryRedirect, w.Code)
+		}
+		if loc := w.Header().Get("Location"); loc != "/login" {
+			t.Errorf("%s: want redirect to login, got %q", path, loc)
+		}
+	}
+}
+
+func TestIndexListsUserBoards(t *testing.T) {
+	_, rt, bs := newTestApp(t, testAccount)
+
+	ctx := context.Background()
+	if _, err := bs.CreateBoard(ctx, "first-board-identifier", "My First Board"); err != nil {
+		t.Fatalf("cannot create board: %s", err)
+	}
+	if err := bs.AddUser(ctx, "first-board-identifier", "42"); err != nil {
+		t.Fatalf("cannot add user: %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("want %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "My First Board") {
+		t.Errorf("board name not rendered: %s", body)
+	}
+	if !strings.Contains(body, "/b/first-board-identifier") {
+		t.Errorf("board link not rendered: %s", body)
+	}
+}
+
+func TestCreateBoard(t *testing.T) {
+	_, rt, bs := newTestApp(t, testAccount)
+
+	form := url.Values{"name": {"Sprint 12"}}
+	r := httptest.NewRequest("POST", "/new", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	withCSRF(t, rt, r)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want %d, got %d: %s", http.StatusSeeOther, w.Code, w.Body)
+	}
+
+	boards, err := bs.UserBoards(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 || boards[0].Name != "Sprint 12" {
+		t.Fatalf("unexpected boards: %+v", boards)
+	}
+	if loc := w.Header().Get("Location"); loc != "/b/"+boards[0].ID {
+		t.Fatalf("unexpected redirect: %q", loc)
+	}
+}
+
+func TestCreateBoardWithRandomName(t *testing.T) {
+	_, rt, bs := newTestApp(t, testAccount)
+
+	r := httptest.NewRequest("POST", "/new", nil)
+	withCSRF(t, rt, r)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want %d, got %d: %s", http.StatusSeeOther, w.Code, w.Body)
+	}
+
+	boards, err := bs.UserBoards(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 || boards[0].Name == "" {
+		t.Fatalf("unexpected boards: %+v", boards)
+	}
+}
+
+func TestCreateBoardRequiresCSRFToken(t *testing.T) {
+	_, rt, bs := newTestApp(t, testAccount)
+
+	form := url.Values{"name": {"Sprint 12"}}
+	r := httptest.NewRequest("POST", "/new", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("want %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	boards, err := bs.UserBoards(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 0 {
+		t.Fatalf("board created: %+v", boards)
+	}
+}
+
+// withCSRF sets on the request CSRF cookie and token, as rendered by the
+// index page form.
+func withCSRF(t *testing.T, rt http.Handler, r *http.Request) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	match := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindStringSubmatch(w.Body.String())
+	if match == nil {
+		t.Fatalf("CSRF token not rendered: %s", w.Body)
+	}
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	r.Header.Set(surf.CSRFHeaderName, match[1])
+}
+
+func TestBoardPage(t *testing.T) {
+	_, rt, bs := newTestApp(t, testAccount)
+
+	ctx := context.Background()
+	if _, err := bs.CreateBoard(ctx, "first-board-identifier", "First"); err != nil {
+		t.Fatalf("cannot create board: %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/b/first-board-identifier", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("want %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	nonce := strings.TrimSuffix(strings.TrimPrefix(w.Header().Get("Content-Security-Policy"), "script-src 'nonce-"), "'")
+	if body := w.Body.String(); nonce == "" || !strings.Contains(body, `<script type="text/javascript" nonce="`+nonce+`">`) {
+		t.Errorf("inline script nonce %q not rendered: %s", nonce, body)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"websocketPath":"/ws/first-board-identifier"`) {
+		t.Errorf("websocket address not rendered: %s", body)
+	}
+
+	// visiting the board gives access to it
+	boards, err := bs.UserBoards(ctx, "42")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 {
+		t.Fatalf("unexpected boards: %+v", boards)
+	}
+}
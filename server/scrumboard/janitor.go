@@ -0,0 +1,168 @@
+package scrumboard
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/husio/scrumboard/server/atomicfile"
+	"github.com/husio/scrumboard/server/pubsub"
+	"github.com/husio/scrumboard/server/surf"
+)
+
+// Janitor removes boards that were not used for a long time, together with
+// their snapshots. If archive directory is provided, every board is written
+// there before being removed.
+type Janitor struct {
+	boards     BoardStore
+	snapshots  pubsub.SnapshotStore
+	maxIdle    time.Duration
+	archiveDir string
+	log        surf.Logger
+}
+
+func NewJanitor(
+	boards BoardStore,
+	snapshots pubsub.SnapshotStore,
+	maxIdle time.Duration,
+	archiveDir string,
+) *Janitor {
+	return &Janitor{
+		boards:     boards,
+		snapshots:  snapshots,
+		maxIdle:    maxIdle,
+		archiveDir: archiveDir,
+		log:        surf.NewLogger(os.Stdout, "app", "janitor"),
+	}
+}
+
+// TrackActivity returns snapshot store recording activity of every board which
+// state is stored, so that boards modified only using websocket or event
+// stream connections are not considered inactive.
+func TrackActivity(snapshots pubsub.SnapshotStore, boards BoardStore) pubsub.SnapshotStore {
+	return &activitySnapshotStore{SnapshotStore: snapshots, boards: boards}
+}
+
+type activitySnapshotStore struct {
+	pubsub.SnapshotStore
+	boards BoardStore
+}
+
+func (s *activitySnapshotStore) Store(ctx context.Context, board string, data []byte, version int64) error {
+	if err := s.SnapshotStore.Store(ctx, board, data, version); err != nil {
+		return err
+	}
+	return s.boards.TouchBoard(ctx, board)
+}
+
+// Run removes inactive boards every interval, until context is cancelled.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			n, err := j.Clean(ctx)
+			if err != nil {
+				j.log.Error(ctx, "cannot clean boards",
+					"removed", fmt.Sprint(n),
+					"error", err.Error())
+			} else if n != 0 {
+				j.log.Info(ctx, "inactive boards removed",
+					"removed", fmt.Sprint(n))
+			}
+		}
+	}
+}
+
+// Clean removes all inactive boards and returns their number.
+func (j *Janitor) Clean(ctx context.Context) (int, error) {
+	since := time.Now().Add(-j.maxIdle)
+
+	var removed int
+	for {
+		boards, err := j.boards.InactiveBoards(ctx, since, 100)
+		if err != nil {
+			return removed, fmt.Errorf("cannot list inactive boards: %s", err)
+		}
+
+		var batch int
+		for _, b := range boards {
+			if b.ID == demoBoardID {
+				continue
+			}
+			if err := j.remove(ctx, b); err != nil {
+				return removed, fmt.Errorf("cannot remove %s: %s", b.ID, err)
+			}
+			batch++
+		}
+		if batch == 0 {
+			return removed, nil
+		}
+		removed += batch
+	}
+}
+
+func (j *Janitor) remove(ctx context.Context, b *Board) error {
+	snapshot, err := j.snapshots.Load(ctx, b.ID)
+	switch err {
+	case nil, pubsub.ErrNoSnapshot:
+		// all good
+	default:
+		return fmt.Errorf("cannot load snapshot: %s", err)
+	}
+
+	if j.archiveDir != "" {
+		if err := j.archive(b, snapshot); err != nil {
+			return fmt.Errorf("cannot archive: %s", err)
+		}
+	}
+
+	if snapshot != nil {
+		if err := j.snapshots.Delete(ctx, b.ID); err != nil && err != pubsub.ErrNoSnapshot {
+			return fmt.Errorf("cannot delete snapshot: %s", err)
+		}
+	}
+	if err := j.boards.DeleteBoard(ctx, b.ID); err != nil && err != ErrNotFound {
+		return fmt.Errorf("cannot delete board: %s", err)
+	}
+	return nil
+}
+
+// archive writes board and it's snapshot into a compressed JSON file.
+func (j *Janitor) archive(board *Board, snapshot []byte) error {
+	// board ID might be provided by the client and cannot be trusted
+	name := filepath.Base(board.ID)
+	if name != board.ID || name == "." || name == ".." {
+		return fmt.Errorf("invalid board id: %q", board.ID)
+	}
+
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	content := struct {
+		ID         string    `json:"id"`
+		Name       string    `json:"name"`
+		Snapshot   string    `json:"snapshot"`
+		ArchivedAt time.Time `json:"archivedAt"`
+	}{
+		ID:         board.ID,
+		Name:       board.Name,
+		Snapshot:   string(snapshot),
+		ArchivedAt: time.Now().UTC(),
+	}
+	if err := json.NewEncoder(gz).Encode(content); err != nil {
+		return fmt.Errorf("cannot write: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("cannot write: %s", err)
+	}
+	return atomicfile.WriteFile(filepath.Join(j.archiveDir, name+".json.gz"), b.Bytes(), 0600)
+}
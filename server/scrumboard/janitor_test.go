@@ -0,0 +1,107 @@
+package scrumboard
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/pubsub"
+)
+
+func TestJanitorClean(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	bs := NewMemoryBoardStore().(*localBoardStore)
+	snapshots := pubsub.NewMemorySnapshotStore()
+
+	for _, id := range []string{"old-board-identifier", "new-board-identifier", demoBoardID} {
+		if _, err := bs.CreateBoard(ctx, id, "Board "+id); err != nil {
+			t.Fatalf("cannot create board: %s", err)
+		}
+		if err := snapshots.Store(ctx, id, []byte(`{"rows":4}`), 1); err != nil {
+			t.Fatalf("cannot store snapshot: %s", err)
+		}
+	}
+	bs.data.Boards["old-board-identifier"].LastActivity = time.Now().Add(-2 * time.Hour)
+	bs.data.Boards[demoBoardID].LastActivity = time.Now().Add(-2 * time.Hour)
+
+	j := NewJanitor(bs, snapshots, time.Hour, dir)
+	removed, err := j.Clean(ctx)
+	if err != nil {
+		t.Fatalf("cannot clean: %s", err)
+	}
+	if removed != 1 {
+		t.Fatalf("want one board removed, got %d", removed)
+	}
+
+	if _, err := snapshots.Load(ctx, "old-board-identifier"); err != pubsub.ErrNoSnapshot {
+		t.Errorf("want snapshot removed, got %v", err)
+	}
+	if err := bs.DeleteBoard(ctx, "old-board-identifier"); err != ErrNotFound {
+		t.Errorf("want board removed, got %v", err)
+	}
+	// demo board is never removed
+	for _, id := range []string{"new-board-identifier", demoBoardID} {
+		if _, err := snapshots.Load(ctx, id); err != nil {
+			t.Errorf("cannot load %s snapshot: %s", id, err)
+		}
+	}
+
+	fd, err := os.Open(filepath.Join(dir, "old-board-identifier.json.gz"))
+	if err != nil {
+		t.Fatalf("cannot open archive: %s", err)
+	}
+	defer fd.Close()
+	gz, err := gzip.NewReader(fd)
+	if err != nil {
+		t.Fatalf("cannot read archive: %s", err)
+	}
+	var archived struct {
+		ID       string
+		Name     string
+		Snapshot string
+	}
+	if err := json.NewDecoder(gz).Decode(&archived); err != nil {
+		t.Fatalf("cannot decode archive: %s", err)
+	}
+	if archived.ID != "old-board-identifier" || archived.Name != "Board old-board-identifier" || archived.Snapshot != `{"rows":4}` {
+		t.Errorf("unexpected archive: %+v", archived)
+	}
+
+	// nothing left to remove
+	if removed, err := j.Clean(ctx); err != nil || removed != 0 {
+		t.Fatalf("want nothing removed, got %d, %v", removed, err)
+	}
+}
+
+func TestTrackActivity(t *testing.T) {
+	ctx := context.Background()
+	bs := NewMemoryBoardStore().(*localBoardStore)
+	if _, err := bs.CreateBoard(ctx, "old-board-identifier", "Old"); err != nil {
+		t.Fatalf("cannot create board: %s", err)
+	}
+	bs.data.Boards["old-board-identifier"].LastActivity = time.Now().Add(-2 * time.Hour)
+
+	snapshots := TrackActivity(pubsub.NewMemorySnapshotStore(), bs)
+	if err := snapshots.Store(ctx, "old-board-identifier", []byte(`{"rows":4}`), 1); err != nil {
+		t.Fatalf("cannot store snapshot: %s", err)
+	}
+
+	inactive, err := bs.InactiveBoards(ctx, time.Now().Add(-time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 0 {
+		t.Fatalf("want board state change to be activity, got %+v", inactive)
+	}
+}
@@ -0,0 +1,205 @@
+package scrumboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/husio/scrumboard/server/atomicfile"
+)
+
+// localBoardStore keeps all data in process memory. If path is set, all data
+// is written to a JSON file after every change.
+type localBoardStore struct {
+	mu   sync.Mutex
+	path string
+	data localBoardData
+}
+
+type localBoardData struct {
+	Boards     map[string]*localBoard         `json:"boards"`
+	UserBoards map[string]map[string]struct{} `json:"userBoards"`
+}
+
+type localBoard struct {
+	Name string `json:"name"`
+	// Created is false for boards that were visited, but never created
+	Created      bool      `json:"created"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+var _ BoardStore = (*localBoardStore)(nil)
+
+// NewMemoryBoardStore returns board store that keeps all data in process
+// memory. All data is lost when the process exits.
+func NewMemoryBoardStore() BoardStore {
+	return &localBoardStore{
+		data: localBoardData{
+			Boards:     make(map[string]*localBoard),
+			UserBoards: make(map[string]map[string]struct{}),
+		},
+	}
+}
+
+// NewFileBoardStore returns board store that keeps all data in a single JSON
+// file. File is created if it does not exist. Store is meant for small
+// installations, that cannot run external database.
+func NewFileBoardStore(path string) (BoardStore, error) {
+	s := &localBoardStore{
+		path: path,
+		data: localBoardData{
+			Boards:     make(map[string]*localBoard),
+			UserBoards: make(map[string]map[string]struct{}),
+		},
+	}
+
+	switch raw, err := ioutil.ReadFile(path); {
+	case err == nil:
+		if err := json.Unmarshal(raw, &s.data); err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %s", path, err)
+		}
+	case os.IsNotExist(err):
+		// new database
+	default:
+		return nil, fmt.Errorf("cannot read %s: %s", path, err)
+	}
+	return s, nil
+}
+
+// save writes all data to the file. Must be called with lock acquired.
+func (s *localBoardStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("cannot encode: %s", err)
+	}
+	if err := atomicfile.WriteFile(s.path, raw, 0600); err != nil {
+		return fmt.Errorf("cannot write %s: %s", s.path, err)
+	}
+	return nil
+}
+
+func (s *localBoardStore) CreateBoard(ctx context.Context, id, name string) (*Board, error) {
+	if len(id) < 16 {
+		return nil, fmt.Errorf("id too short: %d", len(id))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Boards[id] = &localBoard{
+		Name:         name,
+		Created:      true,
+		LastActivity: time.Now(),
+	}
+	if err := s.save(); err != nil {
+		return nil, fmt.Errorf("cannot store: %s", err)
+	}
+	board := &Board{
+		ID:   id,
+		Name: name,
+	}
+	return board, nil
+}
+
+func (s *localBoardStore) AddUser(ctx context.Context, boardID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.data.Boards[boardID]
+	if !ok {
+		b = &localBoard{}
+		s.data.Boards[boardID] = b
+	}
+	b.LastActivity = time.Now()
+
+	if _, ok := s.data.UserBoards[userID]; !ok {
+		s.data.UserBoards[userID] = make(map[string]struct{})
+	}
+	s.data.UserBoards[userID][boardID] = struct{}{}
+
+	if err := s.save(); err != nil {
+		return fmt.Errorf("cannot store: %s", err)
+	}
+	return nil
+}
+
+func (s *localBoardStore) TouchBoard(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.data.Boards[id]
+	if !ok {
+		b = &localBoard{}
+		s.data.Boards[id] = b
+	}
+	b.LastActivity = time.Now()
+
+	if err := s.save(); err != nil {
+		return fmt.Errorf("cannot store: %s", err)
+	}
+	return nil
+}
+
+func (s *localBoardStore) UserBoards(ctx context.Context, userID string) ([]*Board, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var boards []*Board
+	for bid := range s.data.UserBoards[userID] {
+		b, ok := s.data.Boards[bid]
+		if !ok || !b.Created || bid == demoBoardID {
+			continue
+		}
+		boards = append(boards, &Board{ID: bid, Name: b.Name})
+	}
+	sort.Slice(boards, func(i, j int) bool { return boards[i].Name < boards[j].Name })
+	if len(boards) > 50 {
+		boards = boards[:50]
+	}
+	return boards, nil
+}
+
+func (s *localBoardStore) InactiveBoards(ctx context.Context, since time.Time, limit int) ([]*Board, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var boards []*Board
+	for bid, b := range s.data.Boards {
+		if b.LastActivity.Before(since) {
+			boards = append(boards, &Board{ID: bid, Name: b.Name})
+		}
+	}
+	sort.Slice(boards, func(i, j int) bool {
+		return s.data.Boards[boards[i].ID].LastActivity.Before(s.data.Boards[boards[j].ID].LastActivity)
+	})
+	if len(boards) > limit {
+		boards = boards[:limit]
+	}
+	return boards, nil
+}
+
+func (s *localBoardStore) DeleteBoard(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.data.Boards[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.data.Boards, id)
+	for _, boards := range s.data.UserBoards {
+		delete(boards, id)
+	}
+
+	if err := s.save(); err != nil {
+		return fmt.Errorf("cannot delete: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package scrumboard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/husio/scrumboard/server/pubsub"
+)
+
+// CopyRedisToPostgres copies all boards, their users and snapshots from redis
+// database to PostgreSQL database. Already copied data is updated, so it is
+// safe to run the copy more than once. Number of copied boards is returned.
+//
+// Snapshots are copied from one snapshot store to another, so that compression
+// and key format is handled by the stores.
+func CopyRedisToPostgres(
+	ctx context.Context,
+	rp *redis.Pool,
+	db *sql.DB,
+	from pubsub.SnapshotStore,
+	to pubsub.SnapshotStore,
+) (int, error) {
+	rc := rp.Get()
+	boards, err := readRedisBoards(rc)
+	rc.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	var copied int
+	for bid, board := range boards {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO boards (id, name, last_activity) VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET
+				name = COALESCE(EXCLUDED.name, boards.name),
+				last_activity = GREATEST(EXCLUDED.last_activity, boards.last_activity)
+		`, bid, board.name, board.lastActivity); err != nil {
+			return copied, fmt.Errorf("cannot store board %s: %s", bid, err)
+		}
+		for _, userID := range board.users {
+			if _, err := db.ExecContext(ctx, `
+				INSERT INTO user_boards (user_id, board_id) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING
+			`, userID, bid); err != nil {
+				return copied, fmt.Errorf("cannot store board %s user: %s", bid, err)
+			}
+		}
+
+		switch snapshot, err := from.Load(ctx, bid); err {
+		case nil:
+			// copied state has the lowest version, so that state
+			// already modified in PostgreSQL is kept
+			switch err := to.Store(ctx, bid, snapshot, 0); err {
+			case nil, pubsub.ErrStaleSnapshot:
+			default:
+				return copied, fmt.Errorf("cannot store board %s snapshot: %s", bid, err)
+			}
+		case pubsub.ErrNoSnapshot:
+			// nothing to copy
+		default:
+			return copied, fmt.Errorf("cannot load board %s snapshot: %s", bid, err)
+		}
+
+		copied++
+	}
+	return copied, nil
+}
+
+// redisBoard is board data read from redis database.
+type redisBoard struct {
+	// name is null for boards that were never created
+	name         sql.NullString
+	lastActivity time.Time
+	users        []string
+}
+
+// readRedisBoards returns all boards found in redis database, including those
+// known only from activity, user boards or snapshots.
+func readRedisBoards(rc redis.Conn) (map[string]*redisBoard, error) {
+	ids, err := scanBoardIDs(rc)
+	if err != nil {
+		return nil, err
+	}
+	boards := make(map[string]*redisBoard, len(ids))
+	board := func(id string) *redisBoard {
+		b, ok := boards[id]
+		if !ok {
+			b = &redisBoard{}
+			boards[id] = b
+		}
+		return b
+	}
+	for id := range ids {
+		board(id)
+	}
+
+	values, err := redis.Strings(rc.Do("ZRANGE", "board:activity", 0, -1, "WITHSCORES"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get board activity: %s", err)
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		unix, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid board %s activity: %s", values[i], err)
+		}
+		board(values[i]).lastActivity = time.Unix(int64(unix), 0)
+	}
+
+	keys, err := scanKeys(rc, "userboards:*")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		userID := key[len("userboards:"):]
+		bids, err := redis.Strings(rc.Do("SMEMBERS", key))
+		if err != nil {
+			return nil, fmt.Errorf("cannot get user %s boards: %s", userID, err)
+		}
+		for _, bid := range bids {
+			b := board(bid)
+			b.users = append(b.users, userID)
+		}
+	}
+
+	now := time.Now()
+	for bid, b := range boards {
+		v, err := redis.StringMap(rc.Do("HGETALL", "board:"+bid))
+		if err != nil {
+			return nil, fmt.Errorf("cannot get board %s: %s", bid, err)
+		}
+		if n, ok := v["name"]; ok {
+			b.name = sql.NullString{String: n, Valid: true}
+		}
+		if b.lastActivity.IsZero() {
+			b.lastActivity = now
+		}
+	}
+	return boards, nil
+}
+
+// scanKeys returns all keys matching given pattern.
+func scanKeys(rc redis.Conn, pattern string) ([]string, error) {
+	var keys []string
+	cursor := 0
+	for {
+		v, err := redis.Values(rc.Do("SCAN", cursor, "MATCH", pattern, "COUNT", 1000))
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan %s: %s", pattern, err)
+		}
+		var chunk []string
+		if _, err := redis.Scan(v, &cursor, &chunk); err != nil {
+			return nil, fmt.Errorf("cannot scan %s: %s", pattern, err)
+		}
+		keys = append(keys, chunk...)
+		if cursor == 0 {
+			return keys, nil
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package scrumboard
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/husio/scrumboard/server/migrate"
+	"github.com/husio/scrumboard/server/pgtest"
+	"github.com/husio/scrumboard/server/pubsub"
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestReadRedisBoards(t *testing.T) {
+	rp := redistest.NewPool()
+	fillRedis(t, rp.Get())
+
+	rc := rp.Get()
+	defer rc.Close()
+	boards, err := readRedisBoards(rc)
+	if err != nil {
+		t.Fatalf("cannot read boards: %s", err)
+	}
+
+	var ids []string
+	for id := range boards {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	want := []string{"first-board-identifier", "snapshot-board-identifier", "visited-board-identifier"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("want %q boards, got %q", want, ids)
+	}
+
+	first := boards["first-board-identifier"]
+	if !first.name.Valid || first.name.String != "First" {
+		t.Fatalf("unexpected name: %+v", first.name)
+	}
+	if !first.lastActivity.Equal(time.Unix(1500000000, 0)) {
+		t.Fatalf("unexpected activity: %s", first.lastActivity)
+	}
+	sort.Strings(first.users)
+	if !reflect.DeepEqual(first.users, []string{"1", "2"}) {
+		t.Fatalf("unexpected users: %q", first.users)
+	}
+
+	// boards that were never created have no name, but are copied to
+	// keep user boards and snapshots
+	visited := boards["visited-board-identifier"]
+	if visited.name.Valid || !reflect.DeepEqual(visited.users, []string{"1"}) {
+		t.Fatalf("unexpected board: %+v", visited)
+	}
+	if snap := boards["snapshot-board-identifier"]; snap.name.Valid || snap.lastActivity.IsZero() {
+		t.Fatalf("unexpected board: %+v", snap)
+	}
+}
+
+func TestCopyRedisToPostgres(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.Open(t)
+	for component, migrations := range map[string][]migrate.Migration{
+		"scrumboard": PostgresMigrations,
+		"pubsub":     pubsub.PostgresMigrations,
+	} {
+		if err := migrate.Run(ctx, db, component, migrations); err != nil {
+			t.Fatalf("cannot migrate %s: %s", component, err)
+		}
+	}
+
+	rp := redistest.NewPool()
+	fillRedis(t, rp.Get())
+	from := pubsub.NewRedisSnapshotStore(rp, "board:snapshot:")
+	to := pubsub.NewPostgresSnapshotStore(db)
+
+	// copy can be repeated
+	for i := 0; i < 2; i++ {
+		n, err := CopyRedisToPostgres(ctx, rp, db, from, to)
+		if err != nil {
+			t.Fatalf("cannot copy: %s", err)
+		}
+		if n != 3 {
+			t.Fatalf("want 3 boards copied, got %d", n)
+		}
+	}
+
+	bs := NewPostgresBoardStore(db)
+	boards, err := bs.UserBoards(ctx, "2")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 || boards[0].ID != "first-board-identifier" || boards[0].Name != "First" {
+		t.Fatalf("unexpected user boards: %+v", boards)
+	}
+
+	// board activity is copied, boards without activity are active now
+	inactive, err := bs.InactiveBoards(ctx, time.Unix(1500000001, 0), 10)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 1 || inactive[0].ID != "first-board-identifier" {
+		t.Fatalf("unexpected inactive boards: %+v", inactive)
+	}
+
+	state, err := to.Load(ctx, "snapshot-board-identifier")
+	if err != nil {
+		t.Fatalf("snapshot not copied: %s", err)
+	}
+	if string(state) != `{"cards":[],"rows":4}` {
+		t.Fatalf("unexpected snapshot: %s", state)
+	}
+}
+
+// fillRedis writes boards using the redis key layout and closes the
+// connection.
+func fillRedis(t *testing.T, rc redis.Conn) {
+	t.Helper()
+	defer rc.Close()
+
+	rc.Send("HMSET", "board:first-board-identifier", "name", "First")
+	rc.Send("ZADD", "board:activity", 1500000000, "first-board-identifier")
+	rc.Send("SADD", "userboards:1", "first-board-identifier", "visited-board-identifier")
+	rc.Send("SADD", "userboards:2", "first-board-identifier")
+	rc.Send("SET", "board:snapshot:snapshot-board-identifier", `{"cards":[],"rows":4}`)
+	if _, err := rc.Do(""); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+}
@@ -0,0 +1,162 @@
+package scrumboard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/husio/scrumboard/server/migrate"
+)
+
+// PostgresMigrations creates schema required by PostgreSQL board store.
+var PostgresMigrations = []migrate.Migration{
+	{
+		Name: "0001_boards",
+		SQL: `
+			CREATE TABLE boards (
+				id            TEXT PRIMARY KEY,
+				-- name is null for boards that were visited,
+				-- but never created
+				name          TEXT,
+				last_activity TIMESTAMPTZ NOT NULL DEFAULT now()
+			);
+
+			CREATE INDEX boards_last_activity_idx ON boards (last_activity);
+
+			CREATE TABLE user_boards (
+				user_id  TEXT NOT NULL,
+				board_id TEXT NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+				PRIMARY KEY (user_id, board_id)
+			);
+		`,
+	},
+}
+
+type pgBoardStore struct {
+	db *sql.DB
+}
+
+var _ BoardStore = (*pgBoardStore)(nil)
+
+// NewPostgresBoardStore returns board store using PostgreSQL database.
+// Database schema must be created using PostgresMigrations.
+func NewPostgresBoardStore(db *sql.DB) BoardStore {
+	return &pgBoardStore{db: db}
+}
+
+func (s *pgBoardStore) CreateBoard(ctx context.Context, id, name string) (*Board, error) {
+	if len(id) < 16 {
+		return nil, fmt.Errorf("id too short: %d", len(id))
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO boards (id, name, last_activity) VALUES ($1, $2, now())
+		ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name
+	`, id, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot store: %s", err)
+	}
+	board := &Board{
+		ID:   id,
+		Name: name,
+	}
+	return board, nil
+}
+
+func (s *pgBoardStore) AddUser(ctx context.Context, boardID, userID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot start transaction: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO boards (id, last_activity) VALUES ($1, now())
+		ON CONFLICT (id) DO UPDATE SET last_activity = now()
+	`, boardID); err != nil {
+		return fmt.Errorf("cannot store activity: %s", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_boards (user_id, board_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, boardID); err != nil {
+		return fmt.Errorf("cannot store: %s", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit: %s", err)
+	}
+	return nil
+}
+
+func (s *pgBoardStore) TouchBoard(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO boards (id, last_activity) VALUES ($1, now())
+		ON CONFLICT (id) DO UPDATE SET last_activity = now()
+	`, id); err != nil {
+		return fmt.Errorf("cannot store activity: %s", err)
+	}
+	return nil
+}
+
+func (s *pgBoardStore) UserBoards(ctx context.Context, userID string) ([]*Board, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.id, b.name
+		FROM user_boards ub INNER JOIN boards b ON ub.board_id = b.id
+		WHERE ub.user_id = $1 AND b.name IS NOT NULL AND b.id <> $2
+		ORDER BY b.name
+		LIMIT 50
+	`, userID, demoBoardID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get user boards: %s", err)
+	}
+	defer rows.Close()
+
+	var boards []*Board
+	for rows.Next() {
+		var board Board
+		if err := rows.Scan(&board.ID, &board.Name); err != nil {
+			return boards, fmt.Errorf("cannot scan board: %s", err)
+		}
+		boards = append(boards, &board)
+	}
+	return boards, rows.Err()
+}
+
+func (s *pgBoardStore) InactiveBoards(ctx context.Context, since time.Time, limit int) ([]*Board, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, COALESCE(name, '')
+		FROM boards
+		WHERE last_activity < $1
+		ORDER BY last_activity
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get inactive boards: %s", err)
+	}
+	defer rows.Close()
+
+	var boards []*Board
+	for rows.Next() {
+		var board Board
+		if err := rows.Scan(&board.ID, &board.Name); err != nil {
+			return boards, fmt.Errorf("cannot scan board: %s", err)
+		}
+		boards = append(boards, &board)
+	}
+	return boards, rows.Err()
+}
+
+func (s *pgBoardStore) DeleteBoard(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM boards WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("cannot delete: %s", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("cannot delete: %s", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package scrumboard
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/husio/scrumboard/server/metrics"
+	"github.com/husio/scrumboard/server/surf"
+)
+
+var pollClientGauge = metrics.NewGauge("scrumboard_poll_clients",
+	"Number of connected long-polling clients.")
+
+const (
+	// defaultPollTimeout is the longest time single poll request is
+	// waiting for messages
+	defaultPollTimeout = 25 * time.Second
+	// defaultPollExpire is the time after which client that stopped
+	// polling is unsubscribed
+	defaultPollExpire = time.Minute
+)
+
+// pollClient is the state of long-polling client, kept between requests.
+// Messages are buffered by the subscription channel, so the hub overflow
+// policy applies to clients that are not polling often enough.
+type pollClient struct {
+	recv chan []byte
+	// mu allows only single poll request at a time
+	mu sync.Mutex
+	// touch is notified by every poll request, to delay expiration
+	touch chan struct{}
+}
+
+// handlePoll is long-polling alternative to the event stream, for clients
+// behind proxies that buffer the responses. Request without "client"
+// parameter subscribes to the board and returns client ID, that must be
+// provided with every following poll and update. Poll request returns as soon
+// as there are messages to deliver, or after a timeout with no messages.
+//
+// Updates are sent the same way as for the event stream, using
+// handleEventsUpdate.
+func (app *ScrumBoardApp) handlePoll(w http.ResponseWriter, r *http.Request) {
+	boardID := surf.PathArg(r, 0)
+
+	if ln := len(boardID); ln < 20 || ln > 60 {
+		surf.JSONErr(w, http.StatusBadRequest, "invalid board id")
+		return
+	}
+
+	if !app.connect() {
+		surf.JSONErr(w, http.StatusServiceUnavailable, "server restarting")
+		return
+	}
+	defer app.clients.Done()
+
+	clientID := r.URL.Query().Get("client")
+	if clientID == "" {
+		clientID, ok := app.subscribePoll(boardID)
+		if !ok {
+			surf.JSONErr(w, http.StatusServiceUnavailable, "server restarting")
+			return
+		}
+		surf.JSONResp(w, http.StatusOK, pollResponse{
+			Client:   clientID,
+			Messages: []string{},
+		})
+		return
+	}
+
+	stream, ok := app.streams.get(clientID, boardID)
+	if !ok || stream.poll == nil {
+		// client expired and must subscribe again
+		surf.JSONErr(w, http.StatusGone, "polling client not found")
+		return
+	}
+
+	poll := stream.poll
+	poll.mu.Lock()
+	defer poll.mu.Unlock()
+	poll.keepalive()
+	defer poll.keepalive()
+
+	timeout := time.NewTimer(app.pollTimeout)
+	defer timeout.Stop()
+
+	resp := pollResponse{Client: clientID, Messages: []string{}}
+	select {
+	case <-r.Context().Done():
+		return
+	case <-app.closing:
+		surf.JSONErr(w, http.StatusServiceUnavailable, "server restarting")
+		return
+	case <-stream.sub.Done():
+		// client was too slow and missed some of the updates, it
+		// must subscribe again to receive the current board state
+		surf.JSONErr(w, http.StatusGone, "polling client disconnected")
+		return
+	case <-timeout.C:
+	case msg := <-poll.recv:
+		resp.Messages = append(resp.Messages, string(msg))
+		// return all messages that are already waiting
+	drain:
+		for {
+			select {
+			case msg := <-poll.recv:
+				resp.Messages = append(resp.Messages, string(msg))
+			default:
+				break drain
+			}
+		}
+	}
+	surf.JSONResp(w, http.StatusOK, resp)
+}
+
+type pollResponse struct {
+	Client   string   `json:"client"`
+	Messages []string `json:"messages"`
+}
+
+func (p *pollClient) keepalive() {
+	select {
+	case p.touch <- struct{}{}:
+	default:
+	}
+}
+
+// subscribePoll creates new polling client subscribed to given board and
+// returns its ID. Client is unsubscribed once it stops polling.
+func (app *ScrumBoardApp) subscribePoll(boardID string) (string, bool) {
+	// subscription outlives the request, so it is tracked as a separate
+	// client
+	if !app.connect() {
+		return "", false
+	}
+
+	poll := &pollClient{
+		recv:  make(chan []byte, 4),
+		touch: make(chan struct{}, 1),
+	}
+	stream := eventStream{
+		board: boardID,
+		sub:   app.hub.Subscribe(boardID, poll.recv),
+		poll:  poll,
+	}
+	clientID := genBoardID()
+	app.streams.add(clientID, stream)
+
+	go app.expirePoll(clientID, stream)
+	return clientID, true
+}
+
+// expirePoll unsubscribes polling client when it was not polling for too
+// long, or when the server is shutting down.
+func (app *ScrumBoardApp) expirePoll(clientID string, stream eventStream) {
+	defer app.clients.Done()
+
+	pollClientGauge.Inc()
+	defer pollClientGauge.Dec()
+
+	defer stream.sub.Close()
+	defer app.streams.del(clientID)
+
+	expire := time.NewTimer(app.pollExpire)
+	defer expire.Stop()
+	for {
+		select {
+		case <-stream.poll.touch:
+			if !expire.Stop() {
+				<-expire.C
+			}
+			expire.Reset(app.pollExpire)
+		case <-expire.C:
+			return
+		case <-app.closing:
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package scrumboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/husio/scrumboard/server/pubsub"
+)
+
+func poll(t *testing.T, srv *httptest.Server, boardID, clientID string) (int, pollResponse) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/poll/" + boardID + "?client=" + clientID)
+	if err != nil {
+		t.Fatalf("cannot poll: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var content pollResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&content); err != nil {
+			t.Fatalf("cannot decode response: %s", err)
+		}
+	}
+	return resp.StatusCode, content
+}
+
+func TestPollBroadcast(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	app.pollTimeout = 100 * time.Millisecond
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	code, resp := poll(t, srv, testBoardID, "")
+	if code != http.StatusOK || resp.Client == "" {
+		t.Fatalf("cannot subscribe: %d %+v", code, resp)
+	}
+	clientID := resp.Client
+
+	// current board state is delivered with the first poll
+	if _, resp := poll(t, srv, testBoardID, clientID); len(resp.Messages) != 1 || resp.Messages[0] != string(pubsub.EmptyState) {
+		t.Fatalf("unexpected initial state: %+v", resp)
+	}
+	// poll returns with no messages after a timeout
+	if code, resp := poll(t, srv, testBoardID, clientID); code != http.StatusOK || len(resp.Messages) != 0 {
+		t.Fatalf("unexpected response: %d %+v", code, resp)
+	}
+
+	ws := dialBoard(t, srv, testBoardID)
+	defer ws.Close()
+	readMessage(t, ws)
+
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(`{"rows":1}`)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	if _, resp := poll(t, srv, testBoardID, clientID); len(resp.Messages) != 1 || resp.Messages[0] != `{"rows":1}` {
+		t.Fatalf("unexpected messages: %+v", resp)
+	}
+
+	// updates are sent the same way as by the event stream clients
+	if code := postUpdate(t, srv, testBoardID, clientID, `{"rows":2}`); code != http.StatusNoContent {
+		t.Fatalf("unexpected response: %d", code)
+	}
+	if msg := readMessage(t, ws); msg != `{"rows":2}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+	// message is not echoed back to the author
+	if _, resp := poll(t, srv, testBoardID, clientID); len(resp.Messages) != 0 {
+		t.Fatalf("author received own message: %+v", resp)
+	}
+}
+
+func TestPollExpire(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	app.pollTimeout = 50 * time.Millisecond
+	app.pollExpire = 200 * time.Millisecond
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	_, resp := poll(t, srv, testBoardID, "")
+	clientID := resp.Client
+
+	// polling keeps the client subscribed
+	for i := 0; i < 6; i++ {
+		if code, _ := poll(t, srv, testBoardID, clientID); code != http.StatusOK {
+			t.Fatalf("unexpected response: %d", code)
+		}
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if code, _ := poll(t, srv, testBoardID, clientID); code != http.StatusGone {
+		t.Fatalf("want 410, got %d", code)
+	}
+	if code := postUpdate(t, srv, testBoardID, clientID, "{}"); code != http.StatusGone {
+		t.Fatalf("want 410, got %d", code)
+	}
+	if code, _ := poll(t, srv, testBoardID, "unknown"); code != http.StatusGone {
+		t.Fatalf("want 410, got %d", code)
+	}
+}
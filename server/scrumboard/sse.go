@@ -0,0 +1,203 @@
+package scrumboard
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/husio/scrumboard/server/metrics"
+	"github.com/husio/scrumboard/server/pubsub"
+	"github.com/husio/scrumboard/server/surf"
+)
+
+var eventStreamGauge = metrics.NewGauge("scrumboard_event_streams",
+	"Number of open Server-Sent Events streams.")
+
+// handleEvents is Server-Sent Events alternative to websocket connection, for
+// clients that cannot use websockets (ie. because of a proxy). Board state is
+// streamed to the client, while updates must be sent using handleEventsUpdate.
+//
+// First event sent to the client is "client" event, containing stream ID that
+// must be provided with every update, so that hub does not echo it back.
+func (app *ScrumBoardApp) handleEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardID := surf.PathArg(r, 0)
+
+	if ln := len(boardID); ln < 20 || ln > 60 {
+		surf.JSONErr(w, http.StatusBadRequest, "invalid board id")
+		return
+	}
+
+	if !app.connect() {
+		surf.JSONErr(w, http.StatusServiceUnavailable, "server restarting")
+		return
+	}
+	defer app.clients.Done()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		app.log.Error(ctx, "response writer does not support flushing")
+		surf.JSONErr(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	// stream is kept open for as long as the client is connected, so server
+	// write timeout must not apply
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		app.log.Error(ctx, "cannot reset write deadline", "error", err.Error())
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// disable response buffering when running behind nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	recv := make(chan []byte, 4)
+	sub := app.hub.Subscribe(boardID, recv)
+	defer sub.Close()
+
+	eventStreamGauge.Inc()
+	defer eventStreamGauge.Dec()
+
+	streamID := genBoardID()
+	app.streams.add(streamID, eventStream{board: boardID, sub: sub})
+	defer app.streams.del(streamID)
+
+	if err := writeEvent(w, "client", []byte(streamID)); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	// comment lines are ignored by the client, but keep the connection
+	// from being closed by proxies because of inactivity
+	keepalive := time.NewTicker(30 * time.Second)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-app.closing:
+			// server is shutting down, client should reconnect
+			writeEvent(w, "restart", []byte("server restarting, reconnect"))
+			flusher.Flush()
+			return
+		case <-sub.Done():
+			// client was too slow and missed some of the updates,
+			// it must reconnect to receive the current board state
+			writeEvent(w, "resync", nil)
+			flusher.Flush()
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case msg := <-recv:
+			if err := writeEvent(w, "message", msg); err != nil {
+				app.log.Info(ctx, "cannot write to client",
+					"board", boardID,
+					"error", err.Error())
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes single Server-Sent Event. Data is split into multiple
+// data fields if necessary, because event field value cannot contain new
+// line.
+func writeEvent(w http.ResponseWriter, name string, data []byte) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "event: %s\n", name)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		b.WriteString("data: ")
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// handleEventsUpdate broadcast request body to all board subscribers, except
+// the event stream or polling client identified by "client" parameter.
+func (app *ScrumBoardApp) handleEventsUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	boardID := surf.PathArg(r, 0)
+
+	stream, ok := app.streams.get(r.URL.Query().Get("client"), boardID)
+	if !ok {
+		// client should reconnect the stream and try again
+		surf.JSONErr(w, http.StatusGone, "event stream not found")
+		return
+	}
+
+	msg, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxUpdateSize))
+	if err != nil {
+		surf.JSONErr(w, http.StatusRequestEntityTooLarge, "cannot read message")
+		return
+	}
+	if err := stream.sub.Broadcast(msg); err != nil {
+		app.log.Error(ctx, "cannot broadcast",
+			"board", boardID,
+			"error", err.Error())
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(errorMessage(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const maxUpdateSize = 1 << 20
+
+// eventStreams keeps track of all subscriptions of the open event streams and
+// polling clients, so that updates sent in separate requests can be
+// broadcasted in their name.
+type eventStreams struct {
+	mu      sync.Mutex
+	streams map[string]eventStream
+}
+
+type eventStream struct {
+	board string
+	sub   pubsub.Subscription
+	// poll is set only for long-polling clients
+	poll *pollClient
+}
+
+func newEventStreams() *eventStreams {
+	return &eventStreams{
+		streams: make(map[string]eventStream),
+	}
+}
+
+func (es *eventStreams) add(id string, s eventStream) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.streams[id] = s
+}
+
+func (es *eventStreams) del(id string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	delete(es.streams, id)
+}
+
+// get returns the stream with given ID, only if that stream is subscribed to
+// given board.
+func (es *eventStreams) get(id, board string) (eventStream, bool) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	s, ok := es.streams[id]
+	if !ok || s.board != board {
+		return eventStream{}, false
+	}
+	return s, true
+}
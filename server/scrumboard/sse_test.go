@@ -0,0 +1,212 @@
+package scrumboard
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/husio/scrumboard/server/pubsub"
+)
+
+type event struct {
+	name string
+	data string
+}
+
+// openEvents connects to the board event stream and returns received events.
+func openEvents(t *testing.T, srv *httptest.Server, boardID string) (<-chan event, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequest("GET", srv.URL+"/events/"+boardID, nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		t.Fatalf("cannot connect: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		cancel()
+		t.Fatalf("unexpected response: %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		cancel()
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+
+	events := make(chan event, 16)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		var ev event
+		rd := bufio.NewReader(resp.Body)
+		for {
+			line, err := rd.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch line = strings.TrimSuffix(line, "\n"); {
+			case line == "":
+				events <- ev
+				ev = event{}
+			case strings.HasPrefix(line, "event: "):
+				ev.name = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				if ev.data != "" {
+					ev.data += "\n"
+				}
+				ev.data += line[len("data: "):]
+			}
+		}
+	}()
+	return events, cancel
+}
+
+func readEvent(t *testing.T, events <-chan event) event {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("event stream closed")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+		return event{}
+	}
+}
+
+func postUpdate(t *testing.T, srv *httptest.Server, boardID, clientID, msg string) int {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/events/"+boardID+"?client="+clientID,
+		"application/json", strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("cannot post: %s", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestEventsBroadcast(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	alice, closeAlice := openEvents(t, srv, testBoardID)
+	defer closeAlice()
+	bob, closeBob := openEvents(t, srv, testBoardID)
+	defer closeBob()
+
+	// stream ID is sent first, followed by the current board state
+	ev := readEvent(t, alice)
+	if ev.name != "client" || ev.data == "" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	aliceID := ev.data
+	if ev := readEvent(t, alice); ev.name != "message" || ev.data != string(pubsub.EmptyState) {
+		t.Fatalf("unexpected initial state: %+v", ev)
+	}
+	if ev := readEvent(t, bob); ev.name != "client" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	readEvent(t, bob)
+
+	state := "{\"cards\":[],\n\"rows\":5}"
+	if code := postUpdate(t, srv, testBoardID, aliceID, state); code != http.StatusNoContent {
+		t.Fatalf("unexpected response: %d", code)
+	}
+	// multi line message is split into several data fields
+	if ev := readEvent(t, bob); ev.name != "message" || ev.data != state {
+		t.Fatalf("unexpected state: %+v", ev)
+	}
+
+	// message is not echoed back to the author
+	select {
+	case ev := <-alice:
+		t.Fatalf("author received own message: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEventsUpdateUnknownStream(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	events, cancel := openEvents(t, srv, testBoardID)
+	clientID := readEvent(t, events).data
+
+	// stream ID is valid only for the board it is subscribed to
+	if code := postUpdate(t, srv, "other-test-board-identifier", clientID, "{}"); code != http.StatusGone {
+		t.Fatalf("want 410, got %d", code)
+	}
+	if code := postUpdate(t, srv, testBoardID, "unknown", "{}"); code != http.StatusGone {
+		t.Fatalf("want 410, got %d", code)
+	}
+
+	// closed stream cannot be used to send updates
+	cancel()
+	for range events {
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for postUpdate(t, srv, testBoardID, clientID, "{}") != http.StatusGone {
+		if time.Now().After(deadline) {
+			t.Fatal("closed stream is still accepting updates")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEventsAndWebsocketInteroperate(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	events, cancel := openEvents(t, srv, testBoardID)
+	defer cancel()
+	clientID := readEvent(t, events).data
+	readEvent(t, events)
+
+	ws := dialBoard(t, srv, testBoardID)
+	defer ws.Close()
+	readMessage(t, ws)
+
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(`{"rows":1}`)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	if ev := readEvent(t, events); ev.name != "message" || ev.data != `{"rows":1}` {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	if code := postUpdate(t, srv, testBoardID, clientID, `{"rows":2}`); code != http.StatusNoContent {
+		t.Fatalf("unexpected response: %d", code)
+	}
+	if msg := readMessage(t, ws); msg != `{"rows":2}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestEventsShutdown(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	events, cancel := openEvents(t, srv, testBoardID)
+	defer cancel()
+	readEvent(t, events)
+	readEvent(t, events)
+
+	ctx, done := context.WithTimeout(context.Background(), 2*time.Second)
+	defer done()
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatalf("cannot shutdown: %s", err)
+	}
+	if ev := readEvent(t, events); ev.name != "restart" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+}
@@ -2,7 +2,12 @@ package scrumboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/garyburd/redigo/redis"
 )
@@ -14,12 +19,37 @@ type Board struct {
 
 type BoardStore interface {
 	CreateBoard(ctx context.Context, id, name string) (*Board, error)
+
+	// AddUser grants user access to the board. Every call is considered
+	// board activity.
 	AddUser(ctx context.Context, boardID, userID string) error
 	UserBoards(ctx context.Context, userID string) ([]*Board, error)
+
+	// TouchBoard records board activity, ie. change of the board state.
+	TouchBoard(ctx context.Context, id string) error
+
+	// InactiveBoards returns up to limit boards with no activity since
+	// given time.
+	InactiveBoards(ctx context.Context, since time.Time, limit int) ([]*Board, error)
+
+	// DeleteBoard removes board. ErrNotFound is returned if board does not
+	// exist.
+	DeleteBoard(ctx context.Context, id string) error
 }
 
+var ErrNotFound = errors.New("not found")
+
+// demoBoardID is the test board that is hardcoded in the index file and
+// visible to all users.
+const demoBoardID = "b685c036049f6c2f35cc1b03af6815b352b8557e"
+
 type redisBoardStore struct {
 	rp *redis.Pool
+
+	// backfilled is true once activity of all boards created before the
+	// activity was tracked is recorded
+	mu         sync.Mutex
+	backfilled bool
 }
 
 var _ BoardStore = (*redisBoardStore)(nil)
@@ -56,6 +86,19 @@ func (s *redisBoardStore) AddUser(ctx context.Context, boardID, userID string) e
 	if _, err := rc.Do("SADD", "userboards:"+userID, boardID); err != nil {
 		return fmt.Errorf("cannot store: %s", err)
 	}
+	if _, err := rc.Do("ZADD", "board:activity", time.Now().Unix(), boardID); err != nil {
+		return fmt.Errorf("cannot store activity: %s", err)
+	}
+	return nil
+}
+
+func (s *redisBoardStore) TouchBoard(ctx context.Context, id string) error {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	if _, err := rc.Do("ZADD", "board:activity", time.Now().Unix(), id); err != nil {
+		return fmt.Errorf("cannot store activity: %s", err)
+	}
 	return nil
 }
 
@@ -75,8 +118,7 @@ func (s *redisBoardStore) UserBoards(ctx context.Context, userID string) ([]*Boa
 
 	var boards []*Board
 	for _, bid := range bids {
-		if bid == "b685c036049f6c2f35cc1b03af6815b352b8557e" {
-			// test board that is hardcoded in the index file and visible to all users
+		if bid == demoBoardID {
 			continue
 		}
 
@@ -84,6 +126,13 @@ func (s *redisBoardStore) UserBoards(ctx context.Context, userID string) ([]*Boa
 		if err != nil {
 			return boards, fmt.Errorf("cannot get board %s: %s", bid, err)
 		}
+		if len(v) == 0 {
+			// board was deleted, remove reference as well
+			if _, err := rc.Do("SREM", "userboards:"+userID, bid); err != nil {
+				return boards, fmt.Errorf("cannot remove board %s: %s", bid, err)
+			}
+			continue
+		}
 		var board Board
 		if err := redis.ScanStruct(v, &board); err != nil {
 			return boards, fmt.Errorf("cannot scan board %s: %s", bid, err)
@@ -93,3 +142,96 @@ func (s *redisBoardStore) UserBoards(ctx context.Context, userID string) ([]*Boa
 
 	return boards, nil
 }
+
+func (s *redisBoardStore) InactiveBoards(ctx context.Context, since time.Time, limit int) ([]*Board, error) {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	if err := s.backfill(rc); err != nil {
+		return nil, err
+	}
+
+	bids, err := redis.Strings(rc.Do("ZRANGEBYSCORE", "board:activity",
+		"-inf", "("+strconv.FormatInt(since.Unix(), 10),
+		"LIMIT", 0, limit))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get inactive boards: %s", err)
+	}
+
+	boards := make([]*Board, 0, len(bids))
+	for _, bid := range bids {
+		v, err := redis.Values(rc.Do("HGETALL", "board:"+bid))
+		if err != nil {
+			return boards, fmt.Errorf("cannot get board %s: %s", bid, err)
+		}
+		// board might be known only by it's ID
+		board := Board{ID: bid}
+		if err := redis.ScanStruct(v, &board); err != nil {
+			return boards, fmt.Errorf("cannot scan board %s: %s", bid, err)
+		}
+		boards = append(boards, &board)
+	}
+	return boards, nil
+}
+
+// backfill records activity of boards created before the activity was
+// tracked. Their last activity is not known, so the current time is used. It
+// is done only once per process.
+func (s *redisBoardStore) backfill(rc redis.Conn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backfilled {
+		return nil
+	}
+
+	bids, err := scanBoardIDs(rc)
+	if err != nil {
+		return fmt.Errorf("cannot backfill activity: %s", err)
+	}
+	now := time.Now().Unix()
+	for bid := range bids {
+		rc.Send("ZADD", "board:activity", "NX", now, bid)
+	}
+	if _, err := rc.Do(""); err != nil {
+		return fmt.Errorf("cannot backfill activity: %s", err)
+	}
+	s.backfilled = true
+	return nil
+}
+
+// scanBoardIDs returns IDs of all boards that have a description or a
+// snapshot stored.
+func scanBoardIDs(rc redis.Conn) (map[string]bool, error) {
+	keys, err := scanKeys(rc, "board:*")
+	if err != nil {
+		return nil, err
+	}
+	bids := make(map[string]bool)
+	for _, key := range keys {
+		chunks := strings.Split(key, ":")
+		switch {
+		case len(chunks) == 2 && chunks[1] != "activity":
+			bids[chunks[1]] = true
+		case len(chunks) == 3 && chunks[1] == "snapshot":
+			bids[chunks[2]] = true
+		}
+	}
+	return bids, nil
+}
+
+func (s *redisBoardStore) DeleteBoard(ctx context.Context, id string) error {
+	rc := s.rp.Get()
+	defer rc.Close()
+
+	// user board references are removed when listing user boards
+	rc.Send("DEL", "board:"+id)
+	rc.Send("ZREM", "board:activity", id)
+	res, err := redis.Ints(rc.Do(""))
+	if err != nil {
+		return fmt.Errorf("cannot delete: %s", err)
+	}
+	if res[0] == 0 && res[1] == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package scrumboard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/husio/scrumboard/server/migrate"
+	"github.com/husio/scrumboard/server/pgtest"
+	"github.com/husio/scrumboard/server/redistest"
+)
+
+func TestBoardStoreImplementations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boards-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	implementations := map[string]func(t *testing.T) BoardStore{
+		"memory": func(t *testing.T) BoardStore {
+			return NewMemoryBoardStore()
+		},
+		"redis": func(t *testing.T) BoardStore {
+			return NewRedisBoardStore(redistest.NewPool())
+		},
+		"postgres": func(t *testing.T) BoardStore {
+			db := pgtest.Open(t)
+			if err := migrate.Run(context.Background(), db, "scrumboard", PostgresMigrations); err != nil {
+				t.Fatalf("cannot migrate: %s", err)
+			}
+			return NewPostgresBoardStore(db)
+		},
+		"file": func(t *testing.T) BoardStore {
+			bs, err := NewFileBoardStore(filepath.Join(dir, "boards.json"))
+			if err != nil {
+				t.Fatalf("cannot create file store: %s", err)
+			}
+			return bs
+		},
+	}
+
+	for name, newStore := range implementations {
+		t.Run(name, func(t *testing.T) {
+			testBoardStore(t, newStore(t))
+		})
+	}
+}
+
+func testBoardStore(t *testing.T, bs BoardStore) {
+	ctx := context.Background()
+
+	if _, err := bs.CreateBoard(ctx, "short", "Short"); err == nil {
+		t.Fatal("board with too short ID created")
+	}
+
+	board, err := bs.CreateBoard(ctx, "first-board-identifier", "First")
+	if err != nil {
+		t.Fatalf("cannot create board: %s", err)
+	}
+	if board.ID != "first-board-identifier" || board.Name != "First" {
+		t.Fatalf("unexpected board: %+v", board)
+	}
+	if err := bs.AddUser(ctx, board.ID, "1"); err != nil {
+		t.Fatalf("cannot add user: %s", err)
+	}
+	// boards that were never created and demo board are not listed
+	if err := bs.AddUser(ctx, "not-created-board-identifier", "1"); err != nil {
+		t.Fatalf("cannot add user: %s", err)
+	}
+	if err := bs.AddUser(ctx, demoBoardID, "1"); err != nil {
+		t.Fatalf("cannot add user: %s", err)
+	}
+
+	boards, err := bs.UserBoards(ctx, "1")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 || boards[0].ID != board.ID || boards[0].Name != "First" {
+		t.Fatalf("unexpected user boards: %+v", boards)
+	}
+
+	if boards, err := bs.UserBoards(ctx, "2"); err != nil || len(boards) != 0 {
+		t.Fatalf("want no boards, got %+v, %v", boards, err)
+	}
+
+	inactive, err := bs.InactiveBoards(ctx, time.Now().Add(-time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 0 {
+		t.Fatalf("want no inactive boards, got %+v", inactive)
+	}
+	inactive, err = bs.InactiveBoards(ctx, time.Now().Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 3 {
+		t.Fatalf("want all boards to be inactive, got %+v", inactive)
+	}
+	inactive, err = bs.InactiveBoards(ctx, time.Now().Add(time.Hour), 2)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 2 {
+		t.Fatalf("want limited number of boards, got %+v", inactive)
+	}
+
+	// board known only by the state changes is active as well
+	if err := bs.TouchBoard(ctx, "touched-board-identifier"); err != nil {
+		t.Fatalf("cannot touch board: %s", err)
+	}
+	inactive, err = bs.InactiveBoards(ctx, time.Now().Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 4 {
+		t.Fatalf("want touched board to be listed, got %+v", inactive)
+	}
+	if err := bs.DeleteBoard(ctx, "touched-board-identifier"); err != nil {
+		t.Fatalf("cannot delete touched board: %s", err)
+	}
+
+	if err := bs.DeleteBoard(ctx, board.ID); err != nil {
+		t.Fatalf("cannot delete board: %s", err)
+	}
+	if err := bs.DeleteBoard(ctx, board.ID); err != ErrNotFound {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if err := bs.DeleteBoard(ctx, "unknown-board-identifier"); err != ErrNotFound {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	inactive, err = bs.InactiveBoards(ctx, time.Now().Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	for _, b := range inactive {
+		if b.ID == board.ID || b.ID == "touched-board-identifier" {
+			t.Fatalf("deleted board listed: %+v", b)
+		}
+	}
+	if boards, err := bs.UserBoards(ctx, "1"); err != nil || len(boards) != 0 {
+		t.Fatalf("want no boards, got %+v, %v", boards, err)
+	}
+}
+
+func TestFileBoardStorePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boards-")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	path := filepath.Join(dir, "boards.json")
+
+	bs, err := NewFileBoardStore(path)
+	if err != nil {
+		t.Fatalf("cannot create store: %s", err)
+	}
+	if _, err := bs.CreateBoard(ctx, "first-board-identifier", "First"); err != nil {
+		t.Fatalf("cannot create board: %s", err)
+	}
+	if err := bs.AddUser(ctx, "first-board-identifier", "1"); err != nil {
+		t.Fatalf("cannot add user: %s", err)
+	}
+
+	bs, err = NewFileBoardStore(path)
+	if err != nil {
+		t.Fatalf("cannot open store: %s", err)
+	}
+	boards, err := bs.UserBoards(ctx, "1")
+	if err != nil {
+		t.Fatalf("cannot list boards: %s", err)
+	}
+	if len(boards) != 1 || boards[0].Name != "First" {
+		t.Fatalf("unexpected user boards: %+v", boards)
+	}
+}
+
+func TestRedisBoardStoreBackfillActivity(t *testing.T) {
+	ctx := context.Background()
+	rp := redistest.NewPool()
+
+	// boards created before the activity was tracked
+	rc := rp.Get()
+	rc.Send("HMSET", "board:old-board-identifier", "id", "old-board-identifier", "name", "Old")
+	rc.Send("SET", "board:snapshot:snapshot-board-identifier", "{}")
+	rc.Send("ZADD", "board:activity", 1, "tracked-board-identifier")
+	if _, err := rc.Do(""); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	rc.Close()
+
+	bs := NewRedisBoardStore(rp)
+
+	// activity time is not known and is set to the backfill time
+	inactive, err := bs.InactiveBoards(ctx, time.Now().Add(-time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	if len(inactive) != 1 || inactive[0].ID != "tracked-board-identifier" {
+		t.Fatalf("want only tracked board, got %+v", inactive)
+	}
+
+	inactive, err = bs.InactiveBoards(ctx, time.Now().Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("cannot list inactive boards: %s", err)
+	}
+	ids := make(map[string]string)
+	for _, b := range inactive {
+		ids[b.ID] = b.Name
+	}
+	want := map[string]string{
+		"old-board-identifier":      "Old",
+		"snapshot-board-identifier": "",
+		"tracked-board-identifier":  "",
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("want %v boards, got %v", want, ids)
+	}
+}
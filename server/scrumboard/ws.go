@@ -2,10 +2,13 @@ package scrumboard
 
 import (
 	"context"
-	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/husio/scrumboard/server/metrics"
+	"github.com/husio/scrumboard/server/pubsub"
 	"github.com/husio/scrumboard/server/surf"
 )
 
@@ -17,14 +20,24 @@ func (app *ScrumBoardApp) handleClient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !app.connect() {
+		surf.JSONErr(w, http.StatusServiceUnavailable, "server restarting")
+		return
+	}
+	defer app.clients.Done()
+
 	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("cannot upgrade to websocket: %s", err)
+		app.log.Info(r.Context(), "cannot upgrade to websocket",
+			"error", err.Error())
 		surf.JSONErr(w, http.StatusBadRequest, "cannot upgrade to websocket")
 		return
 	}
 	defer ws.Close()
 
+	websocketGauge.Inc()
+	defer websocketGauge.Dec()
+
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
@@ -32,6 +45,15 @@ func (app *ScrumBoardApp) handleClient(w http.ResponseWriter, r *http.Request) {
 	sub := app.hub.Subscribe(boardID, recv)
 	defer sub.Close()
 
+	// websocket does not support concurrent writers, but errors are
+	// written by the reading goroutine
+	var writeMu sync.Mutex
+	write := func(msg []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return ws.WriteMessage(websocket.TextMessage, msg)
+	}
+
 	go func() {
 		for {
 			select {
@@ -40,21 +62,77 @@ func (app *ScrumBoardApp) handleClient(w http.ResponseWriter, r *http.Request) {
 			default:
 				_, msg, err := ws.ReadMessage()
 				if err != nil {
-					log.Printf("cannot read message: %s", err)
+					// client disconnected
+					app.log.Debug(ctx, "cannot read message",
+						"board", boardID,
+						"error", err.Error())
 					cancel()
 					return
 				}
-				sub.Broadcast(msg)
+				if err := sub.Broadcast(msg); err != nil {
+					app.log.Error(ctx, "cannot broadcast",
+						"board", boardID,
+						"error", err.Error())
+					// error is written directly, so that it
+					// does not replace board state waiting
+					// in the subscription queue
+					if err := write(errorMessage(err)); err != nil {
+						app.log.Info(ctx, "cannot write to client",
+							"board", boardID,
+							"error", err.Error())
+					}
+				}
 			}
 		}
 	}()
 
-	for msg := range recv {
-		if err := ws.WriteMessage(websocket.TextMessage, msg); err != nil {
-			log.Printf("cannot write to client: %s", err)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-app.closing:
+			app.writeClose(ctx, ws, websocket.CloseServiceRestart, "server restarting, reconnect")
 			return
+		case <-sub.Done():
+			// client was too slow and missed some of the updates
+			app.writeClose(ctx, ws, closeResync, "resync required")
+			return
+		case msg := <-recv:
+			if err := write(msg); err != nil {
+				app.log.Info(ctx, "cannot write to client",
+					"board", boardID,
+					"error", err.Error())
+				return
+			}
 		}
 	}
 }
 
+// closeResync is websocket close code sent to the clients that were
+// disconnected because of missing updates. Client should reconnect to receive
+// the current board state.
+const closeResync = 4000
+
 var upgrader = websocket.Upgrader{}
+
+var websocketGauge = metrics.NewGauge("scrumboard_websocket_connections",
+	"Number of open websocket connections.")
+
+// writeClose sends close message to the client.
+func (app *ScrumBoardApp) writeClose(ctx context.Context, ws *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	deadline := time.Now().Add(time.Second)
+	if err := ws.WriteControl(websocket.CloseMessage, msg, deadline); err != nil {
+		app.log.Info(ctx, "cannot write close message",
+			"error", err.Error())
+	}
+}
+
+// errorMessage returns message informing the client that the update failed.
+// Error details are not exposed. Type distinguishes it from the board state.
+func errorMessage(err error) []byte {
+	if err == pubsub.ErrSnapshotFailed {
+		return []byte(`{"type":"error","error":"cannot save board state"}`)
+	}
+	return []byte(`{"type":"error","error":"cannot broadcast board state"}`)
+}
@@ -0,0 +1,234 @@
+package scrumboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/husio/scrumboard/server/pubsub"
+)
+
+const testBoardID = "ws-test-board-identifier"
+
+func dialBoard(t *testing.T, srv *httptest.Server, boardID string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/" + boardID
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("cannot dial: %s", err)
+	}
+	return ws
+}
+
+func readMessage(t *testing.T, ws *websocket.Conn) string {
+	t.Helper()
+
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("cannot read message: %s", err)
+	}
+	return string(msg)
+}
+
+func TestWebsocketBroadcast(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	alice := dialBoard(t, srv, testBoardID)
+	defer alice.Close()
+	bob := dialBoard(t, srv, testBoardID)
+	defer bob.Close()
+
+	// new board state is sent to every new client
+	if msg := readMessage(t, alice); msg != string(pubsub.EmptyState) {
+		t.Fatalf("unexpected initial state: %s", msg)
+	}
+	if msg := readMessage(t, bob); msg != string(pubsub.EmptyState) {
+		t.Fatalf("unexpected initial state: %s", msg)
+	}
+
+	state := `{"cards":[],"rows":5}`
+	if err := alice.WriteMessage(websocket.TextMessage, []byte(state)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	if msg := readMessage(t, bob); msg != state {
+		t.Fatalf("unexpected state: %s", msg)
+	}
+
+	// message is not echoed back to the author
+	alice.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, msg, err := alice.ReadMessage(); err == nil {
+		t.Fatalf("author received own message: %s", msg)
+	}
+
+	// late client receives the latest state
+	carol := dialBoard(t, srv, testBoardID)
+	defer carol.Close()
+	if msg := readMessage(t, carol); msg != state {
+		t.Fatalf("unexpected initial state: %s", msg)
+	}
+}
+
+func TestWebsocketBoardsAreSeparated(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	alice := dialBoard(t, srv, testBoardID)
+	defer alice.Close()
+	bob := dialBoard(t, srv, "ws-other-board-identifier")
+	defer bob.Close()
+
+	readMessage(t, alice)
+	readMessage(t, bob)
+
+	if err := alice.WriteMessage(websocket.TextMessage, []byte(`{"cards":[],"rows":5}`)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	bob.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, msg, err := bob.ReadMessage(); err == nil {
+		t.Fatalf("message delivered to other board: %s", msg)
+	}
+}
+
+func TestWebsocketInvalidBoardID(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/short"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatal("connection with invalid board id established")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("want %d response, got %+v", http.StatusBadRequest, resp)
+	}
+}
+
+func TestWebsocketShutdown(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	alice := dialBoard(t, srv, testBoardID)
+	defer alice.Close()
+	readMessage(t, alice)
+
+	ctx, done := context.WithTimeout(context.Background(), 2*time.Second)
+	defer done()
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatalf("cannot shutdown: %s", err)
+	}
+
+	alice.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := alice.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseServiceRestart) {
+		t.Fatalf("want service restart close error, got %v", err)
+	}
+
+	// new clients are not accepted while shutting down
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/" + testBoardID
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatal("connection established during shutdown")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("want %d response, got %+v", http.StatusServiceUnavailable, resp)
+	}
+}
+
+func TestShutdownDuringConnect(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+
+	// clients connecting while shutdown waits must either be rejected or
+	// waited for
+	var served int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for app.connect() {
+				atomic.AddInt32(&served, 1)
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&served, -1)
+				app.clients.Done()
+			}
+		}()
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), 2*time.Second)
+	defer done()
+	time.Sleep(5 * time.Millisecond)
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatalf("cannot shutdown: %s", err)
+	}
+	if n := atomic.LoadInt32(&served); n != 0 {
+		t.Errorf("%d clients served after shutdown", n)
+	}
+	wg.Wait()
+}
+
+// failingHub is a hub storing no snapshots. Messages are still broadcasted.
+type failingHub struct {
+	pubsub.Hub
+}
+
+func (h failingHub) Subscribe(board string, recv chan<- []byte) pubsub.Subscription {
+	return failingSubscription{h.Hub.Subscribe(board, recv)}
+}
+
+type failingSubscription struct {
+	pubsub.Subscription
+}
+
+func (s failingSubscription) Broadcast(msg []byte) error {
+	if err := s.Subscription.Broadcast(msg); err != nil {
+		return err
+	}
+	return pubsub.ErrSnapshotFailed
+}
+
+func TestWebsocketBroadcastError(t *testing.T) {
+	app, _, _ := newTestApp(t, nil)
+	app.hub = failingHub{app.hub}
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	alice := dialBoard(t, srv, testBoardID)
+	defer alice.Close()
+	bob := dialBoard(t, srv, testBoardID)
+	defer bob.Close()
+	readMessage(t, alice)
+	readMessage(t, bob)
+
+	state := `{"cards":[],"rows":5}`
+	if err := alice.WriteMessage(websocket.TextMessage, []byte(state)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	if msg := readMessage(t, alice); msg != `{"type":"error","error":"cannot save board state"}` {
+		t.Fatalf("unexpected error message: %s", msg)
+	}
+	if msg := readMessage(t, bob); msg != state {
+		t.Fatalf("unexpected state: %s", msg)
+	}
+
+	// error is not taking place of the board state sent to the author
+	state = `{"cards":[],"rows":6}`
+	if err := bob.WriteMessage(websocket.TextMessage, []byte(state)); err != nil {
+		t.Fatalf("cannot write: %s", err)
+	}
+	if msg := readMessage(t, alice); msg != state {
+		t.Fatalf("unexpected state: %s", msg)
+	}
+}
@@ -0,0 +1,217 @@
+package surf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Assets serves static files under content hashed names, so that they can be
+// cached by the browser forever. Use Path to get URL of a file.
+//
+// Precompressed file versions are served if the client accepts them and the
+// file system contains a sibling file with .br or .gz extension.
+type Assets struct {
+	// Debug makes files loaded again on every use, so that changes are
+	// visible without restart.
+	Debug bool
+
+	fsys   fs.FS
+	prefix string
+
+	mu     sync.RWMutex
+	files  map[string]*asset
+	hashed map[string]*asset
+}
+
+type asset struct {
+	name    string
+	hashed  string
+	hash    string
+	modTime time.Time
+
+	content []byte
+	brotli  []byte
+	gzip    []byte
+}
+
+// NewAssets returns assets serving all files of given file system. Prefix is
+// the path under which assets are mounted, ie. /static.
+func NewAssets(fsys fs.FS, prefix string) (*Assets, error) {
+	a := &Assets{
+		fsys:   fsys,
+		prefix: strings.TrimSuffix(prefix, "/"),
+	}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Assets) load() error {
+	files := make(map[string]*asset)
+	hashed := make(map[string]*asset)
+	err := fs.WalkDir(a.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(name, ".br") || strings.HasSuffix(name, ".gz") {
+			return nil
+		}
+		content, err := fs.ReadFile(a.fsys, name)
+		if err != nil {
+			return err
+		}
+		var modTime time.Time
+		if info, err := d.Info(); err == nil {
+			modTime = info.ModTime()
+		}
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])[:12]
+		f := &asset{
+			name:    name,
+			hashed:  hashedName(name, hash),
+			hash:    hash,
+			modTime: modTime,
+			content: content,
+		}
+		f.brotli = a.compressed(name+".br", modTime)
+		f.gzip = a.compressed(name+".gz", modTime)
+		files[name] = f
+		hashed[f.hashed] = f
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot load assets: %s", err)
+	}
+
+	a.mu.Lock()
+	a.files = files
+	a.hashed = hashed
+	a.mu.Unlock()
+	return nil
+}
+
+// compressed returns content of the precompressed file or nil if it does not
+// exist. File older than the original is outdated and ignored.
+func (a *Assets) compressed(name string, modTime time.Time) []byte {
+	info, err := fs.Stat(a.fsys, name)
+	if err != nil || info.ModTime().Before(modTime) {
+		return nil
+	}
+	b, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// hashedName returns file name with the hash inserted before the extension,
+// ie. app.min.js becomes app.min.<hash>.js.
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Path returns URL path of the file with given name.
+func (a *Assets) Path(name string) (string, error) {
+	if a.Debug {
+		if err := a.load(); err != nil {
+			return "", err
+		}
+	}
+	a.mu.RLock()
+	f, ok := a.files[strings.TrimPrefix(name, "/")]
+	a.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("asset %q does not exist", name)
+	}
+	return a.prefix + "/" + f.hashed, nil
+}
+
+// ServeHTTP serves the file. Request path must not contain the prefix. File
+// requested by the current hashed name is cached forever, while files
+// requested by name or outdated hash must be revalidated.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.Debug {
+		if err := a.load(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	a.mu.RLock()
+	f, current := a.hashed[name]
+	if !current {
+		f = a.files[name]
+		if f == nil {
+			f = a.files[unhashedName(name)]
+		}
+	}
+	a.mu.RUnlock()
+	if f == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h := w.Header()
+	if current && !a.Debug {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+	if ctype := mime.TypeByExtension(path.Ext(f.name)); ctype != "" {
+		h.Set("Content-Type", ctype)
+	}
+	h.Add("Vary", "Accept-Encoding")
+
+	content, etag := f.content, f.hash
+	accept := r.Header.Get("Accept-Encoding")
+	switch {
+	case f.brotli != nil && acceptsEncoding(accept, "br"):
+		h.Set("Content-Encoding", "br")
+		content, etag = f.brotli, etag+"-br"
+	case f.gzip != nil && acceptsEncoding(accept, "gzip"):
+		h.Set("Content-Encoding", "gzip")
+		content, etag = f.gzip, etag+"-gz"
+	}
+	h.Set("ETag", `"`+etag+`"`)
+	http.ServeContent(w, r, f.name, f.modTime, bytes.NewReader(content))
+}
+
+// unhashedName returns file name with hash removed, or given name if it does
+// not contain a hash.
+func unhashedName(name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	i := strings.LastIndexByte(base, '.')
+	if i < 0 {
+		return name
+	}
+	return base[:i] + ext
+}
+
+// acceptsEncoding returns true if Accept-Encoding header value allows given
+// encoding.
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		chunks := strings.SplitN(strings.TrimSpace(part), ";", 2)
+		if strings.TrimSpace(chunks[0]) != encoding {
+			continue
+		}
+		if len(chunks) == 2 && strings.Replace(chunks[1], " ", "", -1) == "q=0" {
+			return false
+		}
+		return true
+	}
+	return false
+}
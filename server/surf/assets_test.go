@@ -0,0 +1,93 @@
+package surf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.min.js":    {Data: []byte("console.log('hello')")},
+		"app.min.js.gz": {Data: []byte("gzipped")},
+		"app.min.js.br": {Data: []byte("brotli")},
+		"css/app.css":   {Data: []byte("body {}")},
+	}
+	assets, err := NewAssets(fsys, "/static/")
+	if err != nil {
+		t.Fatalf("cannot load assets: %s", err)
+	}
+	rt := NewRouter()
+	rt.Mount(`/static`, assets)
+
+	jsPath, err := assets.Path("app.min.js")
+	if err != nil {
+		t.Fatalf("cannot get path: %s", err)
+	}
+	if !strings.HasPrefix(jsPath, "/static/app.min.") || !strings.HasSuffix(jsPath, ".js") || jsPath == "/static/app.min.js" {
+		t.Fatalf("unexpected path: %q", jsPath)
+	}
+	cssPath, err := assets.Path("css/app.css")
+	if err != nil {
+		t.Fatalf("cannot get path: %s", err)
+	}
+	if _, err := assets.Path("missing.js"); err == nil {
+		t.Error("path of not existing file returned")
+	}
+
+	cases := []struct {
+		path     string
+		encoding string
+		code     int
+		body     string
+		cache    string
+		ctype    string
+	}{
+		{jsPath, "", 200, "console.log('hello')", "public, max-age=31536000, immutable", "text/javascript; charset=utf-8"},
+		{jsPath, "gzip, deflate", 200, "gzipped", "public, max-age=31536000, immutable", "text/javascript; charset=utf-8"},
+		{jsPath, "gzip, br", 200, "brotli", "public, max-age=31536000, immutable", "text/javascript; charset=utf-8"},
+		{jsPath, "gzip, br;q=0", 200, "gzipped", "public, max-age=31536000, immutable", "text/javascript; charset=utf-8"},
+		{cssPath, "gzip", 200, "body {}", "public, max-age=31536000, immutable", "text/css; charset=utf-8"},
+		// not hashed and outdated URLs must be revalidated
+		{"/static/app.min.js", "", 200, "console.log('hello')", "no-cache", "text/javascript; charset=utf-8"},
+		{"/static/app.min.0123456789ab.js", "", 200, "console.log('hello')", "no-cache", "text/javascript; charset=utf-8"},
+		{"/static/missing.js", "", 404, "", "", ""},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", tc.path, nil)
+		if tc.encoding != "" {
+			r.Header.Set("Accept-Encoding", tc.encoding)
+		}
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, r)
+		if w.Code != tc.code {
+			t.Errorf("%s %q: want %d, got %d", tc.path, tc.encoding, tc.code, w.Code)
+			continue
+		}
+		if tc.code != http.StatusOK {
+			continue
+		}
+		if w.Body.String() != tc.body {
+			t.Errorf("%s %q: want %q body, got %q", tc.path, tc.encoding, tc.body, w.Body.String())
+		}
+		if got := w.Header().Get("Cache-Control"); got != tc.cache {
+			t.Errorf("%s %q: want %q cache control, got %q", tc.path, tc.encoding, tc.cache, got)
+		}
+		if got := w.Header().Get("Content-Type"); got != tc.ctype {
+			t.Errorf("%s %q: want %q content type, got %q", tc.path, tc.encoding, tc.ctype, got)
+		}
+	}
+
+	// not modified response is using ETag
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", jsPath, nil))
+	r := httptest.NewRequest("GET", jsPath, nil)
+	r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("want %d, got %d", http.StatusNotModified, w.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package surf
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy allows cross-origin requests to routes of a group. Use
+// Group.CORS to set it.
+type CORSPolicy struct {
+	// AllowedOrigins is the list of origins that can make requests, ie.
+	// "https://example.com". Use "*" to allow any origin.
+	AllowedOrigins []string
+	// AllowedHeaders is the list of request headers, other than the
+	// CORS-safelisted ones, that can be used.
+	AllowedHeaders []string
+	// ExposedHeaders is the list of response headers available to the
+	// client script.
+	ExposedHeaders []string
+	// AllowCredentials allows requests with cookies and authorization
+	// headers. It cannot be used together with "*" origin.
+	AllowCredentials bool
+	// MaxAge is how long preflight response can be cached. Zero leaves
+	// the browser default.
+	MaxAge time.Duration
+}
+
+// allowOrigin returns true if request from given origin is allowed.
+func (p *CORSPolicy) allowOrigin(origin string) bool {
+	for _, o := range p.AllowedOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// setOrigin writes headers shared by preflight and actual responses. False is
+// returned if request origin is not allowed.
+func (p *CORSPolicy) setOrigin(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	// response depends on the origin, even if it is not allowed
+	w.Header().Add("Vary", "Origin")
+	if origin == "" || !p.allowOrigin(origin) {
+		return false
+	}
+	if p.allowOrigin("*") {
+		// credentials are never allowed for a wildcard origin
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if p.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+// setHeaders writes headers of the cross-origin response.
+func (p *CORSPolicy) setHeaders(w http.ResponseWriter, r *http.Request) {
+	if !p.setOrigin(w, r) {
+		return
+	}
+	if len(p.ExposedHeaders) != 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+}
+
+// preflight writes headers of the preflight response. Response without CORS
+// headers is rejecting the request.
+func (p *CORSPolicy) preflight(w http.ResponseWriter, r *http.Request, allow string) {
+	for _, h := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		if h = strings.TrimSpace(h); h != "" && !p.allowHeader(h) {
+			w.Header().Add("Vary", "Origin")
+			return
+		}
+	}
+	if !p.setOrigin(w, r) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", allow)
+	if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+		w.Header().Set("Access-Control-Allow-Headers", h)
+	}
+	if p.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge/time.Second)))
+	}
+}
+
+// allowHeader returns true if request header of given name can be sent.
+func (p *CORSPolicy) allowHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "accept", "accept-language", "content-language", "content-type":
+		return true
+	}
+	for _, h := range p.AllowedHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
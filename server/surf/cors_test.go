@@ -0,0 +1,165 @@
+package surf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	rt := NewRouter()
+	rt.Get(`/private`, echo)
+	api := rt.Group()
+	api.CORS(&CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedHeaders: []string{"Authorization"},
+		ExposedHeaders: []string{"X-Request-Id"},
+		MaxAge:         time.Hour,
+	})
+	api.Get(`/api/boards`, echo)
+	api.Put(`/api/boards/<id>`, echo)
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		code    int
+		want    map[string]string
+	}{
+		{
+			name:   "actual request",
+			method: "GET",
+			path:   "/api/boards",
+			headers: map[string]string{
+				"Origin": "https://example.com",
+			},
+			code: 200,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":   "https://example.com",
+				"Access-Control-Expose-Headers": "X-Request-Id",
+				"Vary":                          "Origin",
+			},
+		},
+		{
+			name:   "preflight",
+			method: "OPTIONS",
+			path:   "/api/boards/1",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "authorization, content-type",
+			},
+			code: 204,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "OPTIONS, PUT",
+				"Access-Control-Allow-Headers": "authorization, content-type",
+				"Access-Control-Max-Age":       "3600",
+				"Allow":                        "OPTIONS, PUT",
+			},
+		},
+		{
+			name:   "preflight with not allowed header",
+			method: "OPTIONS",
+			path:   "/api/boards/1",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			code: 204,
+			want: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:   "not allowed origin",
+			method: "GET",
+			path:   "/api/boards",
+			headers: map[string]string{
+				"Origin": "https://evil.example.com",
+			},
+			code: 200,
+			want: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:   "route without policy",
+			method: "OPTIONS",
+			path:   "/private",
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			code: 204,
+			want: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Allow":                       "GET, HEAD, OPTIONS",
+			},
+		},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, r)
+		if w.Code != tc.code {
+			t.Errorf("%s: want %d, got %d", tc.name, tc.code, w.Code)
+		}
+		for k, v := range tc.want {
+			if got := w.Header().Get(k); got != v {
+				t.Errorf("%s: want %s %q, got %q", tc.name, k, v, got)
+			}
+		}
+	}
+}
+
+func TestCORSPolicyCredentials(t *testing.T) {
+	rt := NewRouter()
+	api := rt.Group()
+	api.CORS(&CORSPolicy{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowCredentials: true,
+	})
+	api.Get(`/api`, func(w http.ResponseWriter, r *http.Request) {})
+
+	r := httptest.NewRequest("GET", "/api", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("origin not allowed: %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("credentials not allowed: %q", got)
+	}
+
+	r = httptest.NewRequest("GET", "/api", nil)
+	r.Header.Set("Origin", "https://attacker.com")
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("not listed origin allowed: %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("credentials allowed for not listed origin: %q", got)
+	}
+}
+
+func TestCORSPolicyWildcardCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("wildcard origin with credentials accepted")
+		}
+	}()
+	NewRouter().Group().CORS(&CORSPolicy{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	})
+}
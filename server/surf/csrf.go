@@ -0,0 +1,94 @@
+package surf
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+)
+
+const (
+	csrfCookieName = "csrf"
+	// CSRFFieldName is the name of the form field carrying CSRF token.
+	CSRFFieldName = "csrf_token"
+	// CSRFHeaderName is the name of the header carrying CSRF token, used
+	// by scripts.
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// CSRFMiddleware protects from cross-site request forgery. Requests with
+// method other than GET, HEAD, OPTIONS and TRACE must carry a valid token,
+// either in the form field or in the header. Otherwise request is rejected
+// with 403 Forbidden.
+//
+// Token is bound to the session returned by the session function, so that
+// token of one session cannot be used with another. Random key used to sign
+// tokens is kept in a cookie, marked as secure if requested.
+func CSRFMiddleware(html Renderer, session func(*http.Request) string, secure bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var key string
+			if c, err := r.Cookie(csrfCookieName); err == nil && len(c.Value) == 64 {
+				key = c.Value
+			} else {
+				key = genCSRFKey()
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookieName,
+					Value:    key,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   secure,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			token := csrfToken(key, session(r))
+
+			switch r.Method {
+			case "GET", "HEAD", "OPTIONS", "TRACE":
+			default:
+				got := r.Header.Get(CSRFHeaderName)
+				if got == "" {
+					got = r.PostFormValue(CSRFFieldName)
+				}
+				if !hmac.Equal([]byte(got), []byte(token)) {
+					html.RenderDefault(w, http.StatusForbidden)
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), csrfTokenKey, token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+var csrfTokenKey = struct{ name string }{"csrf"}
+
+// CSRFToken returns token that must be sent together with state changing
+// request. Token is available only to handlers wrapped with CSRFMiddleware.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey).(string)
+	return token
+}
+
+// csrfField returns hidden form input carrying given CSRF token.
+func csrfField(token string) template.HTML {
+	return template.HTML(`<input type="hidden" name="` + CSRFFieldName + `" value="` + template.HTMLEscapeString(token) + `">`)
+}
+
+func csrfToken(key, session string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(session))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func genCSRFKey() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,62 @@
+package surf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	session := "first"
+	rt := NewRouter()
+	rt.Use(CSRFMiddleware(
+		LoadTemplates("../../templates/*.tmpl"),
+		func(*http.Request) string { return session },
+		true))
+	rt.Get(`/`, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(CSRFToken(r.Context())))
+	})
+	rt.Post(`/`, func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	token := w.Body.String()
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly || !cookies[0].Secure || cookies[0].SameSite != http.SameSiteLaxMode {
+		t.Fatalf("unexpected cookies: %+v", cookies)
+	}
+
+	post := func(form url.Values) int {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := post(url.Values{CSRFFieldName: {token}}); code != http.StatusOK {
+		t.Errorf("valid token: want 200, got %d", code)
+	}
+	if code := post(nil); code != http.StatusForbidden {
+		t.Errorf("missing token: want 403, got %d", code)
+	}
+	if code := post(url.Values{CSRFFieldName: {"invalid"}}); code != http.StatusForbidden {
+		t.Errorf("invalid token: want 403, got %d", code)
+	}
+
+	// token is bound to the session
+	session = "second"
+	if code := post(url.Values{CSRFFieldName: {token}}); code != http.StatusForbidden {
+		t.Errorf("token of another session: want 403, got %d", code)
+	}
+}
+
+func TestCSRFField(t *testing.T) {
+	want := `<input type="hidden" name="csrf_token" value="a&lt;b">`
+	if got := string(csrfField("a<b")); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
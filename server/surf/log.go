@@ -1,16 +1,111 @@
 package surf
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
-	"runtime/debug"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Logger interface {
+	Debug(context.Context, string, ...string)
 	Info(context.Context, string, ...string)
 	Error(context.Context, string, ...string)
+
+	// With returns logger that is adding given key-value pairs to every
+	// entry.
+	With(keyvals ...string) Logger
+}
+
+// LogLevel is the importance of the log entry.
+type LogLevel int32
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelError
+)
+
+func (lvl LogLevel) String() string {
+	switch lvl {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelError:
+		return "error"
+	default:
+		return "level(" + strconv.Itoa(int(lvl)) + ")"
+	}
+}
+
+// ParseLogLevel returns level of given name: debug, info or error.
+func ParseLogLevel(name string) (LogLevel, error) {
+	for _, lvl := range []LogLevel{LevelDebug, LevelInfo, LevelError} {
+		if lvl.String() == name {
+			return lvl, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+// LogFormat is the encoding of log entries.
+type LogFormat int32
+
+const (
+	// LogFmt writes every entry as a line of space separated key=value
+	// pairs.
+	LogFmt LogFormat = iota
+	// LogJSON writes every entry as a single line JSON object.
+	LogJSON
+)
+
+// ParseLogFormat returns format of given name: logfmt or json.
+func ParseLogFormat(name string) (LogFormat, error) {
+	switch name {
+	case "logfmt":
+		return LogFmt, nil
+	case "json":
+		return LogJSON, nil
+	default:
+		return 0, fmt.Errorf("unknown log format %q", name)
+	}
+}
+
+// LogConfig configures all loggers.
+type LogConfig struct {
+	Format LogFormat
+	// MinLevel is the lowest level of entries that are written.
+	MinLevel LogLevel
+	// Caller includes source location of the log call in every entry.
+	Caller bool
+}
+
+var logConfig atomic.Value
+
+func init() {
+	logConfig.Store(LogConfig{
+		Format:   LogFmt,
+		MinLevel: LevelInfo,
+		Caller:   true,
+	})
+}
+
+// ConfigureLogging changes configuration of all loggers, including already
+// created ones.
+func ConfigureLogging(c LogConfig) {
+	logConfig.Store(c)
 }
 
 func NewLogger(out io.Writer, keyvals ...string) Logger {
@@ -18,49 +113,167 @@ func NewLogger(out io.Writer, keyvals ...string) Logger {
 		keyvals = append(keyvals, "")
 	}
 	return &logger{
-		out:     out,
+		out:     syncedWriter(out),
 		keyvals: keyvals,
 	}
 }
 
 type logger struct {
-	out     io.Writer
+	out     *syncWriter
 	keyvals []string
 }
 
-func (lg *logger) log(ctx context.Context, keyvalues ...string) {
-	if len(keyvalues)%2 == 1 {
-		keyvalues = append(keyvalues, "")
+// syncWriter serialize writes, so that entries written concurrently by
+// loggers sharing the same output are not mixed.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var (
+	syncWritersMu sync.Mutex
+	syncWriters   = make(map[io.Writer]*syncWriter)
+)
+
+// syncedWriter returns writer serializing writes to given output. All loggers
+// created with the same output share the same writer.
+func syncedWriter(out io.Writer) *syncWriter {
+	if w, ok := out.(*syncWriter); ok {
+		return w
+	}
+	if !reflect.TypeOf(out).Comparable() {
+		// cannot be shared, but the logger and all its children
+		// are still serialized
+		return &syncWriter{w: out}
 	}
 
-	/*
-		if rid := requestID(ctx); rid != "" {
-			keyvalues = append(keyvalues, "requestId", rid)
-		}
-	*/
+	syncWritersMu.Lock()
+	defer syncWritersMu.Unlock()
+	w, ok := syncWriters[out]
+	if !ok {
+		w = &syncWriter{w: out}
+		syncWriters[out] = w
+	}
+	return w
+}
 
-	keyvalues = append(keyvalues, lg.keyvals...)
+func (w *syncWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(b)
+}
 
-	// can be done better
-	fmt.Fprintf(lg.out, "surf: %q\n", keyvalues)
+func (lg *logger) With(keyvals ...string) Logger {
+	if len(keyvals)%2 == 1 {
+		keyvals = append(keyvals, "")
+	}
+	return &logger{
+		out:     lg.out,
+		keyvals: append(append([]string(nil), lg.keyvals...), keyvals...),
+	}
+}
+
+func (lg *logger) Debug(ctx context.Context, message string, keyvalues ...string) {
+	lg.log(ctx, LevelDebug, message, keyvalues)
 }
 
 func (lg *logger) Info(ctx context.Context, message string, keyvalues ...string) {
-	pairs := append([]string{
-		"message", message,
-		"level", "info",
-		"time", time.Now().Format(time.RFC3339Nano),
-	}, keyvalues...)
-	lg.log(ctx, pairs...)
+	lg.log(ctx, LevelInfo, message, keyvalues)
 }
 
 func (lg *logger) Error(ctx context.Context, message string, keyvalues ...string) {
-	// TODO: include stack and source location
-	pairs := append([]string{
-		"message", message,
-		"level", "error",
-		"stack", string(debug.Stack()),
+	lg.log(ctx, LevelError, message, keyvalues)
+}
+
+// log writes single entry. Must be called directly by the Logger method, so
+// that the caller location is correct.
+func (lg *logger) log(ctx context.Context, lvl LogLevel, message string, keyvalues []string) {
+	conf := logConfig.Load().(LogConfig)
+	if lvl < conf.MinLevel {
+		return
+	}
+
+	pairs := make([]string, 0, 10+len(keyvalues)+len(lg.keyvals))
+	pairs = append(pairs,
 		"time", time.Now().Format(time.RFC3339Nano),
-	}, keyvalues...)
-	lg.log(ctx, pairs...)
+		"level", lvl.String(),
+		"message", message)
+	if conf.Caller {
+		if _, file, line, ok := runtime.Caller(2); ok {
+			file = filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+			pairs = append(pairs, "caller", file+":"+strconv.Itoa(line))
+		}
+	}
+	if ctx != nil {
+		if rid := RequestID(ctx); rid != "" {
+			pairs = append(pairs, "requestId", rid)
+		}
+	}
+	pairs = append(pairs, keyvalues...)
+	if len(pairs)%2 == 1 {
+		pairs = append(pairs, "")
+	}
+	pairs = append(pairs, lg.keyvals...)
+
+	var b bytes.Buffer
+	if conf.Format == LogJSON {
+		writeJSON(&b, pairs)
+	} else {
+		writeLogfmt(&b, pairs)
+	}
+	lg.out.Write(b.Bytes())
+}
+
+func writeLogfmt(b *bytes.Buffer, pairs []string) {
+	for i := 0; i < len(pairs); i += 2 {
+		if i != 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(pairs[i])
+		b.WriteByte('=')
+		if v := pairs[i+1]; v == "" || strings.ContainsAny(v, " =\"\\\n\t") {
+			b.WriteString(strconv.Quote(v))
+		} else {
+			b.WriteString(v)
+		}
+	}
+	b.WriteByte('\n')
+}
+
+func writeJSON(b *bytes.Buffer, pairs []string) {
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(pairs[i])
+		v, _ := json.Marshal(pairs[i+1])
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteString("}\n")
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns context carrying given request ID. Request ID is
+// included in every entry logged with that context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns request ID carried by given context or empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// genRequestID returns new, random request ID.
+func genRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,85 @@
+package surf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoggerLogfmt(t *testing.T) {
+	defer ConfigureLogging(logConfig.Load().(LogConfig))
+	ConfigureLogging(LogConfig{Format: LogFmt, MinLevel: LevelInfo, Caller: true})
+
+	var b bytes.Buffer
+	lg := NewLogger(&b, "app", "test")
+	ctx := WithRequestID(context.Background(), "abc123")
+
+	lg.Debug(ctx, "not written")
+	lg.With("board", "first").Info(ctx, "client connected", "error", `bad "quote"`)
+
+	line := b.String()
+	for _, want := range []string{
+		"level=info ",
+		`message="client connected"`,
+		"caller=surf/log_test.go:",
+		"requestId=abc123",
+		`error="bad \"quote\""`,
+		"app=test board=first\n",
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("%s not found in %q", want, line)
+		}
+	}
+	if strings.Count(line, "\n") != 1 {
+		t.Errorf("want single entry, got %q", line)
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	defer ConfigureLogging(logConfig.Load().(LogConfig))
+	ConfigureLogging(LogConfig{Format: LogJSON, MinLevel: LevelDebug})
+
+	var b bytes.Buffer
+	lg := NewLogger(&b, "app", "test")
+	lg.Debug(context.Background(), "hello", "odd")
+
+	var entry map[string]string
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("cannot decode %q: %s", b.String(), err)
+	}
+	if entry["level"] != "debug" || entry["message"] != "hello" || entry["app"] != "test" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if v, ok := entry["odd"]; !ok || v != "" {
+		t.Errorf("unexpected odd value: %v", entry)
+	}
+	if _, ok := entry["caller"]; ok {
+		t.Errorf("caller included: %v", entry)
+	}
+}
+
+func TestLoggersShareOutput(t *testing.T) {
+	// buffer is not safe for concurrent use, so the race detector notices
+	// loggers that are not serialized
+	var b bytes.Buffer
+	loggers := []Logger{NewLogger(&b, "app", "first"), NewLogger(&b, "app", "second")}
+
+	var wg sync.WaitGroup
+	for _, lg := range loggers {
+		wg.Add(1)
+		go func(lg Logger) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				lg.Info(context.Background(), "hello")
+			}
+		}(lg)
+	}
+	wg.Wait()
+
+	if n := strings.Count(b.String(), "\n"); n != 200 {
+		t.Errorf("want 200 entries, got %d", n)
+	}
+}
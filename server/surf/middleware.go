@@ -0,0 +1,172 @@
+package surf
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// Middleware wraps handler, to run code before or after it.
+type Middleware func(http.Handler) http.Handler
+
+// Chain returns handler wrapped with all given middleware. First middleware
+// is the outermost one.
+func Chain(h http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// Group registers routes in the router, wrapping all handlers with the group
+// middleware.
+type Group struct {
+	rt         *Router
+	middleware []Middleware
+	cors       *CORSPolicy
+}
+
+// Use adds middleware to the group. It is applied only to routes registered
+// afterwards.
+func (g *Group) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// CORS sets cross-origin policy of the group routes. It is applied only to
+// routes registered afterwards.
+//
+// Policy allowing credentials must list allowed origins explicitly, because
+// wildcard would allow credentialed reads from any site.
+func (g *Group) CORS(policy *CORSPolicy) {
+	if policy != nil && policy.AllowCredentials && policy.allowOrigin("*") {
+		panic("CORS policy cannot allow credentials for any origin")
+	}
+	g.cors = policy
+}
+
+// Group returns nested group, using both parent group and given middleware.
+// Cross-origin policy is inherited.
+func (g *Group) Group(middleware ...Middleware) *Group {
+	return &Group{rt: g.rt, middleware: g.with(middleware), cors: g.cors}
+}
+
+// with returns group middleware followed by given middleware.
+func (g *Group) with(middleware []Middleware) []Middleware {
+	all := make([]Middleware, 0, len(g.middleware)+len(middleware))
+	all = append(all, g.middleware...)
+	return append(all, middleware...)
+}
+
+func (g *Group) Add(path, methods string, handler interface{}, middleware ...Middleware) *Route {
+	route := g.rt.Add(path, methods, handler, g.with(middleware)...)
+	route.e.cors = g.cors
+	return route
+}
+
+func (g *Group) Any(path string, handler interface{}, middleware ...Middleware) *Route {
+	return g.Add(path, "*", handler, middleware...)
+}
+
+func (g *Group) Get(path string, handler interface{}, middleware ...Middleware) *Route {
+	return g.Add(path, "GET", handler, middleware...)
+}
+
+func (g *Group) Post(path string, handler interface{}, middleware ...Middleware) *Route {
+	return g.Add(path, "POST", handler, middleware...)
+}
+
+func (g *Group) Put(path string, handler interface{}, middleware ...Middleware) *Route {
+	return g.Add(path, "PUT", handler, middleware...)
+}
+
+func (g *Group) Del(path string, handler interface{}, middleware ...Middleware) *Route {
+	return g.Add(path, "DELETE", handler, middleware...)
+}
+
+// RequestIDMiddleware assigns ID to every request, so that all log entries
+// of the same request can be found. ID is sent back using X-Request-Id
+// header. ID assigned by a proxy is kept, so that requests can be traced
+// across services.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.Header.Get("X-Request-Id")
+		if !validRequestID(rid) {
+			rid = genRequestID()
+		}
+		w.Header().Set("X-Request-Id", rid)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), rid)))
+	})
+}
+
+// validRequestID returns true if given request ID is safe to be logged.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLogMiddleware writes log entry for every handled request.
+func AccessLogMiddleware(lg Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw, ok := w.(*statusWriter)
+			if !ok {
+				sw = &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			}
+
+			start := time.Now()
+			next.ServeHTTP(sw, r)
+
+			route := "none"
+			if info, ok := r.Context().Value(routeInfoKey).(*routeInfo); ok {
+				route = info.route
+			}
+			lg.Info(r.Context(), "request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", route,
+				"code", strconv.Itoa(sw.code),
+				"duration", time.Since(start).String())
+		})
+	}
+}
+
+// RecoverMiddleware recovers from handler panic, logs it and responds with
+// internal server error page.
+func RecoverMiddleware(html Renderer, lg Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw, ok := w.(*statusWriter)
+			if !ok {
+				sw = &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			}
+
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					// used to abort response on purpose
+					panic(p)
+				}
+				lg.Error(r.Context(), "handler panic",
+					"panic", fmt.Sprint(p),
+					"stack", string(debug.Stack()))
+				if !sw.wroteHeader {
+					html.RenderDefault(sw, http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package surf
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// tracing returns middleware appending given name to the X-Trace header
+// before calling wrapped handler.
+func tracing(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	rt := NewRouter()
+	rt.Use(tracing("router-1"), tracing("router-2"))
+	g := rt.Group(tracing("group"))
+	g.Get(`/grouped`, func(w http.ResponseWriter, r *http.Request) {}, tracing("route"))
+	nested := g.Group(tracing("nested"))
+	nested.Get(`/nested`, func(w http.ResponseWriter, r *http.Request) {})
+	rt.Get(`/plain`, func(w http.ResponseWriter, r *http.Request) {})
+
+	cases := map[string]string{
+		"/grouped": "router-1,router-2,group,route",
+		"/nested":  "router-1,router-2,group,nested",
+		"/plain":   "router-1,router-2",
+		"/missing": "router-1,router-2",
+	}
+	for path, want := range cases {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if got := strings.Join(w.Header()["X-Trace"], ","); got != want {
+			t.Errorf("%s: want %q trace, got %q", path, want, got)
+		}
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var got string
+	rt := NewRouter()
+	rt.Use(RequestIDMiddleware)
+	rt.Get(`/`, func(w http.ResponseWriter, r *http.Request) {
+		got = RequestID(r.Context())
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Request-Id", "proxy-id_1")
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if got != "proxy-id_1" || w.Header().Get("X-Request-Id") != got {
+		t.Errorf("proxy request ID not used: %q", got)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Request-Id", "invalid id\n")
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if got == "" || got == "invalid id\n" || w.Header().Get("X-Request-Id") != got {
+		t.Errorf("invalid request ID not replaced: %q", got)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	var b bytes.Buffer
+	rt := NewRouter()
+	rt.Use(
+		AccessLogMiddleware(NewLogger(&b)),
+		RecoverMiddleware(LoadTemplates("../../templates/*.tmpl"), NewLogger(&b)),
+	)
+	rt.Get(`/panic/<id>`, func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/panic/1", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("want 500, got %d", w.Code)
+	}
+
+	log := b.String()
+	for _, want := range []string{
+		"message=\"handler panic\" ",
+		"panic=boom ",
+		"message=request ",
+		"route=/panic/<id> ",
+		"code=500 ",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("%s not found in %q", want, log)
+		}
+	}
+}
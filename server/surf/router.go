@@ -1,16 +1,32 @@
 package surf
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/husio/scrumboard/server/metrics"
 )
 
 type Router struct {
-	endpoints []endpoint
+	// endpoints is the list of all routes and mounts in order of
+	// registration, while tree is used to match request path
+	endpoints []*endpoint
+	tree      node
+	names     map[string]*endpoint
+
+	// middleware wraps every request handled by the router, including
+	// not matching ones
+	middleware []Middleware
+	handler    http.Handler
 
 	// NotFound is called when none of defined handlers match current route.
 	NotFound http.Handler
@@ -43,15 +59,25 @@ func NewJSONRouter() *Router {
 }
 
 // Add registers handler to be called whenever request matching given path
-// regexp and method must be handled.
+// and method must be handled.
 //
 // Use <name> or <name:regexp> to match part of the path and pass result to
 // handler. First example will use [^/]+ to make match, in second one provided
 // regexp is used. Name is not used and is required only for documentation
-// purposes.
+// purposes. Every path segment is matched separately, so regexp must not
+// match the slash.
+//
+// When more than one route is matching the path, static segment is preferred
+// over segment with regexp, which is preferred over <name> placeholder.
+// Segments are compared from left to right.
 //
 // Using '*' as methods will match any method.
-func (r *Router) Add(path, methods string, handler interface{}) {
+//
+// Returned route can be named, so that its URL can be build using URL method.
+//
+// Middleware, if given, wraps only this handler. First middleware is the
+// outermost one.
+func (r *Router) Add(path, methods string, handler interface{}, middleware ...Middleware) *Route {
 	var h http.Handler
 	switch handler := handler.(type) {
 	case http.Handler:
@@ -65,8 +91,84 @@ func (r *Router) Add(path, methods string, handler interface{}) {
 		panic(msg)
 	}
 
-	builder := regexp.MustCompile(`\<.*?\>`)
-	raw := builder.ReplaceAllStringFunc(path, func(s string) string {
+	methodsSet := make(map[string]struct{})
+	for _, method := range strings.Split(methods, ",") {
+		methodsSet[strings.TrimSpace(method)] = struct{}{}
+	}
+
+	e := &endpoint{
+		methods: methodsSet,
+		route:   path,
+		path:    compilePath(path),
+		handler: Chain(h, middleware...),
+	}
+	r.endpoints = append(r.endpoints, e)
+	n := r.tree.insert(splitPath(path))
+	n.endpoints = append(n.endpoints, e)
+	return &Route{rt: r, e: e}
+}
+
+// Route is a single endpoint registered in the router.
+type Route struct {
+	rt *Router
+	e  *endpoint
+}
+
+// Name sets route name, used to build the route URL. Names must be unique
+// within the router.
+func (r *Route) Name(name string) *Route {
+	if _, ok := r.rt.names[name]; ok {
+		panic(fmt.Sprintf("route name %q already used", name))
+	}
+	if r.rt.names == nil {
+		r.rt.names = make(map[string]*endpoint)
+	}
+	r.rt.names[name] = r.e
+	return r
+}
+
+// Mount registers handler to be called for all requests with path starting
+// with given prefix. Prefix can contain <name> and <name:regexp> path
+// arguments, that are available to the mounted handler before its own path
+// arguments. Handler is called with the prefix removed from the request path,
+// so that all its routes are relative to the prefix.
+//
+// Mounting handler implementing Matcher (ie. Router) makes it handle only
+// those paths that it matches. Other paths are routed to the following
+// mounts of the same prefix, which allows to mount several applications under
+// the same prefix. Route matching the whole path is always preferred over
+// mount and the longest mount prefix is preferred over shorter ones.
+func (r *Router) Mount(prefix string, handler http.Handler, middleware ...Middleware) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	m, _ := handler.(Matcher)
+	rev, _ := handler.(Reverser)
+	e := &endpoint{
+		methods:  map[string]struct{}{"*": {}},
+		route:    prefix,
+		mount:    true,
+		matcher:  m,
+		reverser: rev,
+		handler:  Chain(handler, middleware...),
+	}
+	r.endpoints = append(r.endpoints, e)
+	n := &r.tree
+	if prefix != "" {
+		n = n.insert(splitPath(prefix))
+	}
+	n.mounts = append(n.mounts, e)
+}
+
+// Matcher is implemented by handlers serving only some of the paths.
+type Matcher interface {
+	// Match returns true if handler is serving given path, for any
+	// method.
+	Match(path string) bool
+}
+
+// compilePath returns regular expression matching given routing path. It is
+// used only to validate built URLs, requests are matched using the tree.
+func compilePath(path string) *regexp.Regexp {
+	raw := placeholderRx.ReplaceAllStringFunc(path, func(s string) string {
 		s = s[1 : len(s)-1]
 		// every <name> can be optionally contain separate regexp
 		// definition using notation <name:regexp>
@@ -76,86 +178,266 @@ func (r *Router) Add(path, methods string, handler interface{}) {
 		}
 		return `(` + chunks[1] + `)`
 	})
-	// replace {} with regular expressions syntax
 	rx, err := regexp.Compile(`^` + raw + `$`)
 	if err != nil {
 		panic(fmt.Sprintf("invalid routing path %q: %s", path, err))
 	}
+	return rx
+}
 
-	methodsSet := make(map[string]struct{})
-	for _, method := range strings.Split(methods, ",") {
-		methodsSet[strings.TrimSpace(method)] = struct{}{}
-	}
-
-	r.endpoints = append(r.endpoints, endpoint{
-		methods: methodsSet,
-		path:    rx,
-		handler: h,
-	})
+func (r *Router) Any(path string, handler interface{}, middleware ...Middleware) *Route {
+	return r.Add(path, "*", handler, middleware...)
+}
 
+func (r *Router) Get(path string, handler interface{}, middleware ...Middleware) *Route {
+	return r.Add(path, "GET", handler, middleware...)
 }
 
-func (r *Router) Any(path string, handler interface{}) {
-	r.Add(path, "*", handler)
+func (r *Router) Post(path string, handler interface{}, middleware ...Middleware) *Route {
+	return r.Add(path, "POST", handler, middleware...)
 }
 
-func (r *Router) Get(path string, handler interface{}) {
-	r.Add(path, "GET", handler)
+func (r *Router) Put(path string, handler interface{}, middleware ...Middleware) *Route {
+	return r.Add(path, "PUT", handler, middleware...)
 }
 
-func (r *Router) Post(path string, handler interface{}) {
-	r.Add(path, "POST", handler)
+func (r *Router) Del(path string, handler interface{}, middleware ...Middleware) *Route {
+	return r.Add(path, "DELETE", handler, middleware...)
 }
 
-func (r *Router) Put(path string, handler interface{}) {
-	r.Add(path, "PUT", handler)
+// Use adds middleware that wraps all requests handled by the router, including
+// those not matching any route. Middleware must be added before the router is
+// used to serve requests.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+	r.handler = Chain(http.HandlerFunc(r.dispatch), r.middleware...)
 }
 
-func (r *Router) Del(path string, handler interface{}) {
-	r.Add(path, "DELETE", handler)
+// Group returns route group that is wrapping all its handlers with given
+// middleware.
+func (r *Router) Group(middleware ...Middleware) *Group {
+	return &Group{rt: r, middleware: middleware}
 }
 
 type endpoint struct {
 	methods map[string]struct{}
+	route   string
 	path    *regexp.Regexp
 	handler http.Handler
+
+	// cors is the cross-origin policy of the route, if any
+	cors *CORSPolicy
+
+	// mount endpoint is matching path prefix, while the rest of the path
+	// is handled by the mounted handler
+	mount    bool
+	matcher  Matcher
+	reverser Reverser
+}
+
+// allows returns true if endpoint is handling given method. Empty method is
+// allowed by all endpoints. HEAD is handled by GET handler, unless defined
+// separately.
+func (e *endpoint) allows(method string) bool {
+	if method == "" {
+		return true
+	}
+	if _, ok := e.methods[method]; ok {
+		return true
+	}
+	if method == "HEAD" {
+		if _, ok := e.methods["GET"]; ok {
+			return true
+		}
+	}
+	_, ok := e.methods["*"]
+	return ok
+}
+
+// Match returns true if any of the routes is matching given path.
+func (rt *Router) Match(path string) bool {
+	var res lookup
+	return rt.tree.find(splitPath(path), "", nil, &res)
 }
 
 func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var pathMatch bool
+	// request is measured only by the outermost router, but the route is
+	// set by the router that is calling the final handler
+	info, ok := r.Context().Value(routeInfoKey).(*routeInfo)
+	if !ok {
+		info = &routeInfo{route: "none", root: rt}
+		r = r.WithContext(context.WithValue(r.Context(), routeInfoKey, info))
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		w = sw
+		start := time.Now()
+		defer func() {
+			method := metricMethod(r.Method)
+			httpRequests.Inc(info.route, method, strconv.Itoa(sw.code))
+			httpDuration.Observe(time.Since(start).Seconds(), info.route, method)
+		}()
+	}
 
-	for _, endpoint := range rt.endpoints {
-		match := endpoint.path.FindAllStringSubmatch(r.URL.Path, 1)
-		if len(match) == 0 {
-			continue
-		}
+	if rt.handler != nil {
+		rt.handler.ServeHTTP(w, r)
+	} else {
+		rt.dispatch(w, r)
+	}
+}
 
-		pathMatch = true
+// dispatch calls handler of the route matching the request.
+func (rt *Router) dispatch(w http.ResponseWriter, r *http.Request) {
+	info, _ := r.Context().Value(routeInfoKey).(*routeInfo)
 
-		_, ok := endpoint.methods[r.Method]
-		if !ok {
-			_, ok = endpoint.methods["*"]
+	segments := splitPath(r.URL.Path)
+	var res lookup
+	if !rt.tree.find(segments, r.Method, nil, &res) {
+		switch {
+		case !res.pathMatch:
+			rt.NotFound.ServeHTTP(w, r)
+		case r.Method == "OPTIONS":
+			rt.options(w, r, segments, allowHeader(res.allowed))
+		default:
+			w.Header().Set("Allow", allowHeader(res.allowed))
+			rt.MethodNotAllowed.ServeHTTP(w, r)
 		}
-		if !ok {
-			continue
-		}
-
-		args := match[0][1:]
-		r = r.WithContext(context.WithValue(r.Context(), pathArgsKey, args))
-		endpoint.handler.ServeHTTP(w, r)
 		return
 	}
+	endpoint := res.endpoint
+	if endpoint.cors != nil {
+		endpoint.cors.setHeaders(w, r)
+	}
 
-	if pathMatch {
-		rt.MethodNotAllowed.ServeHTTP(w, r)
+	// arguments of the outer routers go first
+	args := res.args
+	if outer, ok := r.Context().Value(pathArgsKey).([]string); ok {
+		args = append(append([]string(nil), outer...), args...)
+	}
+	ctx := context.WithValue(r.Context(), pathArgsKey, args)
+
+	if endpoint.mount {
+		info.prefix += endpoint.route
+		info.route = info.prefix + "/*"
+		r = r.WithContext(ctx)
+		u := *r.URL
+		u.Path = res.rest
+		u.RawPath = ""
+		r.URL = &u
 	} else {
-		rt.NotFound.ServeHTTP(w, r)
+		info.route = info.prefix + endpoint.route
+		r = r.WithContext(ctx)
 	}
+	endpoint.handler.ServeHTTP(w, r)
+}
+
+// options responds to OPTIONS request of the path without OPTIONS handler. CORS
+// preflight request is answered using policy of the route that is handling
+// requested method.
+func (rt *Router) options(w http.ResponseWriter, r *http.Request, segments []string, allow string) {
+	w.Header().Set("Allow", allow)
+	if method := r.Header.Get("Access-Control-Request-Method"); method != "" && r.Header.Get("Origin") != "" {
+		var res lookup
+		if rt.tree.find(segments, method, nil, &res) && res.endpoint.cors != nil {
+			res.endpoint.cors.preflight(w, r, allow)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowHeader returns value of the Allow header for given methods.
+func allowHeader(methods map[string]struct{}) string {
+	all := []string{"OPTIONS"}
+	for m := range methods {
+		all = append(all, m)
+	}
+	if _, ok := methods["GET"]; ok {
+		if _, ok := methods["HEAD"]; !ok {
+			all = append(all, "HEAD")
+		}
+	}
+	sort.Strings(all)
+	return strings.Join(all, ", ")
 }
 
 var pathArgsKey = struct{}{}
 
-// PathArg return value as matched by path regexp at given index.
+var (
+	httpRequests = metrics.NewCounter("http_requests_total",
+		"Number of handled HTTP requests, by route, method and response code.",
+		"route", "method", "code")
+	httpDuration = metrics.NewHistogram("http_request_duration_seconds",
+		"HTTP request handling latency, by route and method.",
+		metrics.DefaultBuckets, "route", "method")
+)
+
+type routeInfo struct {
+	route string
+	// prefix of all mount endpoints on the way to the final handler
+	prefix string
+	// root is the outermost router, used to build URLs
+	root *Router
+}
+
+var routeInfoKey = struct{ name string }{"route"}
+
+// metricMethod returns method name that is safe to be used as metric label.
+func metricMethod(method string) string {
+	switch method {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+		return method
+	default:
+		return "other"
+	}
+}
+
+// statusWriter records response status code. Hijacking and flushing of the
+// wrapped writer is supported, so that websocket and streaming handlers
+// keep working.
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking not supported")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.code = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the original response writer, used by http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// PathArg return value as matched by path regexp at given index. Arguments
+// matched by mount prefixes come first, in the order of mounting.
 func PathArg(r *http.Request, index int) string {
 	args, ok := r.Context().Value(pathArgsKey).([]string)
 	if !ok {
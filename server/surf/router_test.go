@@ -0,0 +1,251 @@
+package surf
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echo returns handler writing request path and all path arguments.
+func echo(w http.ResponseWriter, r *http.Request) {
+	args := []string{r.URL.Path}
+	for i := 0; PathArg(r, i) != ""; i++ {
+		args = append(args, PathArg(r, i))
+	}
+	io.WriteString(w, strings.Join(args, " "))
+}
+
+func TestRouterMount(t *testing.T) {
+	boards := NewRouter()
+	boards.Get(`/`, echo)
+	boards.Get(`/cards/<card-id:\d+>`, echo)
+
+	users := NewRouter()
+	users.Get(`/users/<name>`, echo)
+
+	rt := NewRouter()
+	rt.Mount(`/b/<board-id>/`, boards)
+	rt.Mount(`/`, users)
+	rt.Mount(`/static`, http.HandlerFunc(echo))
+	rt.Get(`/about`, echo)
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/b/xyz", 200, "/ xyz"},
+		{"GET", "/b/xyz/", 200, "/ xyz"},
+		{"GET", "/b/xyz/cards/12", 200, "/cards/12 xyz 12"},
+		{"GET", "/b/xyz/cards/abc", 404, ""},
+		{"POST", "/b/xyz/cards/12", 405, ""},
+		{"GET", "/users/bob", 200, "/users/bob bob"},
+		{"GET", "/static/js/app.js", 200, "/js/app.js"},
+		{"GET", "/staticfile", 404, ""},
+		// not matched by mounted router, handled by following route
+		{"GET", "/about", 200, "/about"},
+		{"GET", "/missing", 404, ""},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: want %d, got %d", tc.method, tc.path, tc.code, w.Code)
+			continue
+		}
+		if tc.body != "" && w.Body.String() != tc.body {
+			t.Errorf("%s %s: want %q, got %q", tc.method, tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+func TestRouterMountRoute(t *testing.T) {
+	var route string
+	inner := NewRouter()
+	inner.Get(`/cards/<card-id>`, func(w http.ResponseWriter, r *http.Request) {
+		route = r.Context().Value(routeInfoKey).(*routeInfo).route
+	})
+	rt := NewRouter()
+	rt.Mount(`/b/<board-id>`, inner)
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/b/1/cards/2", nil))
+	if want := "/b/<board-id>/cards/<card-id>"; route != want {
+		t.Errorf("want %q route, got %q", want, route)
+	}
+}
+
+func TestRouterURL(t *testing.T) {
+	boards := NewRouter()
+	boards.Get(`/`, echo).Name("board")
+	boards.Get(`/cards/<card-id:\d+>`, echo).Name("card")
+
+	rt := NewRouter()
+	rt.Get(`/`, echo).Name("index")
+	rt.Get(`/users/<name>`, echo).Name("user")
+	rt.Mount(`/b/<board-id>`, boards)
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"index", nil, "/"},
+		{"user", []string{"bob smith"}, "/users/bob%20smith"},
+		{"board", []string{"xyz"}, "/b/xyz/"},
+		{"card", []string{"xyz", "12"}, "/b/xyz/cards/12"},
+	}
+	for _, tc := range cases {
+		got, err := rt.URL(tc.name, tc.args...)
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	if _, err := rt.URL("missing"); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("want ErrNoRoute, got %v", err)
+	}
+	if _, err := rt.URL("user"); err == nil {
+		t.Error("missing argument accepted")
+	}
+	if _, err := rt.URL("card", "xyz", "not-a-number"); err == nil {
+		t.Error("argument not matching route accepted")
+	}
+}
+
+func TestReverseURL(t *testing.T) {
+	inner := NewRouter()
+	var got string
+	inner.Get(`/`, func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ReverseURL(r.Context(), "about")
+	})
+	rt := NewRouter()
+	rt.Get(`/about`, echo).Name("about")
+	rt.Mount(`/`, inner)
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if got != "/about" {
+		t.Errorf("want /about, got %q", got)
+	}
+}
+
+func TestRouterPrecedence(t *testing.T) {
+	named := func(name string) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, name)
+		}
+	}
+
+	rt := NewRouter()
+	rt.Get(`/b/<id>/cards`, named("param"))
+	rt.Get(`/b/<id:\d+>/cards`, named("regexp"))
+	rt.Get(`/b/new/cards`, named("static"))
+	rt.Get(`/b/<id>/history`, named("history"))
+	rt.Get(`/b/card-<id>`, named("prefixed"))
+	rt.Post(`/b/<id>/edit`, named("edit"))
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/b/new/cards", 200, "static"},
+		{"GET", "/b/123/cards", 200, "regexp"},
+		{"GET", "/b/abc/cards", 200, "param"},
+		// no static nor regexp match, so placeholder is used
+		{"GET", "/b/new/history", 200, "history"},
+		{"GET", "/b/123/history", 200, "history"},
+		{"GET", "/b/card-12", 200, "prefixed"},
+		{"GET", "/b/123/edit", 405, ""},
+		{"GET", "/b//cards", 404, ""},
+		{"GET", "/b/new/cards/", 404, ""},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: want %d, got %d", tc.method, tc.path, tc.code, w.Code)
+			continue
+		}
+		if tc.body != "" && w.Body.String() != tc.body {
+			t.Errorf("%s %s: want %q, got %q", tc.method, tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+func TestRouterMethods(t *testing.T) {
+	rt := NewRouter()
+	rt.Get(`/boards`, echo)
+	rt.Post(`/boards`, echo)
+	rt.Add(`/boards/<id>`, "PUT,DELETE", echo)
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		allow  string
+	}{
+		{"HEAD", "/boards", 200, ""},
+		{"PATCH", "/boards", 405, "GET, HEAD, OPTIONS, POST"},
+		{"OPTIONS", "/boards", 204, "GET, HEAD, OPTIONS, POST"},
+		{"GET", "/boards/1", 405, "DELETE, OPTIONS, PUT"},
+		{"OPTIONS", "/missing", 404, ""},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: want %d, got %d", tc.method, tc.path, tc.code, w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != tc.allow {
+			t.Errorf("%s %s: want %q allow header, got %q", tc.method, tc.path, tc.allow, got)
+		}
+	}
+}
+
+// benchRouter returns router with a number of routes similar to a typical
+// application.
+func benchRouter() *Router {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	rt := NewRouter()
+	for _, resource := range []string{"users", "boards", "cards", "teams", "projects", "comments", "labels", "events"} {
+		rt.Get(`/`+resource, noop)
+		rt.Post(`/`+resource, noop)
+		rt.Get(`/`+resource+`/<id>`, noop)
+		rt.Put(`/`+resource+`/<id>`, noop)
+		rt.Del(`/`+resource+`/<id>`, noop)
+		rt.Get(`/`+resource+`/<id>/history/<page:\d+>`, noop)
+	}
+	return rt
+}
+
+func BenchmarkRouterStatic(b *testing.B) {
+	benchmarkRouter(b, "GET", "/events")
+}
+
+func BenchmarkRouterArgs(b *testing.B) {
+	benchmarkRouter(b, "GET", "/events/1234/history/5")
+}
+
+func BenchmarkRouterNotFound(b *testing.B) {
+	benchmarkRouter(b, "GET", "/not/existing/path")
+}
+
+func benchmarkRouter(b *testing.B, method, path string) {
+	rt := benchRouter()
+	r := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rt.ServeHTTP(w, r)
+	}
+}
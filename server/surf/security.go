@@ -0,0 +1,88 @@
+package surf
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityPolicy configures security headers sent with every response. Empty
+// values are not sent.
+type SecurityPolicy struct {
+	// ContentSecurityPolicy is the CSP header value. Every {nonce}
+	// occurrence is replaced with a random value generated for every
+	// request, that must be used by all inline scripts. Use CSPNonce to
+	// get the nonce value.
+	ContentSecurityPolicy string
+	// HSTSMaxAge is how long browser must use only HTTPS to connect.
+	// Enable only when served over HTTPS.
+	HSTSMaxAge time.Duration
+	// FrameOptions is the X-Frame-Options header value, ie. DENY.
+	FrameOptions string
+	// ReferrerPolicy is the Referrer-Policy header value.
+	ReferrerPolicy string
+}
+
+// SecurityHeadersMiddleware sets security headers of every response,
+// including Content Security Policy. X-Content-Type-Options is always set to
+// nosniff.
+func SecurityHeadersMiddleware(p SecurityPolicy) Middleware {
+	withNonce := strings.Contains(p.ContentSecurityPolicy, "{nonce}")
+	var hsts string
+	if p.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(p.HSTSMaxAge/time.Second))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			if p.FrameOptions != "" {
+				h.Set("X-Frame-Options", p.FrameOptions)
+			}
+			if p.ReferrerPolicy != "" {
+				h.Set("Referrer-Policy", p.ReferrerPolicy)
+			}
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			if p.ContentSecurityPolicy != "" {
+				csp := p.ContentSecurityPolicy
+				if withNonce {
+					nonce := genNonce()
+					csp = strings.Replace(csp, "{nonce}", nonce, -1)
+					r = r.WithContext(context.WithValue(r.Context(), cspNonceKey, nonce))
+				}
+				h.Set("Content-Security-Policy", csp)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+var cspNonceKey = struct{ name string }{"csp-nonce"}
+
+// CSPNonce returns nonce that inline scripts must use to be executed. Nonce
+// is available only to handlers wrapped with SecurityHeadersMiddleware.
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey).(string)
+	return nonce
+}
+
+// nonce returns nonce attribute of the inline script or style.
+func nonce(value string) template.HTMLAttr {
+	return template.HTMLAttr(`nonce="` + template.HTMLEscapeString(value) + `"`)
+}
+
+func genNonce() string {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package surf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	var nonce string
+	rt := NewRouter()
+	rt.Use(SecurityHeadersMiddleware(SecurityPolicy{
+		ContentSecurityPolicy: "script-src 'nonce-{nonce}'",
+		HSTSMaxAge:            time.Hour,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "same-origin",
+	}))
+	rt.Get(`/`, func(w http.ResponseWriter, r *http.Request) {
+		nonce = CSPNonce(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if nonce == "" {
+		t.Fatal("nonce not available")
+	}
+	want := map[string]string{
+		"Content-Security-Policy":   "script-src 'nonce-" + nonce + "'",
+		"Strict-Transport-Security": "max-age=3600",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "same-origin",
+		"X-Content-Type-Options":    "nosniff",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("want %s %q, got %q", k, v, got)
+		}
+	}
+
+	first := nonce
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if nonce == first {
+		t.Error("nonce reused")
+	}
+}
+
+func TestSecurityHeadersMiddlewareDisabled(t *testing.T) {
+	rt := NewRouter()
+	rt.Use(SecurityHeadersMiddleware(SecurityPolicy{}))
+	rt.Get(`/`, func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	for _, name := range []string{"Content-Security-Policy", "Strict-Transport-Security", "X-Frame-Options", "Referrer-Policy"} {
+		if v := w.Header().Get(name); v != "" {
+			t.Errorf("%s set to %q", name, v)
+		}
+	}
+	if !strings.Contains(w.Header().Get("X-Content-Type-Options"), "nosniff") {
+		t.Error("nosniff not set")
+	}
+}
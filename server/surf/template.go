@@ -2,11 +2,18 @@ package surf
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Renderer interface {
@@ -15,23 +22,39 @@ type Renderer interface {
 }
 
 type TemplateRenderer struct {
+	// Debug makes errors rendered together with the stack trace and the
+	// last template parsing error.
 	Debug bool
 	tglob string
 
-	parseErr  error
-	templates map[string]*template.Template
+	// Routes is used by the url template function to build route URLs.
+	Routes Reverser
+	// Assets is used by the asset template function to build static file
+	// URLs.
+	Assets *Assets
+
+	// set holds the current *templateSet. It is replaced as a whole when
+	// templates are parsed again, so that rendering never sees a partially
+	// loaded set.
+	set atomic.Value
 
 	buffers sync.Pool
 }
 
+// templateSet is the result of parsing all templates.
+type templateSet struct {
+	templates map[string]*template.Template
+	parseErr  error
+	// version identifies state of the template files when parsed
+	version string
+}
+
 var _ Renderer = (*TemplateRenderer)(nil)
 
 func LoadTemplates(templatesPath string) *TemplateRenderer {
 	r := &TemplateRenderer{
-		Debug:     false,
-		tglob:     templatesPath,
-		templates: nil,
-		parseErr:  nil,
+		Debug: false,
+		tglob: templatesPath,
 		buffers: sync.Pool{
 			New: func() interface{} { return bytes.NewBuffer(nil) },
 		},
@@ -42,28 +65,190 @@ func LoadTemplates(templatesPath string) *TemplateRenderer {
 }
 
 func (r *TemplateRenderer) loadTemplates() {
-	t, err := defaultTemplates().ParseGlob(r.tglob)
-	r.parseErr = err
-
+	// version is computed before parsing, so that files changed in
+	// the meantime are parsed again by the watcher
+	version := r.version()
+	templates, err := r.parse()
 	if err != nil {
 		// if failed to parse user provided templates, fallback to
 		// defaults only, because they are providing templates that are
 		// expected to always be present
-		t = defaultTemplates()
+		templates = make(map[string]*template.Template)
+		for _, t := range defaultTemplates().Templates() {
+			templates[t.Name()] = t
+		}
+	}
+	r.set.Store(&templateSet{
+		templates: templates,
+		parseErr:  err,
+		version:   version,
+	})
+}
+
+func (r *TemplateRenderer) current() *templateSet {
+	return r.set.Load().(*templateSet)
+}
+
+// Watch parses templates again whenever any of the files matching the glob is
+// modified, created or removed. Files are checked every interval until the
+// context is cancelled.
+func (r *TemplateRenderer) Watch(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if r.version() != r.current().version {
+				r.loadTemplates()
+			}
+		}
+	}
+}
+
+// version returns description of all files matching the glob, that changes
+// whenever any of them is modified.
+func (r *TemplateRenderer) version() string {
+	paths, err := filepath.Glob(r.tglob)
+	if err != nil {
+		return ""
 	}
-	r.templates = make(map[string]*template.Template)
-	for _, t := range t.Templates() {
-		r.templates[t.Name()] = t
+	var b strings.Builder
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			// removed in the meantime
+			continue
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", p, info.Size(), info.ModTime().UnixNano())
 	}
+	return b.String()
 }
 
-func (r *TemplateRenderer) Render(w http.ResponseWriter, code int, templateName string, content interface{}) {
-	if r.Debug {
-		r.loadTemplates()
+// parse returns all templates matching the glob, by file name.
+//
+// Files with a name starting with an underscore are layouts and partials
+// shared by all other templates. Every other file is parsed into a separate
+// copy of them, so that each page can define its own blocks, ie. "title" or
+// "body", without overwriting blocks of other pages.
+func (r *TemplateRenderer) parse() (map[string]*template.Template, error) {
+	paths, err := filepath.Glob(r.tglob)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find templates: %s", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("template: pattern matches no files: %#q", r.tglob)
+	}
+
+	var shared, pages []string
+	for _, p := range paths {
+		if strings.HasPrefix(filepath.Base(p), "_") {
+			shared = append(shared, p)
+		} else {
+			pages = append(pages, p)
+		}
+	}
+
+	base := defaultTemplates().Funcs(r.funcs())
+	if len(shared) != 0 {
+		if base, err = base.ParseFiles(shared...); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make(map[string]*template.Template)
+	for _, t := range base.Templates() {
+		templates[t.Name()] = t
+	}
+	for _, p := range pages {
+		t, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if t, err = t.ParseFiles(p); err != nil {
+			return nil, err
+		}
+		name := filepath.Base(p)
+		templates[name] = t.Lookup(name)
+	}
+	return templates, nil
+}
+
+// funcs returns functions available in all templates.
+func (r *TemplateRenderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"url":        r.url,
+		"asset":      r.asset,
+		"csrfField":  csrfField,
+		"nonce":      nonce,
+		"formatTime": formatTime,
+		"pluralize":  pluralize,
+		"json":       toJSON,
+	}
+}
+
+// url returns path of the named route. Arguments can be of any type and are
+// formatted using fmt.Sprint.
+func (r *TemplateRenderer) url(name string, args ...interface{}) (string, error) {
+	if r.Routes == nil {
+		return "", fmt.Errorf("cannot build %q URL: no routes", name)
+	}
+	sargs := make([]string, len(args))
+	for i, a := range args {
+		sargs[i] = fmt.Sprint(a)
+	}
+	return r.Routes.URL(name, sargs...)
+}
+
+// asset returns URL path of the static file.
+func (r *TemplateRenderer) asset(name string) (string, error) {
+	if r.Assets == nil {
+		return "", fmt.Errorf("cannot build %q URL: no assets", name)
+	}
+	return r.Assets.Path(name)
+}
+
+// formatTime returns time formatted using given layout. Zero time is
+// rendered as an empty string.
+func formatTime(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.Format(layout)
+}
+
+// pluralize returns the number followed by the singular or plural noun, ie.
+// "1 board" or "3 boards".
+func pluralize(singular, plural string, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
 
-	if r.parseErr != nil {
-		r.renderError(w, r.parseErr)
+// toJSON returns value serialized to JSON, that can be safely embedded in a
+// script. Characters that could close the script tag or the string literal
+// are escaped.
+func toJSON(v interface{}) (template.JS, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("cannot serialize to JSON: %s", err)
+	}
+	return template.JS(b), nil
+}
+
+// Err returns template parsing error. If not nil, only default templates can
+// be rendered.
+func (r *TemplateRenderer) Err() error {
+	return r.current().parseErr
+}
+
+func (r *TemplateRenderer) Render(w http.ResponseWriter, code int, templateName string, content interface{}) {
+	set := r.current()
+	if set.parseErr != nil {
+		r.renderError(w, set.parseErr)
 		return
 	}
 
@@ -75,7 +260,7 @@ func (r *TemplateRenderer) Render(w http.ResponseWriter, code int, templateName
 		r.buffers.Put(buf)
 	}()
 
-	tmpl, ok := r.templates[templateName]
+	tmpl, ok := set.templates[templateName]
 	if !ok {
 		r.renderError(w, fmt.Errorf("template %q does not exist", templateName))
 		return
@@ -101,7 +286,7 @@ func (r *TemplateRenderer) RenderDefault(w http.ResponseWriter, code int) {
 		content.Stack = string(debug.Stack())
 		templateName = "error_debug.tmpl"
 	} else if code >= 400 {
-		if n := fmt.Sprintf("error_%d.tmpl", code); r.templates[n] != nil {
+		if n := fmt.Sprintf("error_%d.tmpl", code); r.current().templates[n] != nil {
 			templateName = n
 		}
 	}
@@ -119,9 +304,10 @@ func (r *TemplateRenderer) renderError(w http.ResponseWriter, err error) {
 		Stack:      string(debug.Stack()),
 	}
 
-	tmpl := r.templates["default.tmpl"]
+	set := r.current()
+	tmpl := set.templates["default.tmpl"]
 	if r.Debug {
-		tmpl = r.templates["error_debug.tmpl"]
+		tmpl = set.templates["error_debug.tmpl"]
 	}
 	tmpl.Execute(w, content)
 }
@@ -0,0 +1,159 @@
+package surf
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeTemplates creates template files in a temporary directory and returns
+// glob matching all of them.
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "surf-templates")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("cannot write %s: %s", name, err)
+		}
+	}
+	return filepath.Join(dir, "*.tmpl")
+}
+
+func TestTemplateLayout(t *testing.T) {
+	html := LoadTemplates(writeTemplates(t, map[string]string{
+		"_layout.tmpl":  `{{define "layout"}}<title>{{block "title" .}}default{{end}}</title>{{block "body" .}}{{end}}{{end}}`,
+		"_partial.tmpl": `{{define "greeting"}}hello {{.}}{{end}}`,
+		"first.tmpl":    `{{template "layout" .}}{{define "title"}}first{{end}}{{define "body"}}{{template "greeting" .}}{{end}}`,
+		"second.tmpl":   `{{template "layout" .}}{{define "body"}}second {{.}}{{end}}`,
+	}))
+	if err := html.Err(); err != nil {
+		t.Fatalf("cannot parse templates: %s", err)
+	}
+
+	cases := map[string]string{
+		"first.tmpl":  "<title>first</title>hello bob",
+		"second.tmpl": "<title>default</title>second bob",
+	}
+	for name, want := range cases {
+		w := httptest.NewRecorder()
+		html.Render(w, http.StatusOK, name, "bob")
+		if got := w.Body.String(); got != want {
+			t.Errorf("%s: want %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	html := LoadTemplates(writeTemplates(t, map[string]string{
+		"page.tmpl": `{{pluralize "board" "boards" .One}}, {{pluralize "board" "boards" .Many}}
+<script>var data = {{json .Data}};</script>`,
+	}))
+	if err := html.Err(); err != nil {
+		t.Fatalf("cannot parse templates: %s", err)
+	}
+
+	content := struct {
+		One, Many int
+		Data      map[string]string
+	}{
+		One:  1,
+		Many: 3,
+		Data: map[string]string{"name": "</script><script>alert(1)</script>"},
+	}
+	w := httptest.NewRecorder()
+	html.Render(w, http.StatusOK, "page.tmpl", content)
+	body := w.Body.String()
+	if !strings.Contains(body, "1 board, 3 boards") {
+		t.Errorf("pluralized nouns not rendered: %s", body)
+	}
+	want := `var data = {"name":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"};`
+	if !strings.Contains(body, want) {
+		t.Errorf("JSON not escaped: %s", body)
+	}
+}
+
+func TestTemplateWatch(t *testing.T) {
+	glob := writeTemplates(t, map[string]string{
+		"page.tmpl": `first`,
+	})
+	path := filepath.Join(filepath.Dir(glob), "page.tmpl")
+	html := LoadTemplates(glob)
+	html.Debug = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	go html.Watch(ctx, time.Millisecond)
+
+	// render concurrently with reloading, to catch data races
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				html.Render(httptest.NewRecorder(), http.StatusOK, "page.tmpl", nil)
+			}
+		}()
+	}
+
+	// render returns body of the page, once it is equal to want or the
+	// deadline is reached
+	render := func(want string) string {
+		var body string
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			w := httptest.NewRecorder()
+			html.Render(w, http.StatusOK, "page.tmpl", nil)
+			if body = w.Body.String(); strings.Contains(body, want) {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return body
+	}
+	// modification time resolution might be too low to notice a change
+	mtime := time.Now()
+	update := func(content string) {
+		mtime = mtime.Add(time.Second)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("cannot write template: %s", err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("cannot change modification time: %s", err)
+		}
+	}
+
+	update(`second`)
+	if body := render("second"); body != "second" {
+		t.Fatalf("template not reloaded: %q", body)
+	}
+
+	update(`{{if}}`)
+	if body := render("missing value for if"); !strings.Contains(body, "missing value for if") {
+		t.Fatalf("parse error not rendered: %q", body)
+	}
+	if html.Err() == nil {
+		t.Fatal("parse error not returned")
+	}
+
+	update(`third`)
+	if body := render("third"); body != "third" {
+		t.Fatalf("template not reloaded: %q", body)
+	}
+	if err := html.Err(); err != nil {
+		t.Fatalf("parse error not cleared: %s", err)
+	}
+}
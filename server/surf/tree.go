@@ -0,0 +1,175 @@
+package surf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// node is a single path segment of the routing tree. Request path is split
+// into segments by slash and every segment is matched by a single node.
+//
+// Children are tried in a fixed order: static segment first, then segments
+// with a regular expression in the order of registration and a <name>
+// placeholder last. If the following segments do not match, next child is
+// tried. That makes matching independent of the number of routes and
+// deterministic, no matter in which order routes were registered.
+type node struct {
+	static   map[string]*node
+	patterns []*patternNode
+	param    *node
+
+	// endpoints is the list of routes ending at this node
+	endpoints []*endpoint
+	// mounts is the list of mount prefixes ending at this node
+	mounts []*endpoint
+}
+
+// patternNode is a segment defined by a regular expression, either using
+// <name:regexp> notation or containing placeholder next to a static text.
+type patternNode struct {
+	raw string
+	rx  *regexp.Regexp
+	*node
+}
+
+// insert returns node for given route segments, creating all missing nodes.
+func (n *node) insert(segments []string) *node {
+	for _, seg := range segments {
+		n = n.child(seg)
+	}
+	return n
+}
+
+func (n *node) child(seg string) *node {
+	switch {
+	case placeholderOnlyRx.MatchString(seg):
+		if n.param == nil {
+			n.param = &node{}
+		}
+		return n.param
+	case !placeholderRx.MatchString(seg) && regexp.QuoteMeta(seg) == seg:
+		if n.static == nil {
+			n.static = make(map[string]*node)
+		}
+		child, ok := n.static[seg]
+		if !ok {
+			child = &node{}
+			n.static[seg] = child
+		}
+		return child
+	default:
+		for _, p := range n.patterns {
+			if p.raw == seg {
+				return p.node
+			}
+		}
+		p := &patternNode{raw: seg, rx: compileSegment(seg), node: &node{}}
+		n.patterns = append(n.patterns, p)
+		return p.node
+	}
+}
+
+// placeholderOnlyRx matches segment that is a single <name> placeholder
+// without a regular expression.
+var placeholderOnlyRx = regexp.MustCompile(`^<[^:>]*>$`)
+
+// compileSegment returns regular expression matching whole path segment.
+func compileSegment(seg string) *regexp.Regexp {
+	raw := placeholderRx.ReplaceAllStringFunc(seg, func(s string) string {
+		s = s[1 : len(s)-1]
+		// every <name> can be optionally contain separate regexp
+		// definition using notation <name:regexp>
+		chunks := strings.SplitN(s, ":", 2)
+		if len(chunks) == 1 {
+			return `([^/]+)`
+		}
+		return `(` + chunks[1] + `)`
+	})
+	rx, err := regexp.Compile(`^(?:` + raw + `)$`)
+	if err != nil {
+		panic(fmt.Sprintf("invalid routing segment %q: %s", seg, err))
+	}
+	return rx
+}
+
+// splitPath returns path segments. Leading slash is ignored, trailing slash
+// results in an empty last segment.
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// lookup is the result of the tree search.
+type lookup struct {
+	endpoint *endpoint
+	args     []string
+	// rest is the path left after mount prefix
+	rest string
+	// pathMatch is true if any route matched the path, but not the
+	// method
+	pathMatch bool
+	// allowed are methods of all routes matching the path
+	allowed map[string]struct{}
+}
+
+// find searches the tree for endpoint matching given path segments and
+// method. Empty method matches any endpoint. Routes ending at the deepest
+// node take precedence over mount prefixes.
+func (n *node) find(segments []string, method string, args []string, res *lookup) bool {
+	if len(segments) == 0 {
+		for _, e := range n.endpoints {
+			if e.allows(method) {
+				res.endpoint = e
+				res.args = args
+				return true
+			}
+		}
+		for _, e := range n.endpoints {
+			res.pathMatch = true
+			if res.allowed == nil {
+				res.allowed = make(map[string]struct{})
+			}
+			for m := range e.methods {
+				res.allowed[m] = struct{}{}
+			}
+		}
+	} else {
+		seg, next := segments[0], segments[1:]
+		if child, ok := n.static[seg]; ok {
+			if child.find(next, method, args, res) {
+				return true
+			}
+		}
+		for _, p := range n.patterns {
+			match := p.rx.FindStringSubmatch(seg)
+			if match == nil {
+				continue
+			}
+			// limit capacity, so that sibling branches do not
+			// override each other arguments
+			if p.find(next, method, append(args[:len(args):len(args)], match[1:]...), res) {
+				return true
+			}
+		}
+		if n.param != nil && seg != "" {
+			if n.param.find(next, method, append(args[:len(args):len(args)], seg), res) {
+				return true
+			}
+		}
+	}
+
+	if len(n.mounts) == 0 {
+		return false
+	}
+	rest := "/" + strings.Join(segments, "/")
+	for _, m := range n.mounts {
+		if m.matcher != nil && !m.matcher.Match(rest) {
+			continue
+		}
+		res.endpoint = m
+		res.args = args
+		res.rest = rest
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package surf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// Reverser is implemented by handlers that can build URL of their named
+// routes (ie. Router).
+type Reverser interface {
+	URL(name string, args ...string) (string, error)
+}
+
+var _ Reverser = (*Router)(nil)
+
+// ErrNoRoute is returned when building URL of a route that does not exist.
+var ErrNoRoute = errors.New("no route")
+
+// URL returns path of the route with given name. Arguments are used in order
+// to fill <name> placeholders and are escaped. Routes of all mounted
+// reversers are searched as well, with mount prefix arguments going first.
+//
+// An error is returned if route does not exist or given arguments do not
+// match the route definition.
+func (rt *Router) URL(name string, args ...string) (string, error) {
+	if e, ok := rt.names[name]; ok {
+		path, err := buildPath(e.route, args)
+		if err != nil {
+			return "", fmt.Errorf("route %q: %s", name, err)
+		}
+		if !e.path.MatchString(path) {
+			return "", fmt.Errorf("route %q: %q does not match %q", name, path, e.route)
+		}
+		return path, nil
+	}
+
+	for _, e := range rt.endpoints {
+		if !e.mount || e.reverser == nil {
+			continue
+		}
+		n := len(placeholderRx.FindAllStringIndex(e.route, -1))
+		if len(args) < n {
+			continue
+		}
+		path, err := e.reverser.URL(name, args[n:]...)
+		if errors.Is(err, ErrNoRoute) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		prefix, err := buildPath(e.route, args[:n])
+		if err != nil {
+			return "", fmt.Errorf("route %q: %s", name, err)
+		}
+		return prefix + path, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrNoRoute, name)
+}
+
+// ReverseURL returns path of the named route, using the outermost router
+// that is serving request of given context.
+func ReverseURL(ctx context.Context, name string, args ...string) (string, error) {
+	info, ok := ctx.Value(routeInfoKey).(*routeInfo)
+	if !ok {
+		return "", fmt.Errorf("%w: %q, no router in context", ErrNoRoute, name)
+	}
+	return info.root.URL(name, args...)
+}
+
+var placeholderRx = regexp.MustCompile(`\<.*?\>`)
+
+// buildPath returns routing path with all placeholders replaced by given
+// arguments.
+func buildPath(route string, args []string) (string, error) {
+	if n := len(placeholderRx.FindAllStringIndex(route, -1)); n != len(args) {
+		return "", fmt.Errorf("want %d arguments, got %d", n, len(args))
+	}
+	i := 0
+	path := placeholderRx.ReplaceAllStringFunc(route, func(string) string {
+		arg := url.PathEscape(args[i])
+		i++
+		return arg
+	})
+	return path, nil
+}